@@ -8,5 +8,32 @@ function defineConfig(config) {
   return config;
 }
 
-module.exports = { defineConfig };
+/**
+ * Helper function to define a preset that other configs extend
+ * @param {object} config - Partial OpenCore configuration object
+ * @returns {object} The same configuration object
+ */
+function definePreset(config) {
+  return config;
+}
+
+/**
+ * Helper function to define an OpenCore workspace (opencore.workspace.ts)
+ * @param {object} workspace - OpenCore workspace object
+ * @returns {object} The same workspace object
+ */
+function defineWorkspace(workspace) {
+  return workspace;
+}
+
+/**
+ * Helper function to define a colocated resource config (opencore.resource.ts)
+ * @param {object} resource - Options of the resource in the same directory
+ * @returns {object} The same resource object
+ */
+function defineResource(resource) {
+  return resource;
+}
+
+module.exports = { defineConfig, definePreset, defineWorkspace, defineResource };
 
@@ -348,6 +348,12 @@ export interface ResourceBuildConfig {
 
   /** Override dependency resolution for this resource. */
   dependencyResolution?: DependencyResolutionConfig;
+
+  /** Override size budgets for this resource, field by field. */
+  budgets?: BudgetsConfig;
+
+  /** Log level for this resource. Overrides build.logLevel. */
+  logLevel?: LogLevel;
 }
 
 
@@ -667,6 +673,24 @@ export interface DependencyResolutionConfig {
   cache?: boolean;
 }
 
+/**
+ * Maximum output size: a byte count, a size string such as `'250 KB'` or
+ * `'1.5 MB'` (1 KB = 1024 bytes), or an object. A soft budget only warns.
+ */
+export type SizeBudget = number | string | { max: number | string; soft?: boolean };
+
+/** Size budgets for built outputs. Exceeding a hard budget fails the build. */
+export interface BudgetsConfig {
+  /** Budget for the built server bundle (server.js). */
+  server?: SizeBudget;
+
+  /** Budget for the built client bundle (client.js), downloaded by every player. */
+  client?: SizeBudget;
+
+  /** Budget for the whole built `ui/` folder. */
+  ui?: SizeBudget;
+}
+
 /**
  * Global build configuration.
  * These settings apply to all resources unless overridden.
@@ -713,6 +737,21 @@ export interface BuildConfig {
    */
   logLevel?: LogLevel;
 
+  /**
+   * Target for both sides.
+   * @deprecated Use `server.target` and `client.target`.
+   */
+  target?: string;
+
+  /**
+   * Server-only binaries copied next to the core's server.js when
+   * `core.build` is not set. Prefer `core.build.serverBinaries`.
+   */
+  serverBinaries?: string[];
+
+  /** Platform selector for `serverBinaries`, see `core.build.serverBinaryPlatform`. */
+  serverBinaryPlatform?: 'win32' | 'linux' | 'darwin' | string;
+
   /**
    * Whether to minify the output code.
    * Reduces file size but makes debugging harder.
@@ -743,6 +782,54 @@ export interface BuildConfig {
    */
   maxWorkers?: number;
 
+  /**
+   * Stop starting new tasks after the first failure. By default the build
+   * keeps going and only skips the resources that depend on a failed one.
+   * Override for a single run with `--fail-fast` or `--keep-going`.
+   * @default false
+   */
+  failFast?: boolean;
+
+  /**
+   * Whether to restore unchanged resources from the incremental build cache
+   * in `.opencore/cache` instead of rebuilding them.
+   * Disable for a single run with `opencore build --no-cache`.
+   * @default true
+   */
+  cache?: boolean;
+
+  /**
+   * Whether to build on a pool of long-lived Node workers that keep esbuild
+   * warm between builds, instead of starting a node process per task.
+   * Resources with a custom compiler always run one-shot.
+   * Disable for a single run with `opencore build --no-daemon`.
+   * @default true
+   */
+  daemon?: boolean;
+
+  /**
+   * Size budgets for every resource. Explicit resources and `core.build`
+   * can override them.
+   *
+   * @example
+   * ```typescript
+   * budgets: {
+   *   client: '300 KB',
+   *   server: '2 MB',
+   *   ui: { max: '1 MB', soft: true },
+   * }
+   * ```
+   */
+  budgets?: BudgetsConfig;
+
+  /**
+   * Write the esbuild metafile of every server/client bundle to
+   * `.opencore/meta/<resource>.<side>.json` for `opencore analyze`.
+   * Enable for a single run with `opencore build --metafile`.
+   * @default false
+   */
+  metafile?: boolean;
+
   /**
    * Controls how server.external runtime dependencies are made available in built resources.
    *
@@ -793,12 +880,15 @@ export interface BuildConfig {
   client?: SideBuildConfig;
 
   /**
-   * Per-environment build overrides. The active environment is selected via the
-   * `--environment` CLI flag, the `OPENCORE_ENVIRONMENT` env var, or this field.
+   * Per-environment overrides. The active environment is selected via the
+   * `--env` CLI flag, the `OPENCORE_ENVIRONMENT` env var, or this field.
    *
-   * When a build runs, the matching environment's options are merged over the
-   * global build config. The environment file at
-   * `environments/environment.<name>.ts` is aliased to `@opencore/environment`.
+   * The matching environment's options are merged over the rest of the
+   * config; `OPENCORE_TXADMIN_*` env vars and CLI flags still take precedence.
+   * `opencore build --env staging,production` builds each listed environment
+   * into `<outDir>/<env>`.
+   * The environment file at `environments/environment.<name>.ts` is aliased to
+   * `@opencore/environment`.
    *
    * @example
    * ```typescript
@@ -814,6 +904,9 @@ export interface BuildConfig {
    *     fileReplacements: [
    *       { replace: './src/config/api.config.ts', with: './src/config/api.config.prod.ts' },
    *     ],
+   *     destination: '/srv/fxserver/resources',
+   *     server: { target: 'node22' },
+   *     resources: { devtools: false },
    *   },
    * }
    * ```
@@ -821,7 +914,7 @@ export interface BuildConfig {
   environments?: Record<string, EnvironmentOverride>;
 
   /**
-   * The default environment to use when no `--environment` flag is provided.
+   * The default environment to use when no `--env` flag is provided.
    * Can also be set via the `OPENCORE_ENVIRONMENT` environment variable.
    * @default 'development'
    */
@@ -829,7 +922,8 @@ export interface BuildConfig {
 }
 
 /**
- * Per-environment build overrides applied when that environment is active.
+ * Per-environment overrides applied when that environment is active. Unset
+ * options keep the value from the rest of the config.
  */
 export interface EnvironmentOverride {
   /** Override global minify setting for this environment. */
@@ -843,6 +937,24 @@ export interface EnvironmentOverride {
    * Useful for swapping config files or mocks without touching source.
    */
   fileReplacements?: FileReplacement[];
+  /** Override the build output directory. */
+  outDir?: string;
+  /** Override the deployment destination. An empty string turns deployment off. */
+  destination?: string;
+  /** Merged over `build.server`: only the options set here change. */
+  server?: SideBuildConfig;
+  /** Merged over `build.client`: only the options set here change. */
+  client?: SideBuildConfig;
+  /** Merged over `build.dependencyResolution`. */
+  dependencyResolution?: DependencyResolutionConfig;
+  /**
+   * Enable or disable resources by name or glob, e.g. `{ 'devtools': false }`
+   * to leave a dev-only resource out of production. An exact name wins over
+   * globs; among globs, disabling wins. The core resource cannot be disabled.
+   */
+  resources?: Record<string, boolean>;
+  /** Merged over `dev`, e.g. to use another restart mode or txAdmin server. */
+  dev?: DevConfig;
 }
 
 /**
@@ -959,6 +1071,14 @@ export interface OpenCoreAdapterConfig {
  * ```
  */
 export interface OpenCoreConfig {
+  /**
+   * Presets this config extends: config files relative to this one, or
+   * packages that export a config. They are merged in order and this config
+   * is merged last; see "Presets" in docs/configuration.md for the rules.
+   * @example ['@ourteam/opencore-preset', './base.config.ts']
+   */
+  extends?: string[];
+
   /**
    * Project name.
    * Used for identification and logging.
@@ -966,9 +1086,16 @@ export interface OpenCoreConfig {
    */
   name: string;
 
+  /**
+   * Folder resources are built into before they are deployed.
+   * @default './build'
+   */
+  outDir?: string;
+
   /**
    * Deployment destination path.
-   * **Required**. Compiled resources will be output directly to this path.
+   * **Required**. Resources are built into `outDir` and then deployed here,
+   * one resource at a time through a staging folder.
    * Typically points to your FiveM server's resources folder.
    * @example 'C:/FXServer/server-data/resources/[my-server]'
    */
@@ -993,8 +1120,11 @@ export interface OpenCoreConfig {
   standalones?: StandaloneConfig;
 
   /**
-   * OpenCore modules to use.
-   * @example ['@open-core/identity']
+   * Directories of TypeScript code shared between resources. Resources
+   * import a module by its directory name, e.g. `@modules/inventory-types`,
+   * and it is bundled into each resource that imports it. Map
+   * `"@modules/*"` in the `paths` of tsconfig.json so the imports type-check.
+   * @example ['./modules/inventory-types']
    */
   modules?: string[];
 
@@ -1008,12 +1138,36 @@ export interface OpenCoreConfig {
    */
   build?: BuildConfig;
 
+  /**
+   * Deployment settings.
+   */
+  deploy?: DeployConfig;
+
   /**
    * Development mode configuration.
    */
   dev?: DevConfig;
 }
 
+/**
+ * Deployment settings.
+ *
+ * @example
+ * ```typescript
+ * deploy: {
+ *   keep: 5,
+ * }
+ * ```
+ */
+export interface DeployConfig {
+  /**
+   * Previous versions of each resource kept in the destination for
+   * `opencore deploy rollback`, including `.<name>.prev`.
+   * @default 3
+   */
+  keep?: number;
+}
+
 /**
  * Development mode settings.
  *
@@ -1170,3 +1324,84 @@ export interface DevProcessConfig {
  * ```
  */
 export function defineConfig(config: OpenCoreConfig): OpenCoreConfig;
+
+/**
+ * A shared config that projects list in `extends`. Every option is optional;
+ * the project fills in what the preset leaves out.
+ */
+export type OpenCorePreset = Partial<OpenCoreConfig>;
+
+/**
+ * Define a preset for other configs to extend.
+ *
+ * @example
+ * ```typescript
+ * // base.config.ts
+ * import { definePreset } from '@open-core/cli'
+ *
+ * export default definePreset({
+ *   build: { minify: true, sourceMaps: false },
+ *   dev: { bridge: { port: 3847 } },
+ * })
+ * ```
+ */
+export function definePreset(config: OpenCorePreset): OpenCorePreset;
+
+/**
+ * Workspace configuration (opencore.workspace.ts at the repository root).
+ * `opencore build`, `opencore doctor` and `opencore dev` run for every
+ * project, or for those selected with `--project`.
+ */
+export interface OpenCoreWorkspace {
+  /**
+   * Project directories relative to the workspace root, each with its own
+   * opencore.config.ts. Globs match every directory with a config.
+   * @example ['./servers/fivem', './servers/redm']
+   */
+  projects: string[];
+
+  /**
+   * Size of the build worker pool the projects share.
+   * @default the first project's build.maxWorkers
+   */
+  maxWorkers?: number;
+}
+
+/**
+ * Define an OpenCore workspace.
+ *
+ * @example
+ * ```typescript
+ * // opencore.workspace.ts
+ * import { defineWorkspace } from '@open-core/cli'
+ *
+ * export default defineWorkspace({
+ *   projects: ['./servers/*'],
+ * })
+ * ```
+ */
+export function defineWorkspace(workspace: OpenCoreWorkspace): OpenCoreWorkspace;
+
+/**
+ * Resource configuration colocated in a resource directory
+ * (opencore.resource.ts or opencore.resource.json). It takes the options of
+ * an explicit resource; the path is the directory of the file, and
+ * customCompiler, views.path and entryPoints are relative to it. An entry for
+ * the same directory in opencore.config.ts is merged over it.
+ */
+export type OpenCoreResource = Omit<ExplicitResource, 'path'>;
+
+/**
+ * Define the configuration of the resource in this directory.
+ *
+ * @example
+ * ```typescript
+ * // resources/admin/opencore.resource.ts
+ * import { defineResource } from '@open-core/cli'
+ *
+ * export default defineResource({
+ *   build: { server: { external: ['typeorm'] }, client: false },
+ * })
+ * ```
+ */
+export function defineResource(resource: OpenCoreResource): OpenCoreResource;
@@ -4,8 +4,8 @@ const { getBinaryPath } = require('./binary');
 const { spawn } = require('child_process');
 
 // Export config helper for opencore.config.ts
-const { defineConfig } = require('./config-helper');
-module.exports = { defineConfig };
+const { defineConfig, definePreset, defineWorkspace, defineResource } = require('./config-helper');
+module.exports = { defineConfig, definePreset, defineWorkspace, defineResource };
 
 // Only run the binary if this is being executed directly
 if (require.main === module) {
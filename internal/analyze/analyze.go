@@ -0,0 +1,342 @@
+// Package analyze reads the esbuild metafiles written by
+// `opencore build --metafile` and answers questions about bundle contents:
+// which modules are heaviest, which packages are bundled by several
+// resources, and why a package ended up in a bundle.
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Metafile is the subset of the esbuild metafile format used by the analyzer.
+type Metafile struct {
+	Inputs  map[string]MetaInput  `json:"inputs"`
+	Outputs map[string]MetaOutput `json:"outputs"`
+}
+
+type MetaInput struct {
+	Bytes   int64        `json:"bytes"`
+	Imports []MetaImport `json:"imports"`
+}
+
+type MetaImport struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	External bool   `json:"external,omitempty"`
+}
+
+type MetaOutput struct {
+	Bytes      int64                      `json:"bytes"`
+	EntryPoint string                     `json:"entryPoint,omitempty"`
+	Inputs     map[string]MetaOutputInput `json:"inputs"`
+}
+
+type MetaOutputInput struct {
+	BytesInOutput int64 `json:"bytesInOutput"`
+}
+
+// Bundle is one side of a built resource.
+type Bundle struct {
+	Resource string
+	Side     string // server or client
+	Meta     Metafile
+}
+
+// Name identifies the bundle, e.g. "chat (client)".
+func (b Bundle) Name() string {
+	return fmt.Sprintf("%s (%s)", b.Resource, b.Side)
+}
+
+// Bytes returns the size of the bundle's outputs.
+func (b Bundle) Bytes() int64 {
+	var total int64
+	for name, output := range b.Meta.Outputs {
+		if strings.HasSuffix(name, ".map") {
+			continue
+		}
+		total += output.Bytes
+	}
+	return total
+}
+
+// inputBytes returns how many bytes each input contributes to the outputs.
+func (b Bundle) inputBytes() map[string]int64 {
+	bytes := make(map[string]int64)
+	for _, output := range b.Meta.Outputs {
+		for path, input := range output.Inputs {
+			bytes[path] += input.BytesInOutput
+		}
+	}
+	return bytes
+}
+
+// LoadBundles reads every <resource>.<side>.json metafile in dir. When
+// resource is not empty only that resource's bundles are returned.
+func LoadBundles(dir string, resource string) ([]Bundle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var bundles []Bundle
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		dot := strings.LastIndex(name, ".")
+		if dot <= 0 {
+			continue
+		}
+		bundle := Bundle{Resource: name[:dot], Side: name[dot+1:]}
+		if resource != "" && bundle.Resource != resource {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &bundle.Meta); err != nil {
+			return nil, fmt.Errorf("failed to parse metafile %s: %w", entry.Name(), err)
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	sort.Slice(bundles, func(i, j int) bool {
+		if bundles[i].Resource != bundles[j].Resource {
+			return bundles[i].Resource < bundles[j].Resource
+		}
+		return bundles[i].Side > bundles[j].Side
+	})
+	return bundles, nil
+}
+
+// PackageName returns the npm package an input path belongs to, or "" for
+// project sources. Nested node_modules (including pnpm's .pnpm store) resolve
+// to the innermost package.
+func PackageName(inputPath string) string {
+	p := filepath.ToSlash(inputPath)
+	idx := strings.LastIndex(p, "node_modules/")
+	if idx < 0 {
+		return ""
+	}
+	parts := strings.Split(p[idx+len("node_modules/"):], "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return ""
+	}
+	if strings.HasPrefix(parts[0], "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// Module is an input file and the bytes it contributes to a bundle.
+type Module struct {
+	Bundle  string
+	Path    string
+	Package string
+	Bytes   int64
+}
+
+// HeaviestModules returns the inputs contributing the most bytes across all
+// bundles. A limit of 0 returns every module.
+func HeaviestModules(bundles []Bundle, limit int) []Module {
+	var modules []Module
+	for _, bundle := range bundles {
+		for path, bytes := range bundle.inputBytes() {
+			if bytes == 0 {
+				continue
+			}
+			modules = append(modules, Module{
+				Bundle:  bundle.Name(),
+				Path:    path,
+				Package: PackageName(path),
+				Bytes:   bytes,
+			})
+		}
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Bytes != modules[j].Bytes {
+			return modules[i].Bytes > modules[j].Bytes
+		}
+		if modules[i].Bundle != modules[j].Bundle {
+			return modules[i].Bundle < modules[j].Bundle
+		}
+		return modules[i].Path < modules[j].Path
+	})
+	if limit > 0 && len(modules) > limit {
+		modules = modules[:limit]
+	}
+	return modules
+}
+
+// PackageUsage is an npm package and the bundles that include it.
+type PackageUsage struct {
+	Name    string
+	Bytes   int64            // total across bundles
+	Bundles map[string]int64 // bundle name -> bytes
+}
+
+// Resources returns the distinct resources that bundle the package.
+func (p PackageUsage) Resources() []string {
+	seen := make(map[string]bool)
+	var resources []string
+	for name := range p.Bundles {
+		resource := name
+		if i := strings.LastIndex(name, " ("); i >= 0 {
+			resource = name[:i]
+		}
+		if !seen[resource] {
+			seen[resource] = true
+			resources = append(resources, resource)
+		}
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// BundleNames returns the bundles including the package, largest first.
+func (p PackageUsage) BundleNames() []string {
+	names := make([]string, 0, len(p.Bundles))
+	for name := range p.Bundles {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if p.Bundles[names[i]] != p.Bundles[names[j]] {
+			return p.Bundles[names[i]] > p.Bundles[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Duplicated reports whether more than one resource bundles the package.
+func (p PackageUsage) Duplicated() bool {
+	return len(p.Resources()) > 1
+}
+
+// Packages returns every bundled npm package, largest first.
+func Packages(bundles []Bundle) []PackageUsage {
+	byName := make(map[string]*PackageUsage)
+	for _, bundle := range bundles {
+		for path, bytes := range bundle.inputBytes() {
+			name := PackageName(path)
+			if name == "" || bytes == 0 {
+				continue
+			}
+			usage := byName[name]
+			if usage == nil {
+				usage = &PackageUsage{Name: name, Bundles: make(map[string]int64)}
+				byName[name] = usage
+			}
+			usage.Bytes += bytes
+			usage.Bundles[bundle.Name()] += bytes
+		}
+	}
+
+	packages := make([]PackageUsage, 0, len(byName))
+	for _, usage := range byName {
+		packages = append(packages, *usage)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Bytes != packages[j].Bytes {
+			return packages[i].Bytes > packages[j].Bytes
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
+
+// Duplicates returns the packages bundled by more than one resource, ordered
+// by the bytes they add in total.
+func Duplicates(bundles []Bundle) []PackageUsage {
+	var duplicates []PackageUsage
+	for _, usage := range Packages(bundles) {
+		if usage.Duplicated() {
+			duplicates = append(duplicates, usage)
+		}
+	}
+	return duplicates
+}
+
+// Chain is an import path from a bundle's entry point to a module.
+type Chain struct {
+	Bundle string
+	Path   []string
+}
+
+// WhyIncluded returns, for every bundle that includes pkg, the shortest
+// import chain from the entry point to the first module of that package.
+func WhyIncluded(bundles []Bundle, pkg string) []Chain {
+	var chains []Chain
+	for _, bundle := range bundles {
+		if path := bundle.importChain(pkg); path != nil {
+			chains = append(chains, Chain{Bundle: bundle.Name(), Path: path})
+		}
+	}
+	return chains
+}
+
+func (b Bundle) importChain(pkg string) []string {
+	var entries []string
+	for _, output := range b.Meta.Outputs {
+		if output.EntryPoint != "" {
+			entries = append(entries, output.EntryPoint)
+		}
+	}
+	sort.Strings(entries)
+
+	parent := make(map[string]string)
+	visited := make(map[string]bool)
+	queue := append([]string{}, entries...)
+	for _, entry := range entries {
+		visited[entry] = true
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if PackageName(current) == pkg {
+			var path []string
+			for node := current; ; node = parent[node] {
+				path = append([]string{node}, path...)
+				if _, ok := parent[node]; !ok {
+					break
+				}
+			}
+			return path
+		}
+		for _, imp := range b.Meta.Inputs[current].Imports {
+			if imp.External || visited[imp.Path] {
+				continue
+			}
+			if _, ok := b.Meta.Inputs[imp.Path]; !ok {
+				continue
+			}
+			visited[imp.Path] = true
+			parent[imp.Path] = current
+			queue = append(queue, imp.Path)
+		}
+	}
+	return nil
+}
+
+// FormatSize formats bytes like the build summary does.
+func FormatSize(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	} else if bytes < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	}
+	return fmt.Sprintf("%.2f MB", float64(bytes)/(1024*1024))
+}
@@ -0,0 +1,159 @@
+package analyze
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const chatClientMeta = `{
+  "inputs": {
+    "resources/chat/src/client.ts": {"bytes": 400, "imports": [{"path": "resources/chat/src/ui.ts", "kind": "import-statement"}]},
+    "resources/chat/src/ui.ts": {"bytes": 300, "imports": [{"path": "node_modules/.pnpm/lodash@4.17.21/node_modules/lodash/lodash.js", "kind": "import-statement"}]},
+    "node_modules/.pnpm/lodash@4.17.21/node_modules/lodash/lodash.js": {"bytes": 70000, "imports": []},
+    "node_modules/@scope/tiny/index.js": {"bytes": 50, "imports": []}
+  },
+  "outputs": {
+    "build/chat/client.js": {
+      "bytes": 25000,
+      "entryPoint": "resources/chat/src/client.ts",
+      "inputs": {
+        "resources/chat/src/client.ts": {"bytesInOutput": 200},
+        "resources/chat/src/ui.ts": {"bytesInOutput": 150},
+        "node_modules/.pnpm/lodash@4.17.21/node_modules/lodash/lodash.js": {"bytesInOutput": 24000},
+        "node_modules/@scope/tiny/index.js": {"bytesInOutput": 0}
+      }
+    },
+    "build/chat/client.js.map": {"bytes": 90000, "inputs": {}}
+  }
+}`
+
+const adminServerMeta = `{
+  "inputs": {
+    "resources/admin/src/server.ts": {"bytes": 100, "imports": [{"path": "node_modules/lodash/lodash.js", "kind": "require-call"}, {"path": "typeorm", "kind": "require-call", "external": true}]},
+    "node_modules/lodash/lodash.js": {"bytes": 70000, "imports": []}
+  },
+  "outputs": {
+    "build/admin/server.js": {
+      "bytes": 30000,
+      "entryPoint": "resources/admin/src/server.ts",
+      "inputs": {
+        "resources/admin/src/server.ts": {"bytesInOutput": 80},
+        "node_modules/lodash/lodash.js": {"bytesInOutput": 29000}
+      }
+    }
+  }
+}`
+
+func writeMetafiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"chat.client.json":  chatClientMeta,
+		"admin.server.json": adminServerMeta,
+		"notes.txt":         "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestPackageName(t *testing.T) {
+	cases := map[string]string{
+		"resources/chat/src/client.ts":                                      "",
+		"node_modules/lodash/lodash.js":                                     "lodash",
+		"node_modules/@open-core/framework/dist/server.js":                  "@open-core/framework",
+		"node_modules/.pnpm/lodash@4.17.21/node_modules/lodash/lodash.js":   "lodash",
+		"node_modules/a/node_modules/b/index.js":                            "b",
+		"../../node_modules/.pnpm/@scope+pkg@1.0.0/node_modules/@scope/pkg": "@scope/pkg",
+	}
+	for input, expected := range cases {
+		if got := PackageName(input); got != expected {
+			t.Errorf("PackageName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestLoadBundles(t *testing.T) {
+	dir := writeMetafiles(t)
+
+	bundles, err := LoadBundles(dir, "")
+	if err != nil {
+		t.Fatalf("LoadBundles failed: %v", err)
+	}
+	if len(bundles) != 2 || bundles[0].Name() != "admin (server)" || bundles[1].Name() != "chat (client)" {
+		t.Fatalf("unexpected bundles: %+v", bundles)
+	}
+	if bundles[1].Bytes() != 25000 {
+		t.Fatalf("expected source maps to be excluded from bundle size, got %d", bundles[1].Bytes())
+	}
+
+	filtered, _ := LoadBundles(dir, "chat")
+	if len(filtered) != 1 || filtered[0].Resource != "chat" {
+		t.Fatalf("unexpected filtered bundles: %+v", filtered)
+	}
+
+	missing, err := LoadBundles(filepath.Join(dir, "missing"), "")
+	if err != nil || missing != nil {
+		t.Fatalf("expected missing directory to yield no bundles, got %v, %v", missing, err)
+	}
+}
+
+func TestAnalyzeBundles(t *testing.T) {
+	bundles, err := LoadBundles(writeMetafiles(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules := HeaviestModules(bundles, 2)
+	if len(modules) != 2 || modules[0].Bundle != "admin (server)" || modules[0].Package != "lodash" || modules[1].Bytes != 24000 {
+		t.Fatalf("unexpected heaviest modules: %+v", modules)
+	}
+
+	packages := Packages(bundles)
+	if len(packages) != 1 {
+		t.Fatalf("expected tree-shaken packages to be ignored, got %+v", packages)
+	}
+	duplicates := Duplicates(bundles)
+	if len(duplicates) != 1 || duplicates[0].Name != "lodash" || duplicates[0].Bytes != 53000 {
+		t.Fatalf("unexpected duplicates: %+v", duplicates)
+	}
+	if names := duplicates[0].BundleNames(); strings.Join(names, ",") != "admin (server),chat (client)" {
+		t.Fatalf("unexpected bundle names: %v", names)
+	}
+
+	chains := WhyIncluded(bundles, "lodash")
+	if len(chains) != 2 {
+		t.Fatalf("expected a chain per bundle, got %+v", chains)
+	}
+	expected := "resources/chat/src/client.ts,resources/chat/src/ui.ts,node_modules/.pnpm/lodash@4.17.21/node_modules/lodash/lodash.js"
+	if got := strings.Join(chains[1].Path, ","); got != expected {
+		t.Fatalf("unexpected chain: %s", got)
+	}
+	if WhyIncluded(bundles, "typeorm") != nil {
+		t.Fatal("expected external packages to have no chain")
+	}
+}
+
+func TestReportWriteHTML(t *testing.T) {
+	bundles, err := LoadBundles(writeMetafiles(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewReport(bundles, 10).WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"<h2>Duplicate packages</h2>", `class="dup">lodash`, "chat (client)", "resources/chat/src/ui.ts →"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package analyze
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// Report gathers everything shown by `opencore analyze`.
+type Report struct {
+	Bundles    []Bundle
+	Modules    []Module
+	Packages   []PackageUsage
+	Duplicates []PackageUsage
+	Why        map[string][]Chain // package -> import chains, for duplicated packages
+}
+
+// NewReport analyzes bundles, keeping the top modules up to limit.
+func NewReport(bundles []Bundle, limit int) Report {
+	report := Report{
+		Bundles:    bundles,
+		Modules:    HeaviestModules(bundles, limit),
+		Packages:   Packages(bundles),
+		Duplicates: Duplicates(bundles),
+		Why:        make(map[string][]Chain),
+	}
+	for _, dup := range report.Duplicates {
+		report.Why[dup.Name] = WhyIncluded(bundles, dup.Name)
+	}
+	return report
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"size":  FormatSize,
+	"join":  strings.Join,
+	"arrow": func(path []string) string { return strings.Join(path, " → ") },
+	"percent": func(part, total int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(part) / float64(total) * 100
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>OpenCore bundle analysis</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; background: #0f0f14; color: #e5e7eb; margin: 2rem; }
+  h1 { color: #a78bfa; }
+  h2 { color: #f59e0b; margin-top: 2.5rem; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: .35rem .6rem; border-bottom: 1px solid #27272a; font-size: .9rem; }
+  th { color: #9ca3af; font-weight: 600; }
+  td.num { text-align: right; font-variant-numeric: tabular-nums; white-space: nowrap; }
+  .bar { background: #60a5fa; height: .5rem; border-radius: .25rem; }
+  .muted { color: #6b7280; }
+  .dup { color: #f87171; }
+  code { color: #34d399; }
+</style>
+</head>
+<body>
+<h1>OpenCore bundle analysis</h1>
+<p class="muted">Generated {{.Generated}}</p>
+
+<h2>Bundles</h2>
+<table>
+<tr><th>Bundle</th><th class="num">Size</th></tr>
+{{range .Report.Bundles}}<tr><td>{{.Name}}</td><td class="num">{{size .Bytes}}</td></tr>
+{{end}}</table>
+
+<h2>Heaviest modules</h2>
+<table>
+<tr><th>Module</th><th>Bundle</th><th class="num">Size</th><th style="width:20%"></th></tr>
+{{$max := .MaxModule}}{{range .Report.Modules}}<tr><td><code>{{.Path}}</code></td><td>{{.Bundle}}</td><td class="num">{{size .Bytes}}</td><td><div class="bar" style="width:{{printf "%.1f" (percent .Bytes $max)}}%"></div></td></tr>
+{{end}}</table>
+
+<h2>Duplicate packages</h2>
+{{if .Report.Duplicates}}<table>
+<tr><th>Package</th><th>Bundles</th><th class="num">Total</th><th>Why</th></tr>
+{{range .Report.Duplicates}}<tr><td class="dup">{{.Name}}</td><td>{{join .BundleNames ", "}}</td><td class="num">{{size .Bytes}}</td><td>{{range index $.Report.Why .Name}}<div><span class="muted">{{.Bundle}}:</span> {{arrow .Path}}</div>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">No package is bundled by more than one resource.</p>{{end}}
+
+<h2>Packages</h2>
+<table>
+<tr><th>Package</th><th>Bundles</th><th class="num">Total</th></tr>
+{{range .Report.Packages}}<tr><td>{{.Name}}</td><td>{{join .BundleNames ", "}}</td><td class="num">{{size .Bytes}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// WriteHTML renders the report as a self-contained HTML page.
+func (r Report) WriteHTML(w io.Writer) error {
+	var maxModule int64
+	if len(r.Modules) > 0 {
+		maxModule = r.Modules[0].Bytes
+	}
+	return htmlTemplate.Execute(w, struct {
+		Report    Report
+		Generated string
+		MaxModule int64
+	}{r, time.Now().Format(time.RFC1123), maxModule})
+}
@@ -0,0 +1,114 @@
+package builder
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+// BudgetViolation is an output that exceeded its configured size budget.
+type BudgetViolation struct {
+	Resource string `json:"resource"`
+	Output   string `json:"output"` // server.js, client.js or ui/
+	Size     int64  `json:"size"`
+	Max      int64  `json:"max"`
+	Soft     bool   `json:"soft,omitempty"`
+}
+
+func (v BudgetViolation) String() string {
+	return fmt.Sprintf("[%s] %s is %s, budget is %s", v.Resource, v.Output, formatSize(v.Size), formatSize(v.Max))
+}
+
+// resourceBudgets resolves the budgets for a base resource: the global
+// build.budgets overridden by the matching explicit resource or core config.
+func (b *Builder) resourceBudgets(resourceName string) *config.BudgetsConfig {
+	budgets := b.config.Build.Budgets
+	if resourceName == b.config.Core.ResourceName {
+		if b.config.Core.Build != nil {
+			return config.MergeBudgets(budgets, b.config.Core.Build.Budgets)
+		}
+		return budgets
+	}
+
+	explicit := b.config.Resources.Explicit
+	if b.config.Standalones != nil {
+		explicit = append(append([]config.ExplicitResource{}, explicit...), b.config.Standalones.Explicit...)
+	}
+	for _, res := range explicit {
+		name := res.ResourceName
+		if name == "" {
+			name = filepath.Base(res.Path)
+		}
+		if name == resourceName && res.Build != nil {
+			return config.MergeBudgets(budgets, res.Build.Budgets)
+		}
+	}
+	return budgets
+}
+
+// checkBudgets compares the built output sizes against their budgets.
+func (b *Builder) checkBudgets(results []BuildResult) []BudgetViolation {
+	var violations []BudgetViolation
+	check := func(resource, output string, size int64, budget *config.SizeBudget) {
+		if budget == nil || size <= budget.Max {
+			return
+		}
+		violations = append(violations, BudgetViolation{
+			Resource: resource,
+			Output:   output,
+			Size:     size,
+			Max:      budget.Max,
+			Soft:     budget.Soft,
+		})
+	}
+
+	for _, s := range b.getResourceSizes(results) {
+		base := baseResourceName(s.Name)
+		budgets := b.resourceBudgets(base)
+		if budgets == nil {
+			continue
+		}
+		if s.IsViews {
+			check(base, "ui/", s.TotalSize, budgets.UI)
+			continue
+		}
+		layout := b.resourceLayout(s.Name)
+		check(s.Name, layout.ServerOutFile, s.ServerSize, budgets.Server)
+		check(s.Name, layout.ClientOutFile, s.ClientSize, budgets.Client)
+	}
+	return violations
+}
+
+// enforceBudgets reports budget violations and fails the build when any hard
+// budget was exceeded.
+func (b *Builder) enforceBudgets(results []BuildResult, plain bool) error {
+	violations := b.checkBudgets(results)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	hard := 0
+	fmt.Fprintln(b.out)
+	for _, v := range violations {
+		switch {
+		case plain && v.Soft:
+			fmt.Fprintf(b.out, "WARN  budget exceeded: %s\n", v)
+		case plain:
+			fmt.Fprintf(b.out, "FAIL  budget exceeded: %s\n", v)
+		case v.Soft:
+			fmt.Fprintln(b.out, ui.Warning(fmt.Sprintf("Budget exceeded: %s", v)))
+		default:
+			fmt.Fprintln(b.out, ui.Error(fmt.Sprintf("Budget exceeded: %s", v)))
+		}
+		if !v.Soft {
+			hard++
+		}
+	}
+
+	if hard > 0 {
+		return fmt.Errorf("%d size budget(s) exceeded", hard)
+	}
+	return nil
+}
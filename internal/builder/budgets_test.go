@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func TestCheckBudgets(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "build")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "server.js"), strings.Repeat("s", 300))
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "client.js"), strings.Repeat("c", 300))
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "ui", "index.html"), strings.Repeat("u", 300))
+	writeCacheTestFile(t, filepath.Join(outDir, "admin", "client.js"), strings.Repeat("c", 300))
+
+	cfg := &config.Config{
+		OutDir: outDir,
+		Build: config.BuildConfig{
+			Budgets: &config.BudgetsConfig{
+				Server: &config.SizeBudget{Max: 1024},
+				Client: &config.SizeBudget{Max: 256},
+			},
+		},
+		Resources: config.ResourcesConfig{
+			Explicit: []config.ExplicitResource{{
+				Path: "./resources/admin",
+				Build: &config.ResourceBuildConfig{
+					Budgets: &config.BudgetsConfig{Client: &config.SizeBudget{Max: 512}},
+				},
+			}, {
+				Path: "./resources/chat",
+				Build: &config.ResourceBuildConfig{
+					Budgets: &config.BudgetsConfig{UI: &config.SizeBudget{Max: 100, Soft: true}},
+				},
+			}},
+		},
+	}
+	b := New(cfg)
+	results := []BuildResult{
+		{Task: BuildTask{ResourceName: "chat", Type: TypeResource}, Success: true},
+		{Task: BuildTask{ResourceName: "chat/ui", Type: TypeViews, OutDir: filepath.Join(outDir, "chat", "ui")}, Success: true},
+		{Task: BuildTask{ResourceName: "admin", Type: TypeResource}, Success: true},
+	}
+
+	violations := b.checkBudgets(results)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", violations)
+	}
+	if v := violations[0]; v.Resource != "chat" || v.Output != "client.js" || v.Size != 300 || v.Max != 256 || v.Soft {
+		t.Errorf("unexpected client violation: %+v", v)
+	}
+	if v := violations[1]; v.Resource != "chat" || v.Output != "ui/" || !v.Soft {
+		t.Errorf("unexpected ui violation: %+v", v)
+	}
+
+	var out bytes.Buffer
+	b.SetOutput(&out)
+	err := b.enforceBudgets(results, true)
+	if err == nil || !strings.Contains(err.Error(), "1 size budget(s) exceeded") {
+		t.Fatalf("expected hard budget failure, got %v", err)
+	}
+	if !strings.Contains(out.String(), "FAIL  budget exceeded: [chat] client.js is 300 B, budget is 256 B") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "WARN  budget exceeded: [chat] ui/") {
+		t.Errorf("expected soft budget warning, got:\n%s", out.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -25,6 +26,17 @@ type Builder struct {
 	config          *config.Config
 	resourceBuilder *ResourceBuilder
 	deployer        *Deployer
+	cache           *BuildCache
+	out             io.Writer
+	report          ReportOptions
+	baseline        *SizeSnapshot
+	selection       Selection
+	tracer          *Tracer
+	devSourceMaps   bool
+	// validatedSources holds the resource paths whose sources passed
+	// validation and the module paths that passed the type-check, shared by
+	// the builders of a multi-environment build.
+	validatedSources map[string]bool
 }
 
 func normalizedBuildPath(p string) string {
@@ -147,11 +159,135 @@ func buildResourceSideValue(side *config.ResourceBuildSideConfig, base *config.B
 }
 
 func New(cfg *config.Config) *Builder {
-	return &Builder{
-		config:          cfg,
-		resourceBuilder: NewResourceBuilder("."),
-		deployer:        NewDeployer(cfg),
+	b := &Builder{
+		config:           cfg,
+		resourceBuilder:  NewResourceBuilder("."),
+		deployer:         NewDeployer(cfg),
+		out:              os.Stdout,
+		validatedSources: make(map[string]bool),
 	}
+	if cfg.Build.CacheEnabled() {
+		b.cache = projectBuildCache(cfg)
+	}
+	if cfg.Build.DaemonEnabled() {
+		workers := cfg.Build.MaxWorkers
+		if workers == 0 {
+			workers = runtime.NumCPU()
+		}
+		if !cfg.Build.Parallel {
+			workers = 1
+		}
+		b.resourceBuilder.EnableDaemon(workers)
+	}
+	return b
+}
+
+// Close stops the build daemon. A Builder must be closed once it is no
+// longer used.
+func (b *Builder) Close() {
+	b.resourceBuilder.Close()
+}
+
+// ForEnvironment returns a builder for cfg, another environment of the same
+// project (see config.ForEnvironment). It shares b's build daemon, source
+// validation, tracer and output, so only b must be closed. Its build cache
+// entries are kept apart from other environments', except for views, whose
+// outputs do not depend on the environment and are reused between them.
+func (b *Builder) ForEnvironment(cfg *config.Config) *Builder {
+	env := &Builder{
+		config:           cfg,
+		resourceBuilder:  b.resourceBuilder,
+		deployer:         NewDeployer(cfg),
+		out:              b.out,
+		tracer:           b.tracer,
+		devSourceMaps:    b.devSourceMaps,
+		validatedSources: b.validatedSources,
+	}
+	if b.cache != nil && cfg.Build.CacheEnabled() {
+		env.cache = b.cache.forEnvironment(cfg.Build.Environment)
+	}
+	return env
+}
+
+// buildTask builds a single task and records it in the trace.
+func (b *Builder) buildTask(ctx context.Context, task BuildTask) BuildResult {
+	start := time.Now()
+	result := b.buildTaskCached(ctx, task)
+	b.tracer.task(workerFromContext(ctx), start, time.Now(), result)
+	return result
+}
+
+// buildTaskCached builds a single task, restoring its previous outputs from
+// the build cache when none of its inputs changed.
+func (b *Builder) buildTaskCached(ctx context.Context, task BuildTask) BuildResult {
+	// Both a build and a cache hit write the task's metafiles afresh.
+	removeStaleMetafiles(task)
+	build := func() BuildResult {
+		return b.resourceBuilder.BuildWithContext(ctx, task)
+	}
+	if b.cache == nil {
+		return build()
+	}
+
+	start := time.Now()
+	outputs := b.cacheOutputs(task)
+	sources, err := b.cache.sourcesKey(task, b.cacheSkipDirs())
+	if err != nil {
+		return build()
+	}
+	key := inputsKey(sources, b.cache.storedInputs(task))
+
+	if restored, _ := b.cache.Restore(task, key, outputs); restored {
+		return BuildResult{
+			Task:     task,
+			Success:  true,
+			Cached:   true,
+			Duration: time.Since(start),
+		}
+	}
+
+	result := build()
+	if result.Success {
+		// The entry is keyed on the inputs this build read, which may differ
+		// from the ones of the previous build. A failed cache write only
+		// costs a rebuild next time.
+		inputs := externalInputs(task, result.Inputs)
+		_ = b.cache.Store(task, inputsKey(sources, inputs), outputs, inputs)
+	}
+	return result
+}
+
+// SetOutput redirects progress and summary output, e.g. to stderr when a
+// report is written to stdout.
+func (b *Builder) SetOutput(w io.Writer) {
+	b.out = w
+}
+
+// SetReport configures the machine-readable reports written after a build.
+func (b *Builder) SetReport(opts ReportOptions) {
+	b.report = opts
+}
+
+// SetSelection limits the build to the selected tasks. Only the selected
+// resources are cleaned and deployed.
+func (b *Builder) SetSelection(selection Selection) {
+	b.selection = selection
+}
+
+// SetTracer records the build's steps and tasks in tracer.
+func (b *Builder) SetTracer(tracer *Tracer) {
+	b.tracer = tracer
+}
+
+// SetDevSourceMaps makes builds write external source maps next to their
+// bundles when build.sourceMaps is off, so dev mode can remap stack traces.
+func (b *Builder) SetDevSourceMaps(enabled bool) {
+	b.devSourceMaps = enabled
+}
+
+// SetBaseline sets the size snapshot the build summary is compared against.
+func (b *Builder) SetBaseline(snapshot *SizeSnapshot) {
+	b.baseline = snapshot
 }
 
 func (b *Builder) CollectTasks() []BuildTask {
@@ -188,9 +324,18 @@ func (b *Builder) BuildWithOutputContext(ctx context.Context, requestedMode Outp
 	// Cleanup embedded script on exit
 	defer b.resourceBuilder.Cleanup()
 
-	b.applyEnvironmentOverrides()
-
-	if err := b.validateEnvironment(); err != nil {
+	err := b.tracer.Span("validate environment", func() error {
+		// Commands apply the environment before their flags; this only
+		// covers builders used without a command.
+		if err := b.config.ApplyEnvironment(); err != nil {
+			return err
+		}
+		if err := b.validateEnvironment(); err != nil {
+			return err
+		}
+		return b.validateModules()
+	})
+	if err != nil {
 		return err
 	}
 
@@ -201,7 +346,34 @@ func (b *Builder) BuildWithOutputContext(ctx context.Context, requestedMode Outp
 		return fmt.Errorf("no resources to build")
 	}
 
-	if err := b.validateTaskSources(tasks); err != nil {
+	if b.selection.Active() {
+		if err := b.checkSelectiveSnapshot(); err != nil {
+			return err
+		}
+		selected, err := b.selectTasks(tasks)
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			message := fmt.Sprintf("No resources changed since %s, nothing to build", b.selection.ChangedSince)
+			if plain {
+				fmt.Fprintln(b.out, message)
+			} else {
+				fmt.Fprintln(b.out, ui.Muted(message))
+			}
+			return nil
+		}
+		tasks = selected
+	}
+
+	if err := b.tracer.Span("validate sources", func() error { return b.validateTaskSources(tasks) }); err != nil {
+		return err
+	}
+	if err := b.tracer.Span("type-check modules", func() error { return b.typeCheckModules(ctx) }); err != nil {
+		return err
+	}
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
 		return err
 	}
 	sharedOptions, sharedName, err := b.sharedDependencyOptions(tasks)
@@ -211,23 +383,42 @@ func (b *Builder) BuildWithOutputContext(ctx context.Context, requestedMode Outp
 
 	// Clean only the resources we are about to build
 	uniqueResources := make(map[string]struct{})
+	resourceTasks := make(map[string][]BuildTask)
 	for _, task := range tasks {
 		// ResourceName can be "core" or "myresource/ui"
 		baseResource := strings.Split(task.ResourceName, "/")[0]
 		uniqueResources[baseResource] = struct{}{}
+		resourceTasks[baseResource] = append(resourceTasks[baseResource], task)
 	}
 	if sharedName != "" {
 		uniqueResources[sharedName] = struct{}{}
 	}
 
-	for baseResource := range uniqueResources {
-		if err := b.cleanResourceOutputDir(baseResource); err != nil {
-			return fmt.Errorf("failed to clean resource output directory: %w", err)
+	err = b.tracer.Span("clean outputs", func() error {
+		for baseResource := range uniqueResources {
+			var cleanErr error
+			if b.selection.Active() {
+				// A selected views task must not wipe its resource's server output.
+				cleanErr = b.cleanResourceOutputForTasks(baseResource, resourceTasks[baseResource])
+			} else {
+				cleanErr = b.cleanResourceOutputDir(baseResource)
+			}
+			if cleanErr != nil {
+				return fmt.Errorf("failed to clean resource output directory: %w", cleanErr)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	var sharedFailure *BuildResult
 	if sharedOptions != nil {
-		layout := b.resourceLayout(sharedName)
-		if _, err := b.resourceBuilder.GenerateSharedDependencies(ctx, layout.ServerOutDir, *sharedOptions); err != nil {
+		err = b.tracer.Span("generate shared dependencies", func() (err error) {
+			sharedFailure, err = b.generateSharedResource(ctx, graph, *sharedOptions, sharedName, plain)
+			return err
+		})
+		if err != nil {
 			return err
 		}
 	}
@@ -243,15 +434,33 @@ func (b *Builder) BuildWithOutputContext(ctx context.Context, requestedMode Outp
 
 	// Build with parallel or sequential mode
 	var results []BuildResult
+	buildStart := time.Now()
 
-	if b.config.Build.Parallel && len(tasks) > 1 {
-		if mode == OutputModeTUI {
-			results, err = b.buildParallelTUI(ctx, tasks, workers)
+	_ = b.tracer.Span("build tasks", func() error {
+		if b.config.Build.Parallel && len(tasks) > 1 {
+			if mode == OutputModeTUI {
+				results, err = b.buildParallelTUI(ctx, graph, workers)
+			} else {
+				results, err = b.buildParallelPlain(ctx, graph, workers)
+			}
 		} else {
-			results, err = b.buildParallelPlain(ctx, tasks, workers)
+			results, err = b.buildSequential(ctx, graph, plain)
 		}
-	} else {
-		results, err = b.buildSequential(ctx, tasks, plain)
+		return err
+	})
+	if sharedFailure != nil && !errors.Is(err, context.Canceled) {
+		results = append([]BuildResult{*sharedFailure}, results...)
+		err = buildFailureError(results)
+	}
+	if !errors.Is(err, context.Canceled) {
+		b.printDiagnosticsSummary(results, plain)
+	}
+
+	if reportErr := b.writeReports(results, time.Since(buildStart)); reportErr != nil {
+		if err != nil {
+			return errors.Join(err, reportErr)
+		}
+		return reportErr
 	}
 
 	if err != nil {
@@ -261,24 +470,49 @@ func (b *Builder) BuildWithOutputContext(ctx context.Context, requestedMode Outp
 		return err
 	}
 
-	if err := b.writeRuntimeArtifacts(results); err != nil {
+	if err := b.saveSnapshot(results); err != nil {
+		return err
+	}
+
+	if err := b.enforceBudgets(results, plain); err != nil {
+		return err
+	}
+
+	if err := b.tracer.Span("write runtime artifacts", func() error { return b.writeRuntimeArtifacts(results) }); err != nil {
 		return fmt.Errorf("failed to write runtime artifacts: %w", err)
 	}
 
+	if err := b.tracer.Span("check manifests", func() error { return b.checkManifests(results, plain) }); err != nil {
+		return err
+	}
+
 	// Deploy to destination if configured and necessary
 	if b.deployer.ShouldDeploy() {
 		if plain {
-			fmt.Printf("\nDeploying to %s...\n", b.config.Destination)
+			fmt.Fprintf(b.out, "\nDeploying to %s...\n", b.config.Destination)
 		} else {
-			fmt.Printf("\n%s Deploying to %s...\n", ui.Info("→"), b.config.Destination)
-		}
-		if err := b.deployer.Deploy(); err != nil {
-			return fmt.Errorf("deployment failed: %w", err)
+			fmt.Fprintf(b.out, "\n%s Deploying to %s...\n", ui.Info("→"), b.config.Destination)
+		}
+		err := b.tracer.Span("deploy", func() error {
+			deployment := b.deployer.NewDeployment()
+			if b.selection.Active() {
+				for _, baseResource := range sortedKeys(uniqueResources) {
+					if err := b.deployer.DeployResource(baseResource, deployment); err != nil {
+						return fmt.Errorf("deployment failed for %s: %w", baseResource, err)
+					}
+				}
+			} else if err := b.deployer.Deploy(deployment); err != nil {
+				return fmt.Errorf("deployment failed: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 		if plain {
-			fmt.Println("Deployed successfully")
+			fmt.Fprintln(b.out, "Deployed successfully")
 		} else {
-			fmt.Println(ui.Success("Deployed successfully!"))
+			fmt.Fprintln(b.out, ui.Success("Deployed successfully!"))
 		}
 	}
 
@@ -300,15 +534,23 @@ func (b *Builder) BuildTasksContext(ctx context.Context, tasks []BuildTask) ([]B
 	// Cleanup embedded script on exit
 	defer b.resourceBuilder.Cleanup()
 
-	b.applyEnvironmentOverrides()
-
+	if err := b.config.ApplyEnvironment(); err != nil {
+		return nil, err
+	}
 	if err := b.validateEnvironment(); err != nil {
 		return nil, err
 	}
+	if err := b.validateModules(); err != nil {
+		return nil, err
+	}
 
 	if err := b.validateTaskSources(tasks); err != nil {
 		return nil, err
 	}
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
+		return nil, err
+	}
 	sharedOptions, sharedName, err := b.sharedDependencyOptions(tasks)
 	if err != nil {
 		return nil, err
@@ -330,36 +572,110 @@ func (b *Builder) BuildTasksContext(ctx context.Context, tasks []BuildTask) ([]B
 			return nil, fmt.Errorf("failed to clean resource output directory: %w", err)
 		}
 	}
+	var sharedFailure *BuildResult
 	if sharedOptions != nil {
-		layout := b.resourceLayout(sharedName)
-		if _, err := b.resourceBuilder.GenerateSharedDependencies(ctx, layout.ServerOutDir, *sharedOptions); err != nil {
+		if sharedFailure, err = b.generateSharedResource(ctx, graph, *sharedOptions, sharedName, false); err != nil {
 			return nil, err
 		}
 	}
 
-	results, err := b.buildSequential(ctx, tasks, false)
-	if err != nil {
-		return results, err
+	results, buildErr := b.buildSequential(ctx, graph, false)
+	if errors.Is(buildErr, context.Canceled) {
+		return results, buildErr
 	}
+	if sharedFailure != nil {
+		results = append([]BuildResult{*sharedFailure}, results...)
+		buildErr = buildFailureError(results)
+	}
+	b.printDiagnosticsSummary(results, false)
 
+	// Resources that built are still deployed when others failed.
 	if err := b.writeRuntimeArtifacts(results); err != nil {
 		return results, fmt.Errorf("failed to write runtime artifacts: %w", err)
 	}
 
+	if err := b.checkManifests(results, true); err != nil {
+		return results, errors.Join(buildErr, err)
+	}
+
 	if b.deployer.ShouldDeploy() {
+		built := builtResources(results)
+		deployment := b.deployer.NewDeployment()
 		for baseResource := range uniqueResources {
-			if err := b.deployer.DeployResource(baseResource); err != nil {
+			if !built[baseResource] {
+				continue
+			}
+			if err := b.deployer.DeployResource(baseResource, deployment); err != nil {
 				return results, fmt.Errorf("deployment failed for %s: %w", baseResource, err)
 			}
 		}
 	}
 
-	return results, nil
+	return results, buildErr
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// builtResources returns the base resources whose tasks all succeeded.
+func builtResources(results []BuildResult) map[string]bool {
+	built := make(map[string]bool)
+	for _, result := range results {
+		base := baseResourceName(result.Task.ResourceName)
+		if _, seen := built[base]; !seen {
+			built[base] = true
+		}
+		if !result.Success {
+			built[base] = false
+		}
+	}
+	return built
+}
+
+// failurePolicy returns the configured failure policy (keep-going by default).
+func (b *Builder) failurePolicy() FailurePolicy {
+	if b.config.Build.FailFast {
+		return FailFast
+	}
+	return KeepGoing
+}
+
+// generateSharedResource generates the shared dependency resource. When that
+// fails and the build keeps going, the tasks importing from it are skipped
+// and the failure is returned as a result instead of an error.
+func (b *Builder) generateSharedResource(ctx context.Context, graph *TaskGraph, options SharedDependencyOptions, name string, plain bool) (*BuildResult, error) {
+	layout := b.resourceLayout(name)
+	start := time.Now()
+	output, err := b.resourceBuilder.GenerateSharedDependencies(ctx, layout.ServerOutDir, options)
+	if err == nil {
+		return nil, nil
+	}
+	if ctx.Err() != nil || b.failurePolicy() == FailFast {
+		return nil, err
+	}
+
+	result := BuildResult{
+		Task:     BuildTask{ResourceName: name, Type: TypeShared, OutDir: layout.ServerOutDir},
+		Duration: time.Since(start),
+		Error:    err,
+		Output:   output,
+	}
+	b.reportResult(result, plain)
+	graph.Skip(func(task BuildTask) bool {
+		return task.Type != TypeViews && usesSharedResource(task)
+	}, fmt.Errorf("skipped: dependency %s failed", name))
+	return &result, nil
 }
 
 func (b *Builder) writeRuntimeArtifacts(results []BuildResult) error {
 	if b.runtimeKind() != "ragemp" {
-		return nil
+		return b.writeManifests(results)
 	}
 
 	serverResources := b.collectBarrelResources(results, "server")
@@ -769,7 +1085,7 @@ func (b *Builder) sharedDependencyOptions(tasks []BuildTask) (*SharedDependencyO
 		name = "__opencore_deps"
 	}
 
-	options := &SharedDependencyOptions{SharedResourceName: name, Dependencies: deps}
+	options := &SharedDependencyOptions{SharedResourceName: name, DependencyCacheDir: b.dependencyCacheDir(), Dependencies: deps}
 	if cfg != nil {
 		options.PackageManager = cfg.PackageManager
 		options.VerifySandboxPaths = cfg.VerifySandboxPaths
@@ -1301,39 +1617,41 @@ func (b *Builder) collectAllTasks() []BuildTask {
 		}
 	}
 
+	// Drop the resources the active environment disables.
+	enabled := tasks[:0]
+	for _, task := range tasks {
+		if b.config.ResourceEnabled(baseResourceName(task.ResourceName)) {
+			enabled = append(enabled, task)
+		}
+	}
+	tasks = enabled
+
 	envAliases := b.collectEnvironmentAliases()
+	dependencyCache := b.dependencyCacheDir()
 	for i := range tasks {
 		tasks[i].Options.PackageManager = pm
 		tasks[i].Options.ResourceName = tasks[i].ResourceName
-		if len(envAliases) > 0 {
+		compiled := tasks[i].Type != TypeViews && tasks[i].Type != TypeCopy
+		if len(envAliases) > 0 && compiled {
 			tasks[i].Options.EnvironmentAliases = envAliases
 		}
+		if dependencyCache != "" && compiled {
+			tasks[i].Options.DependencyCacheDir = dependencyCache
+		}
+		if b.devSourceMaps && compiled && !tasks[i].Options.SourceMaps {
+			tasks[i].Options.DevSourceMaps = true
+		}
+		if b.config.Build.Metafile && compiled {
+			tasks[i].Options.Metafile = &MetafileOptions{
+				Server: MetafilePath(tasks[i].ResourceName, "server"),
+				Client: MetafilePath(tasks[i].ResourceName, "client"),
+			}
+		}
 	}
+	b.attachModules(tasks)
 	return tasks
 }
 
-// applyEnvironmentOverrides merges the active environment's build overrides
-// (minify, sourceMaps, logLevel) onto b.config.Build before tasks are collected.
-func (b *Builder) applyEnvironmentOverrides() {
-	envName := b.config.Build.Environment
-	if envName == "" {
-		return
-	}
-	override, ok := b.config.Build.Environments[envName]
-	if !ok {
-		return
-	}
-	if override.Minify != nil {
-		b.config.Build.Minify = *override.Minify
-	}
-	if override.SourceMaps != nil {
-		b.config.Build.SourceMaps = *override.SourceMaps
-	}
-	if override.LogLevel != "" {
-		b.config.Build.LogLevel = override.LogLevel
-	}
-}
-
 // collectEnvironmentAliases builds the esbuild alias map for the active environment:
 // - @opencore/environment → environments/environment.<name>.ts (if exists)
 // - each fileReplacement from the environment override (if any)
@@ -1425,17 +1743,18 @@ func (b *Builder) findAvailableEnvironments() []string {
 }
 
 // buildParallelTUI executes builds in parallel using worker pool with TUI
-func (b *Builder) buildParallelTUI(ctx context.Context, tasks []BuildTask, workers int) ([]BuildResult, error) {
+func (b *Builder) buildParallelTUI(ctx context.Context, graph *TaskGraph, workers int) ([]BuildResult, error) {
+	tasks := graph.Tasks()
 	pool := NewWorkerPool(workers)
-	pool.StartWithContext(b.resourceBuilder.BuildWithContext)
+	pool.StartWithContext(b.buildTask)
 	defer pool.Cancel()
 
-	// Submit all tasks
-	pool.SubmitAll(tasks)
+	// Tasks are submitted as their dependencies complete
+	scheduled := graph.Schedule(ctx, pool, b.failurePolicy())
 
 	// Run TUI
-	m := newBuildModel(tasks, pool.Results())
-	p := tea.NewProgram(m, tea.WithContext(ctx))
+	m := newBuildModel(tasks, scheduled)
+	p := tea.NewProgram(m, tea.WithContext(ctx), tea.WithOutput(b.out))
 
 	finalModel, err := p.Run()
 	if err != nil {
@@ -1446,122 +1765,176 @@ func (b *Builder) buildParallelTUI(ctx context.Context, tasks []BuildTask, worke
 	model := finalModel.(buildModel)
 	if model.cancelled || errors.Is(ctx.Err(), context.Canceled) {
 		pool.Cancel()
+		drainScheduled(scheduled)
 		pool.Close()
 		return model.results, context.Canceled
 	}
 
 	pool.Close()
 
-	// Check for failures
-	failCount := 0
-	for _, r := range model.results {
-		if !r.Success {
-			failCount++
-		}
-	}
-
-	if failCount > 0 {
-		return model.results, fmt.Errorf("%d resource(s) failed to build", failCount)
-	}
-
-	return model.results, nil
+	return model.results, buildFailureError(model.results)
 }
 
 // buildParallelPlain executes builds in parallel with plain logs (non-TTY/CI friendly)
-func (b *Builder) buildParallelPlain(ctx context.Context, tasks []BuildTask, workers int) ([]BuildResult, error) {
+func (b *Builder) buildParallelPlain(ctx context.Context, graph *TaskGraph, workers int) ([]BuildResult, error) {
+	tasks := graph.Tasks()
 	pool := NewWorkerPool(workers)
-	pool.StartWithContext(b.resourceBuilder.BuildWithContext)
+	pool.StartWithContext(b.buildTask)
 	defer pool.Cancel()
 
-	pool.SubmitAll(tasks)
+	scheduled := graph.Schedule(ctx, pool, b.failurePolicy())
 
-	fmt.Printf("Building %d task(s) with %d worker(s)\n", len(tasks), workers)
+	fmt.Fprintf(b.out, "Building %d task(s) with %d worker(s)\n", len(tasks), workers)
 
 	results := make([]BuildResult, 0, len(tasks))
 	for i := 0; i < len(tasks); i++ {
 		select {
 		case <-ctx.Done():
 			pool.Cancel()
+			drainScheduled(scheduled)
 			pool.Close()
 			return results, ctx.Err()
-		case result := <-pool.Results():
-			results = append(results, result)
-
-			if result.Success {
-				fmt.Printf("OK    [%s] (%s)\n", result.Task.ResourceName, result.Duration.Round(time.Millisecond))
-				continue
-			}
-
-			fmt.Printf("FAIL  [%s] %v\n", result.Task.ResourceName, result.Error)
-			if result.Output != "" {
-				fmt.Println("Build output:")
-				fmt.Println(result.Output)
+		case result, ok := <-scheduled:
+			if !ok {
+				pool.Cancel()
+				pool.Close()
+				return results, ctx.Err()
 			}
+			results = append(results, result)
+			b.reportResult(result, true)
 		}
 	}
 
 	pool.Close()
 
+	return results, buildFailureError(results)
+}
+
+// buildFailureError summarizes failed and skipped tasks, or returns nil when
+// every task succeeded.
+func buildFailureError(results []BuildResult) error {
 	failCount := 0
+	skipCount := 0
 	for _, r := range results {
-		if !r.Success {
+		switch {
+		case r.Skipped:
+			skipCount++
+		case !r.Success:
 			failCount++
 		}
 	}
 
-	if failCount > 0 {
-		return results, fmt.Errorf("%d resource(s) failed to build", failCount)
+	if failCount == 0 && skipCount == 0 {
+		return nil
 	}
-
-	return results, nil
+	if skipCount > 0 {
+		return fmt.Errorf("%d resource(s) failed to build, %d skipped", failCount, skipCount)
+	}
+	return fmt.Errorf("%d resource(s) failed to build", failCount)
 }
 
 // buildSequential executes builds one by one
-func (b *Builder) buildSequential(ctx context.Context, tasks []BuildTask, plain bool) ([]BuildResult, error) {
-	results := make([]BuildResult, 0, len(tasks))
-
-	for _, task := range tasks {
-		if err := ctx.Err(); err != nil {
-			return results, err
-		}
-
+func (b *Builder) buildSequential(ctx context.Context, graph *TaskGraph, plain bool) ([]BuildResult, error) {
+	build := func(task BuildTask) BuildResult {
 		if plain {
-			fmt.Printf("Building %s...\n", task.ResourceName)
+			fmt.Fprintf(b.out, "Building %s...\n", task.ResourceName)
 		} else {
-			fmt.Printf("%s Building %s...\n", ui.Info("→"), task.ResourceName)
+			fmt.Fprintf(b.out, "%s Building %s...\n", ui.Info("→"), task.ResourceName)
 		}
+		return b.buildTask(ctx, task)
+	}
+	report := func(result BuildResult) {
+		b.reportResult(result, plain)
+	}
 
-		result := b.resourceBuilder.BuildWithContext(ctx, task)
-		results = append(results, result)
-
-		if result.Success {
-			if plain {
-				fmt.Printf("OK    [%s] (%s)\n", task.ResourceName, result.Duration.Round(time.Millisecond))
-			} else {
-				fmt.Println(ui.Success(fmt.Sprintf("[%s] compiled (%s)", task.ResourceName, result.Duration.Round(time.Millisecond))))
-			}
+	results, err := graph.Walk(ctx, b.failurePolicy(), build, report)
+	if err != nil {
+		return results, err
+	}
+	return results, buildFailureError(results)
+}
+
+// reportResult prints the outcome of a task, with its diagnostics, or its
+// output when it failed without any.
+func (b *Builder) reportResult(result BuildResult, plain bool) {
+	name := result.Task.ResourceName
+	switch {
+	case result.Success && plain:
+		fmt.Fprintf(b.out, "OK    [%s] (%s)\n", name, resultDurationLabel(result))
+	case result.Success:
+		fmt.Fprintln(b.out, ui.Success(fmt.Sprintf("[%s] compiled (%s)", name, resultDurationLabel(result))))
+	case result.Skipped && plain:
+		fmt.Fprintf(b.out, "SKIP  [%s] %v\n", name, result.Error)
+	case result.Skipped:
+		fmt.Fprintln(b.out, ui.Muted(fmt.Sprintf("[%s] %v", name, result.Error)))
+	default:
+		if plain {
+			fmt.Fprintf(b.out, "FAIL  [%s] %v\n", name, result.Error)
 		} else {
+			fmt.Fprintln(b.out, ui.Error(fmt.Sprintf("[%s] failed: %v", name, result.Error)))
+		}
+		if len(result.Diagnostics) > 0 {
+			break
+		}
+		if result.Output != "" {
 			if plain {
-				fmt.Printf("FAIL  [%s] %v\n", task.ResourceName, result.Error)
+				fmt.Fprintln(b.out, "Build output:")
 			} else {
-				fmt.Println(ui.Error(fmt.Sprintf("[%s] failed: %v", task.ResourceName, result.Error)))
+				fmt.Fprintln(b.out, ui.Muted("Build output:"))
 			}
-			if result.Output != "" {
-				if plain {
-					fmt.Println("Build output:")
-				} else {
-					fmt.Println(ui.Muted("Build output:"))
-				}
-				fmt.Println(result.Output)
-			}
-			if errors.Is(result.Error, context.Canceled) {
-				return results, result.Error
-			}
-			return results, fmt.Errorf("build failed for %s", task.ResourceName)
+			fmt.Fprintln(b.out, result.Output)
+		}
+	}
+	if len(result.Diagnostics) > 0 {
+		fmt.Fprint(b.out, indent(formatDiagnostics(result.Diagnostics, !plain), "      "))
+	}
+}
+
+// printDiagnosticsSummary prints the number of errors and warnings of each
+// resource that reported any.
+func (b *Builder) printDiagnosticsSummary(results []BuildResult, plain bool) {
+	var order []string
+	byResource := make(map[string][]Diagnostic)
+	for _, result := range results {
+		if len(result.Diagnostics) == 0 {
+			continue
 		}
+		name := baseResourceName(result.Task.ResourceName)
+		if _, seen := byResource[name]; !seen {
+			order = append(order, name)
+		}
+		byResource[name] = append(byResource[name], result.Diagnostics...)
+	}
+	if len(order) == 0 {
+		return
 	}
 
-	return results, nil
+	if plain {
+		fmt.Fprintln(b.out, "\nDiagnostics:")
+	} else {
+		fmt.Fprintln(b.out, "\n"+ui.TitleStyle.Render("Diagnostics"))
+	}
+	for _, name := range order {
+		fmt.Fprintf(b.out, "  %-20s %s\n", name, diagnosticsSummary(byResource[name]))
+	}
+}
+
+func indent(text, prefix string) string {
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// resultDurationLabel formats a result's duration, marking cache restores.
+func resultDurationLabel(result BuildResult) string {
+	if result.Cached {
+		return "cached"
+	}
+	return result.Duration.Round(time.Millisecond).String()
 }
 
 // hasClientCode checks if a resource has client code
@@ -1824,7 +2197,7 @@ func (b *Builder) showSummary(results []BuildResult, plain bool) {
 		return
 	}
 
-	fmt.Println()
+	fmt.Fprintln(b.out)
 
 	// Get resource sizes
 	sizes := b.getResourceSizes(results)
@@ -1865,9 +2238,16 @@ func (b *Builder) showSummary(results []BuildResult, plain bool) {
 			boxContent.WriteString("\n")
 			for _, s := range sizes {
 				nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+				deltaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+				withDelta := func(value string, side string) string {
+					if delta := b.sizeDelta(s, side); delta != "" {
+						return value + deltaStyle.Render(fmt.Sprintf(" (%s)", delta))
+					}
+					return value
+				}
 				if s.IsViews {
 					// Views show only total size (includes JS, CSS, HTML, assets) + framework
-					totalStr := lipgloss.NewStyle().Foreground(lipgloss.Color("#E879F9")).Render(formatSize(s.TotalSize))
+					totalStr := withDelta(lipgloss.NewStyle().Foreground(lipgloss.Color("#E879F9")).Render(formatSize(s.TotalSize)), "total")
 					frameworkStr := ""
 					if s.Framework != "" {
 						frameworkStr = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(fmt.Sprintf(" (%s)", s.Framework))
@@ -1878,20 +2258,29 @@ func (b *Builder) showSummary(results []BuildResult, plain bool) {
 					serverStr := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render("-")
 					clientStr := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render("-")
 					if s.ServerSize > 0 {
-						serverStr = lipgloss.NewStyle().Foreground(lipgloss.Color("#60A5FA")).Render(formatSize(s.ServerSize))
+						serverStr = withDelta(lipgloss.NewStyle().Foreground(lipgloss.Color("#60A5FA")).Render(formatSize(s.ServerSize)), "server")
 					}
 					if s.ClientSize > 0 {
-						clientStr = lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Render(formatSize(s.ClientSize))
+						clientStr = withDelta(lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Render(formatSize(s.ClientSize)), "client")
 					}
 					boxContent.WriteString(fmt.Sprintf("%s  Server: %s  Client: %s\n",
 						nameStyle.Render(fmt.Sprintf("%-14s", s.Name)), serverStr, clientStr))
 				}
 			}
+			baseline := b.comparisonBaseline(sizes)
+			for _, removed := range baseline.Removed(sizes) {
+				boxContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(
+					fmt.Sprintf("%-14s  removed (was %s)", removed.Name, formatSize(removed.Total))) + "\n")
+			}
 			totalStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F59E0B"))
 			boxContent.WriteString(fmt.Sprintf("\nTotal: %s", totalStyle.Render(formatSize(grandTotal))))
+			if baseline != nil {
+				boxContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(
+					fmt.Sprintf(" (%s)", formatSizeDelta(baseline.Total(), grandTotal))))
+			}
 		}
 
-		fmt.Println(ui.SuccessBoxStyle.Render(boxContent.String()))
+		fmt.Fprintln(b.out, ui.SuccessBoxStyle.Render(boxContent.String()))
 	} else {
 		var boxContent strings.Builder
 		boxContent.WriteString("Build completed with errors\n\n")
@@ -1920,17 +2309,17 @@ func (b *Builder) showSummary(results []BuildResult, plain bool) {
 			boxContent.WriteString(fmt.Sprintf("✗ Failed: Standalones: %d", failStandaloneCount))
 		}
 
-		fmt.Println(ui.ErrorBoxStyle.Render(boxContent.String()))
+		fmt.Fprintln(b.out, ui.ErrorBoxStyle.Render(boxContent.String()))
 	}
 }
 
 func (b *Builder) showSummaryPlain(successResourceCount, successUICount, successStandaloneCount, failResourceCount, failStandaloneCount, failCount int, totalDuration time.Duration, results []BuildResult) {
-	fmt.Println()
+	fmt.Fprintln(b.out)
 
 	if failCount == 0 {
-		fmt.Println("Build completed successfully")
+		fmt.Fprintln(b.out, "Build completed successfully")
 	} else {
-		fmt.Println("Build completed with errors")
+		fmt.Fprintln(b.out, "Build completed with errors")
 	}
 
 	parts := make([]string, 0, 3)
@@ -1944,7 +2333,7 @@ func (b *Builder) showSummaryPlain(successResourceCount, successUICount, success
 		parts = append(parts, fmt.Sprintf("Standalones: %d", successStandaloneCount))
 	}
 	if len(parts) > 0 {
-		fmt.Printf("Success: %s\n", strings.Join(parts, " | "))
+		fmt.Fprintf(b.out, "Success: %s\n", strings.Join(parts, " | "))
 	}
 
 	if failCount > 0 {
@@ -1955,13 +2344,13 @@ func (b *Builder) showSummaryPlain(successResourceCount, successUICount, success
 		if failStandaloneCount > 0 {
 			failedParts = append(failedParts, fmt.Sprintf("Standalones: %d", failStandaloneCount))
 		}
-		fmt.Printf("Failed: %s\n", strings.Join(failedParts, " | "))
+		fmt.Fprintf(b.out, "Failed: %s\n", strings.Join(failedParts, " | "))
 	}
 
-	fmt.Printf("Time: %s\n", totalDuration.Round(time.Millisecond))
+	fmt.Fprintf(b.out, "Time: %s\n", totalDuration.Round(time.Millisecond))
 
 	if b.deployer.HasDestination() {
-		fmt.Printf("Deployed: %s\n", b.config.Destination)
+		fmt.Fprintf(b.out, "Deployed: %s\n", b.config.Destination)
 	}
 
 	sizes := b.getResourceSizes(results)
@@ -1969,35 +2358,50 @@ func (b *Builder) showSummaryPlain(successResourceCount, successUICount, success
 		return
 	}
 
-	fmt.Println()
-	fmt.Println("Bundle sizes")
+	fmt.Fprintln(b.out)
+	fmt.Fprintln(b.out, "Bundle sizes")
 
 	var grandTotal int64
 	for _, s := range sizes {
 		grandTotal += s.TotalSize
+		withDelta := func(value string, side string) string {
+			if delta := b.sizeDelta(s, side); delta != "" {
+				return fmt.Sprintf("%s (%s)", value, delta)
+			}
+			return value
+		}
 		if s.IsViews {
 			if s.Framework != "" {
-				fmt.Printf("- %s: total=%s framework=%s\n", s.Name, formatSize(s.TotalSize), s.Framework)
+				fmt.Fprintf(b.out, "- %s: total=%s framework=%s\n", s.Name, withDelta(formatSize(s.TotalSize), "total"), s.Framework)
 			} else {
-				fmt.Printf("- %s: total=%s\n", s.Name, formatSize(s.TotalSize))
+				fmt.Fprintf(b.out, "- %s: total=%s\n", s.Name, withDelta(formatSize(s.TotalSize), "total"))
 			}
 			continue
 		}
 
 		serverSize := "-"
 		if s.ServerSize > 0 {
-			serverSize = formatSize(s.ServerSize)
+			serverSize = withDelta(formatSize(s.ServerSize), "server")
 		}
 
 		clientSize := "-"
 		if s.ClientSize > 0 {
-			clientSize = formatSize(s.ClientSize)
+			clientSize = withDelta(formatSize(s.ClientSize), "client")
 		}
 
-		fmt.Printf("- %s: server=%s client=%s\n", s.Name, serverSize, clientSize)
+		fmt.Fprintf(b.out, "- %s: server=%s client=%s\n", s.Name, serverSize, clientSize)
 	}
 
-	fmt.Printf("Total: %s\n", formatSize(grandTotal))
+	baseline := b.comparisonBaseline(sizes)
+	for _, removed := range baseline.Removed(sizes) {
+		fmt.Fprintf(b.out, "- %s: removed (was %s)\n", removed.Name, formatSize(removed.Total))
+	}
+
+	if baseline != nil {
+		fmt.Fprintf(b.out, "Total: %s (%s)\n", formatSize(grandTotal), formatSizeDelta(baseline.Total(), grandTotal))
+		return
+	}
+	fmt.Fprintf(b.out, "Total: %s\n", formatSize(grandTotal))
 }
 
 // ============================================================================
@@ -2011,6 +2415,7 @@ const (
 	statusBuilding
 	statusDone
 	statusFailed
+	statusSkipped
 )
 
 type taskState struct {
@@ -2086,9 +2491,12 @@ func (m buildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for i := range m.tasks {
 			if m.tasks[i].task.Path == result.Task.Path &&
 				m.tasks[i].task.Type == result.Task.Type {
-				if result.Success {
+				switch {
+				case result.Success:
 					m.tasks[i].status = statusDone
-				} else {
+				case result.Skipped:
+					m.tasks[i].status = statusSkipped
+				default:
 					m.tasks[i].status = statusFailed
 				}
 				m.tasks[i].result = &result
@@ -2152,11 +2560,13 @@ func (m buildModel) View() string {
 		case statusDone:
 			duration := ""
 			if ts.result != nil {
-				duration = fmt.Sprintf(" (%s)", ts.result.Duration.Round(time.Millisecond))
+				duration = fmt.Sprintf(" (%s)", resultDurationLabel(*ts.result))
 			}
 			b.WriteString(fmt.Sprintf("  %s %s%s\n", ui.Success("✓"), ts.task.ResourceName, ui.Muted(duration)))
 		case statusFailed:
 			b.WriteString(fmt.Sprintf("  %s %s\n", ui.Error("✗"), ts.task.ResourceName))
+		case statusSkipped:
+			b.WriteString(fmt.Sprintf("  ○ %s\n", ui.Muted(ts.task.ResourceName+" (skipped)")))
 		}
 	}
 
@@ -2179,21 +2589,32 @@ func (m buildModel) renderFinal() string {
 		case statusDone:
 			duration := ""
 			if ts.result != nil {
-				duration = fmt.Sprintf(" (%s)", ts.result.Duration.Round(time.Millisecond))
+				duration = fmt.Sprintf(" (%s)", resultDurationLabel(*ts.result))
 			}
 			b.WriteString(fmt.Sprintf("%s [%s] compiled%s\n", ui.Success("✓"), ts.task.ResourceName, ui.Muted(duration)))
+			if ts.result != nil && len(ts.result.Diagnostics) > 0 {
+				b.WriteString(indent(formatDiagnostics(ts.result.Diagnostics, true), "  "))
+			}
 		case statusFailed:
 			errMsg := ""
 			if ts.result != nil && ts.result.Error != nil {
 				errMsg = fmt.Sprintf(": %v", ts.result.Error)
 			}
 			b.WriteString(fmt.Sprintf("%s [%s] failed%s\n", ui.Error("✗"), ts.task.ResourceName, errMsg))
-			// Show build output for failed tasks (contains detailed error messages)
-			if ts.result != nil && ts.result.Output != "" {
+			// Show diagnostics, or the build output when the compiler reported none
+			if ts.result != nil && len(ts.result.Diagnostics) > 0 {
+				b.WriteString(indent(formatDiagnostics(ts.result.Diagnostics, true), "  "))
+			} else if ts.result != nil && ts.result.Output != "" {
 				b.WriteString(ui.Muted("Build output:\n"))
 				b.WriteString(ts.result.Output)
 				b.WriteString("\n")
 			}
+		case statusSkipped:
+			reason := ""
+			if ts.result != nil && ts.result.Error != nil {
+				reason = fmt.Sprintf(": %v", ts.result.Error)
+			}
+			b.WriteString(fmt.Sprintf("○ [%s] skipped%s\n", ts.task.ResourceName, ui.Muted(reason)))
 		default:
 			b.WriteString(fmt.Sprintf("○ [%s] skipped\n", ts.task.ResourceName))
 		}
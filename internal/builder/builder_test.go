@@ -395,6 +395,44 @@ func TestCollectAllTasks_WithGlobPatterns(t *testing.T) {
 	}
 }
 
+func TestCollectAllTasks_EnvironmentDisablesResources(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"core", "resources/admin", "resources/devtools"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(tmpDir)
+
+	cfg := &config.Config{
+		Name:   "test-project",
+		OutDir: "./dist",
+		Core: config.CoreConfig{
+			Path:         "./core",
+			ResourceName: "[core]",
+		},
+		Resources: config.ResourcesConfig{
+			Include: []string{"./resources/*"},
+		},
+		Build: config.BuildConfig{
+			Environment: "production",
+			Environments: map[string]config.EnvironmentOverride{
+				"production": {Resources: map[string]bool{"devtools": false}},
+			},
+		},
+	}
+
+	tasks := New(cfg).collectAllTasks()
+	if got := taskNames(tasks); got != "[core],admin" {
+		t.Errorf("expected devtools to be left out of production, got %s", got)
+	}
+
+	cfg.Build.Environment = "development"
+	if got := taskNames(New(cfg).collectAllTasks()); got != "[core],admin,devtools" {
+		t.Errorf("expected every resource in development, got %s", got)
+	}
+}
+
 func TestDetectViewFramework_PrefersViteConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	viewDir := filepath.Join(tmpDir, "ui")
@@ -11,7 +11,7 @@ import (
 func (b *Builder) validateTaskSources(tasks []BuildTask) error {
 	resourcePaths := make(map[string]struct{})
 	for _, task := range tasks {
-		if task.Type == TypeViews || !task.Options.Compile {
+		if task.Type == TypeViews || !task.Options.Compile || b.validatedSources[filepath.Clean(task.Path)] {
 			continue
 		}
 		resourcePaths[filepath.Clean(task.Path)] = struct{}{}
@@ -38,6 +38,11 @@ func (b *Builder) validateTaskSources(tasks []BuildTask) error {
 	}
 
 	if len(allIssues) == 0 {
+		if b.validatedSources != nil {
+			for resourcePath := range resourcePaths {
+				b.validatedSources[resourcePath] = true
+			}
+		}
 		return nil
 	}
 
@@ -51,9 +56,9 @@ func (b *Builder) validateTaskSources(tasks []BuildTask) error {
 		return allIssues[i].Message < allIssues[j].Message
 	})
 
-	fmt.Println(ui.Warning("Source validation failed. Build cancelled."))
+	fmt.Fprintln(b.out, ui.Warning("Source validation failed. Build cancelled."))
 	for _, issue := range allIssues {
-		fmt.Println(ui.Warning(issue.String()))
+		fmt.Fprintln(b.out, ui.Warning(issue.String()))
 	}
 
 	return fmt.Errorf("source validation failed: %d issue(s) detected", len(allIssues))
@@ -0,0 +1,512 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/newcore-network/opencore-cli/internal/builder/embedded"
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+const buildCacheFormatVersion = "opencore-build-cache/v1"
+
+// projectCacheInputs are project-level files that influence every task's output.
+var projectCacheInputs = []string{
+	"opencore.config.ts",
+	"package.json",
+	"pnpm-lock.yaml",
+	"package-lock.json",
+	"yarn.lock",
+	"tsconfig.json",
+	"vite.config.ts",
+	"vite.config.js",
+	"vite.config.mjs",
+	"vite.config.cjs",
+}
+
+// inputsMarker prefixes the output line on which the build scripts send the
+// files a successful build read (see reportInputs in build.js).
+const inputsMarker = "::opencore-inputs::"
+
+var (
+	embeddedScriptVersionOnce sync.Once
+	embeddedScriptVersion     string
+)
+
+// BuildCache stores the outputs of successful build tasks keyed on a content
+// hash of everything that influences them. Only the latest entry per task is
+// kept, so the cache never grows beyond one copy of the build output per
+// environment built with Builder.ForEnvironment.
+type BuildCache struct {
+	dir         string
+	environment string
+	// presets are the config files the project extends. Like
+	// opencore.config.ts, they influence every task's output.
+	presets []string
+}
+
+// cacheOutput is one output directory of a task, stored in the cache entry
+// under Name.
+type cacheOutput struct {
+	Name    string
+	Path    string
+	Exclude []string
+}
+
+// NewBuildCache creates a build cache rooted at dir.
+func NewBuildCache(dir string) *BuildCache {
+	return &BuildCache{dir: dir}
+}
+
+// forEnvironment returns a view of the cache that keeps the entries of
+// environment-dependent tasks apart, so building several environments does
+// not evict each other's entries.
+func (c *BuildCache) forEnvironment(name string) *BuildCache {
+	return &BuildCache{dir: c.dir, environment: name, presets: c.presets}
+}
+
+// projectBuildCache creates the cache of the project cfg describes, in its
+// .opencore directory.
+func projectBuildCache(cfg *config.Config) *BuildCache {
+	cache := NewBuildCache(filepath.Join(".opencore", "cache"))
+	for _, preset := range cfg.Presets() {
+		cache.presets = append(cache.presets, preset.File)
+	}
+	return cache
+}
+
+// Dir returns the cache root directory.
+func (c *BuildCache) Dir() string {
+	return c.dir
+}
+
+// buildScriptVersion hashes the embedded build scripts so a CLI upgrade
+// invalidates every cached output.
+func buildScriptVersion() string {
+	embeddedScriptVersionOnce.Do(func() {
+		h := sha256.New()
+		entries, err := embedded.BuildFS.ReadDir(".")
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			content, err := embedded.BuildFS.ReadFile(entry.Name())
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", entry.Name(), len(content))
+			h.Write(content)
+		}
+		embeddedScriptVersion = hex.EncodeToString(h.Sum(nil))
+	})
+	return embeddedScriptVersion
+}
+
+// Key computes the cache key of a task from its source tree, serialized
+// BuildOptions, environment alias targets, imported shared modules, custom
+// compiler, project-level inputs and the embedded build script version, and
+// from the files outside its source tree that its last build read.
+func (c *BuildCache) Key(task BuildTask, skipDirs []string) (string, error) {
+	sources, err := c.sourcesKey(task, skipDirs)
+	if err != nil {
+		return "", err
+	}
+	return inputsKey(sources, c.storedInputs(task)), nil
+}
+
+// sourcesKey hashes everything Key covers except the inputs recorded by the
+// previous build, which are only known once the task has been built.
+func (c *BuildCache) sourcesKey(task BuildTask, skipDirs []string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", buildCacheFormatVersion, buildScriptVersion())
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", task.Type, task.ResourceName, filepath.ToSlash(filepath.Clean(task.Path)))
+
+	optionsJSON, err := json.Marshal(task.Options)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal options: %w", err)
+	}
+	h.Write(optionsJSON)
+	h.Write([]byte{0})
+
+	for _, input := range projectCacheInputs {
+		hashFileIfExists(h, input)
+	}
+	for _, preset := range c.presets {
+		hashFileIfExists(h, preset)
+	}
+
+	aliasTargets := make([]string, 0, len(task.Options.EnvironmentAliases))
+	for _, target := range task.Options.EnvironmentAliases {
+		aliasTargets = append(aliasTargets, target)
+	}
+	sort.Strings(aliasTargets)
+	for _, target := range aliasTargets {
+		hashFileIfExists(h, filepath.FromSlash(target))
+	}
+
+	for _, module := range task.Modules {
+		fmt.Fprintf(h, "module\x00%s\x00", filepath.ToSlash(module))
+		if err := hashTree(h, module, nil); err != nil {
+			return "", fmt.Errorf("failed to hash module %s: %w", module, err)
+		}
+	}
+
+	if task.CustomCompiler != "" {
+		if err := hashFile(h, task.CustomCompiler); err != nil {
+			return "", fmt.Errorf("failed to hash custom compiler: %w", err)
+		}
+	}
+
+	if err := hashTree(h, task.Path, skipDirs); err != nil {
+		return "", fmt.Errorf("failed to hash sources of %s: %w", task.ResourceName, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// inputsKey extends the sources key of a task with the contents of inputs.
+// A missing input hashes differently from an empty one.
+func inputsKey(sources string, inputs []string) string {
+	if len(inputs) == 0 {
+		return sources
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", sources)
+	for _, input := range inputs {
+		if err := hashFile(h, filepath.FromSlash(input)); err != nil {
+			fmt.Fprintf(h, "missing\x00%s\x00", input)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// externalInputs returns the files a build of task read from outside its
+// source tree, relative to the project and sorted. Dependencies are left
+// out: the lockfiles among the project inputs cover them.
+func externalInputs(task BuildTask, inputs []string) []string {
+	root, err := filepath.Abs(task.Path)
+	if err != nil {
+		return nil
+	}
+	project, err := filepath.Abs(".")
+	if err != nil {
+		return nil
+	}
+	var external []string
+	for _, input := range inputs {
+		if rel, err := filepath.Rel(root, input); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if slices.Contains(strings.Split(filepath.ToSlash(input), "/"), "node_modules") {
+			continue
+		}
+		if info, err := os.Stat(input); err != nil || !info.Mode().IsRegular() {
+			continue // esbuild also lists inputs of plugin namespaces
+		}
+		if rel, err := filepath.Rel(project, input); err == nil {
+			input = rel
+		}
+		external = append(external, filepath.ToSlash(input))
+	}
+	sort.Strings(external)
+	return slices.Compact(external)
+}
+
+// extractInputs removes the inputs line from a build's output and returns
+// the inputs it carries.
+func extractInputs(output string) (string, []string) {
+	if !strings.Contains(output, inputsMarker) {
+		return output, nil
+	}
+
+	var inputs []string
+	var kept []string
+	for _, line := range strings.Split(output, "\n") {
+		payload, found := strings.CutPrefix(strings.TrimSpace(line), inputsMarker)
+		if !found {
+			kept = append(kept, line)
+			continue
+		}
+		var reported []string
+		if err := json.Unmarshal([]byte(payload), &reported); err != nil {
+			kept = append(kept, line)
+			continue
+		}
+		inputs = append(inputs, reported...)
+	}
+	return strings.Join(kept, "\n"), inputs
+}
+
+// storedInputs returns the external inputs recorded with the cache entry of task.
+func (c *BuildCache) storedInputs(task BuildTask) []string {
+	content, err := os.ReadFile(filepath.Join(c.entryDir(task), "inputs"))
+	if err != nil {
+		return nil
+	}
+	var inputs []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" {
+			inputs = append(inputs, line)
+		}
+	}
+	return inputs
+}
+
+func hashFileIfExists(h hash.Hash, path string) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	_ = hashFile(h, path)
+}
+
+func hashFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(h, "%s\x00", filepath.ToSlash(path))
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	h.Write([]byte{0})
+	return nil
+}
+
+// hashTree hashes every file below root in lexical order. Dependency and
+// output folders are skipped, as are any directories listed in skipDirs.
+func hashTree(h hash.Hash, root string, skipDirs []string) error {
+	skipAbs := make([]string, 0, len(skipDirs))
+	for _, dir := range skipDirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			skipAbs = append(skipAbs, filepath.Clean(abs))
+		}
+	}
+
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			switch d.Name() {
+			case "node_modules", "dist", ".git", ".opencore":
+				if path != root {
+					return filepath.SkipDir
+				}
+			}
+			if abs, err := filepath.Abs(path); err == nil {
+				for _, skip := range skipAbs {
+					if filepath.Clean(abs) == skip {
+						return filepath.SkipDir
+					}
+				}
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+
+		if d.Type()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "link\x00%s\x00%s\x00", filepath.ToSlash(rel), filepath.ToSlash(target))
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fmt.Fprintf(h, "file\x00%s\x00", filepath.ToSlash(rel))
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		h.Write([]byte{0})
+		return nil
+	})
+}
+
+func (c *BuildCache) entryDir(task BuildTask) string {
+	name := strings.NewReplacer("/", "__", "\\", "__", ":", "_").Replace(task.ResourceName)
+	entry := fmt.Sprintf("%s.%s", name, task.Type)
+	if c.environment != "" && task.Type != TypeViews {
+		entry += "." + c.environment
+	}
+	return filepath.Join(c.dir, "tasks", entry)
+}
+
+// Restore copies the cached outputs of task back into place when the stored
+// key matches. It reports whether the outputs were restored.
+func (c *BuildCache) Restore(task BuildTask, key string, outputs []cacheOutput) (bool, error) {
+	entry := c.entryDir(task)
+	stored, err := os.ReadFile(filepath.Join(entry, "key"))
+	if err != nil || strings.TrimSpace(string(stored)) != key {
+		return false, nil
+	}
+
+	for _, output := range outputs {
+		src := filepath.Join(entry, output.Name)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(output.Path), 0755); err != nil {
+			return false, err
+		}
+		if err := copyTree(src, output.Path, nil); err != nil {
+			return false, fmt.Errorf("failed to restore cached %s output: %w", output.Name, err)
+		}
+	}
+
+	return true, nil
+}
+
+// Store replaces the cache entry of task with its current outputs and the
+// external inputs its build read (see externalInputs).
+func (c *BuildCache) Store(task BuildTask, key string, outputs []cacheOutput, inputs []string) error {
+	entry := c.entryDir(task)
+	staging := fmt.Sprintf("%s.tmp-%d", entry, os.Getpid())
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		if _, err := os.Stat(output.Path); os.IsNotExist(err) {
+			continue
+		}
+		if err := copyTree(output.Path, filepath.Join(staging, output.Name), output.Exclude); err != nil {
+			os.RemoveAll(staging)
+			return fmt.Errorf("failed to cache %s output: %w", output.Name, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(staging, "key"), []byte(key+"\n"), 0644); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if len(inputs) > 0 {
+		if err := os.WriteFile(filepath.Join(staging, "inputs"), []byte(strings.Join(inputs, "\n")+"\n"), 0644); err != nil {
+			os.RemoveAll(staging)
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(entry); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	return os.Rename(staging, entry)
+}
+
+// copyTree copies src into dst, recreating symlinks as links. Paths listed in
+// exclude are skipped.
+func copyTree(src, dst string, exclude []string) error {
+	excludeAbs := make([]string, 0, len(exclude))
+	for _, path := range exclude {
+		if abs, err := filepath.Abs(path); err == nil {
+			excludeAbs = append(excludeAbs, filepath.Clean(abs))
+		}
+	}
+
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if len(excludeAbs) > 0 {
+			if abs, err := filepath.Abs(path); err == nil {
+				for _, skip := range excludeAbs {
+					if filepath.Clean(abs) == skip {
+						if d.IsDir() {
+							return filepath.SkipDir
+						}
+						return nil
+					}
+				}
+			}
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target)
+		}
+	})
+}
+
+// cacheOutputs lists the directories a task writes to. Views output lives
+// inside the resource directory but belongs to its own task, so it is excluded
+// from the resource's entry. The metafiles of a --metafile build are outputs
+// too, so `opencore analyze` finds them after a build served from the cache.
+func (b *Builder) cacheOutputs(task BuildTask) []cacheOutput {
+	if task.Type == TypeViews {
+		return []cacheOutput{{Name: "views", Path: task.OutDir}}
+	}
+
+	layout := b.resourceLayout(task.ResourceName)
+	outputs := []cacheOutput{{Name: "server", Path: layout.ServerOutDir, Exclude: []string{layout.ViewsOutDir}}}
+	if layout.ClientOutDir != layout.ServerOutDir {
+		outputs = append(outputs, cacheOutput{Name: "client", Path: layout.ClientOutDir, Exclude: []string{layout.ViewsOutDir}})
+	}
+	if metafile := task.Options.Metafile; metafile != nil {
+		if metafile.Server != "" {
+			outputs = append(outputs, cacheOutput{Name: "metafile.server.json", Path: metafile.Server})
+		}
+		if metafile.Client != "" {
+			outputs = append(outputs, cacheOutput{Name: "metafile.client.json", Path: metafile.Client})
+		}
+	}
+	return outputs
+}
+
+// cacheSkipDirs returns directories that must never be hashed as task sources.
+func (b *Builder) cacheSkipDirs() []string {
+	skip := []string{b.config.OutDir}
+	if b.config.Destination != "" {
+		skip = append(skip, b.config.Destination)
+	}
+	return skip
+}
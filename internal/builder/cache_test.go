@@ -0,0 +1,281 @@
+package builder
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func writeCacheTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildCacheKeyChangesWithInputs(t *testing.T) {
+	root := t.TempDir()
+	resourcePath := filepath.Join(root, "resources", "chat")
+	writeCacheTestFile(t, filepath.Join(resourcePath, "src", "server", "main.ts"), "console.log('a')")
+	writeCacheTestFile(t, filepath.Join(resourcePath, "node_modules", "pkg", "index.js"), "ignored")
+
+	cache := NewBuildCache(filepath.Join(root, ".opencore", "cache"))
+	task := BuildTask{Path: resourcePath, ResourceName: "chat", Type: TypeResource, Options: BuildOptions{Minify: true}}
+
+	key1, err := cache.Key(task, nil)
+	if err != nil {
+		t.Fatalf("Key failed: %v", err)
+	}
+	key2, _ := cache.Key(task, nil)
+	if key1 != key2 {
+		t.Fatal("expected key to be stable for unchanged inputs")
+	}
+
+	writeCacheTestFile(t, filepath.Join(resourcePath, "node_modules", "pkg", "index.js"), "still ignored")
+	if key, _ := cache.Key(task, nil); key != key1 {
+		t.Fatal("expected node_modules changes to be ignored")
+	}
+
+	task.Options.Minify = false
+	if key, _ := cache.Key(task, nil); key == key1 {
+		t.Fatal("expected key to change with build options")
+	}
+	task.Options.Minify = true
+
+	writeCacheTestFile(t, filepath.Join(resourcePath, "src", "server", "main.ts"), "console.log('b')")
+	if key, _ := cache.Key(task, nil); key == key1 {
+		t.Fatal("expected key to change with source contents")
+	}
+}
+
+func TestBuildCacheKeyTracksEnvironmentAliasTargets(t *testing.T) {
+	root := t.TempDir()
+	resourcePath := filepath.Join(root, "core")
+	envFile := filepath.Join(root, "environments", "environment.production.ts")
+	writeCacheTestFile(t, filepath.Join(resourcePath, "src", "server.ts"), "export {}")
+	writeCacheTestFile(t, envFile, "export const environment = { production: true }")
+
+	cache := NewBuildCache(filepath.Join(root, ".opencore", "cache"))
+	task := BuildTask{
+		Path:         resourcePath,
+		ResourceName: "core",
+		Type:         TypeCore,
+		Options: BuildOptions{
+			EnvironmentAliases: map[string]string{"@opencore/environment": filepath.ToSlash(envFile)},
+		},
+	}
+
+	key1, _ := cache.Key(task, nil)
+	writeCacheTestFile(t, envFile, "export const environment = { production: false }")
+	if key, _ := cache.Key(task, nil); key == key1 {
+		t.Fatal("expected key to change when the environment file changes")
+	}
+}
+
+func TestBuildCacheKeyTracksPresets(t *testing.T) {
+	root := t.TempDir()
+	resourcePath := filepath.Join(root, "core")
+	presetFile := filepath.Join(root, "node_modules", "@acme", "opencore-preset", "opencore.config.ts")
+	writeCacheTestFile(t, filepath.Join(resourcePath, "src", "server.ts"), "export {}")
+	writeCacheTestFile(t, presetFile, "export default { build: { minify: true } }")
+
+	cache := NewBuildCache(filepath.Join(root, ".opencore", "cache"))
+	cache.presets = []string{presetFile}
+	task := BuildTask{Path: resourcePath, ResourceName: "core", Type: TypeCore}
+
+	key1, _ := cache.Key(task, nil)
+	writeCacheTestFile(t, presetFile, "export default { build: { minify: false } }")
+	if key, _ := cache.Key(task, nil); key == key1 {
+		t.Fatal("expected key to change when a preset changes")
+	}
+}
+
+func TestExternalInputs(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	for _, path := range []string{"resources/chat/src/server.ts", "shared/x.ts", "node_modules/pkg/index.js"} {
+		writeCacheTestFile(t, filepath.Join(root, filepath.FromSlash(path)), "export {}")
+	}
+
+	task := BuildTask{Path: "resources/chat", ResourceName: "chat", Type: TypeResource}
+	got := externalInputs(task, []string{
+		filepath.Join(root, "resources", "chat", "src", "server.ts"),
+		filepath.Join(root, "shared", "x.ts"),
+		filepath.Join(root, "node_modules", "pkg", "index.js"),
+		filepath.Join(root, "opencore-virtual:reflect"),
+		filepath.Join(root, "shared", "x.ts"),
+	})
+	if len(got) != 1 || got[0] != "shared/x.ts" {
+		t.Errorf("expected only the shared file, relative to the project, got %v", got)
+	}
+}
+
+// newCacheTestBuilder returns a builder with a build cache for the project
+// in the working directory.
+func newCacheTestBuilder() *Builder {
+	return &Builder{
+		config:          &config.Config{OutDir: "build"},
+		resourceBuilder: NewResourceBuilder("."),
+		cache:           NewBuildCache(filepath.Join(".opencore", "cache")),
+		out:             io.Discard,
+	}
+}
+
+func TestBuildCacheTracksExternalInputs(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("skipping: node is not installed")
+	}
+
+	root := t.TempDir()
+	t.Chdir(root)
+	writeCacheTestFile(t, filepath.Join(root, "resources", "chat", "src", "server.ts"), "import '../../../shared/x'")
+	writeCacheTestFile(t, filepath.Join(root, "shared", "x.ts"), "export const x = 1")
+	// The compiler reports its inputs the way build.js reports esbuild's.
+	writeCacheTestFile(t, filepath.Join(root, "compile.js"), `const fs = require('fs')
+const path = require('path')
+const [, , , , resourcePath, outDir] = process.argv
+fs.mkdirSync(outDir, { recursive: true })
+fs.writeFileSync(path.join(outDir, 'server.js'), fs.readFileSync('shared/x.ts'))
+console.log('::opencore-inputs::' + JSON.stringify([path.resolve(resourcePath, 'src/server.ts'), path.resolve('shared/x.ts')]))
+`)
+
+	b := newCacheTestBuilder()
+	task := BuildTask{
+		Path:           "resources/chat",
+		ResourceName:   "chat",
+		Type:           TypeResource,
+		OutDir:         filepath.Join("build", "chat"),
+		CustomCompiler: "compile.js",
+	}
+
+	if result := b.buildTaskCached(context.Background(), task); !result.Success || result.Cached {
+		t.Fatalf("expected a fresh build, got %+v", result)
+	}
+	if result := b.buildTaskCached(context.Background(), task); !result.Cached {
+		t.Fatalf("expected a cache hit for unchanged inputs, got %+v", result)
+	}
+
+	writeCacheTestFile(t, filepath.Join(root, "shared", "x.ts"), "export const x = 2")
+	if result := b.buildTaskCached(context.Background(), task); !result.Success || result.Cached {
+		t.Fatalf("expected editing an imported file outside the resource to rebuild, got %+v", result)
+	}
+	content, err := os.ReadFile(filepath.Join(root, "build", "chat", "server.js"))
+	if err != nil || string(content) != "export const x = 2" {
+		t.Errorf("expected the rebuilt bundle, got %q (%v)", content, err)
+	}
+}
+
+func TestBuildCacheRestoresMetafiles(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("skipping: node is not installed")
+	}
+
+	root := t.TempDir()
+	t.Chdir(root)
+	writeCacheTestFile(t, filepath.Join(root, "resources", "chat", "src", "server.ts"), "export {}")
+	// The compiler writes the metafile the way build.js does with --metafile.
+	writeCacheTestFile(t, filepath.Join(root, "compile.js"), `const fs = require('fs')
+const path = require('path')
+const [, , , , , outDir, optionsJSON] = process.argv
+const options = JSON.parse(optionsJSON)
+fs.mkdirSync(outDir, { recursive: true })
+fs.writeFileSync(path.join(outDir, 'server.js'), '// built')
+fs.mkdirSync(path.dirname(options.metafile.server), { recursive: true })
+fs.writeFileSync(options.metafile.server, '{"inputs":{},"outputs":{}}')
+`)
+
+	b := newCacheTestBuilder()
+	task := BuildTask{
+		Path:           "resources/chat",
+		ResourceName:   "chat",
+		Type:           TypeResource,
+		OutDir:         filepath.Join("build", "chat"),
+		CustomCompiler: "compile.js",
+		Options:        BuildOptions{Metafile: &MetafileOptions{Server: MetafilePath("chat", "server"), Client: MetafilePath("chat", "client")}},
+	}
+	if result := b.buildTaskCached(context.Background(), task); !result.Success || result.Cached {
+		t.Fatalf("expected a fresh build, got %+v", result)
+	}
+
+	// CI only keeps .opencore/cache between runs.
+	for _, dir := range []string{"build", MetafileDir} {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if result := b.buildTaskCached(context.Background(), task); !result.Cached {
+		t.Fatalf("expected a cache hit, got %+v", result)
+	}
+	if content, err := os.ReadFile(MetafilePath("chat", "server")); err != nil || string(content) != `{"inputs":{},"outputs":{}}` {
+		t.Errorf("expected the metafile to be restored, got %q (%v)", content, err)
+	}
+	if _, err := os.Stat(MetafilePath("chat", "client")); !os.IsNotExist(err) {
+		t.Errorf("expected no client metafile for a server-only build, got %v", err)
+	}
+}
+
+func TestBuildCacheStoreAndRestore(t *testing.T) {
+	root := t.TempDir()
+	outDir := filepath.Join(root, "build", "chat")
+	viewsDir := filepath.Join(outDir, "ui")
+	writeCacheTestFile(t, filepath.Join(outDir, "server.js"), "server")
+	writeCacheTestFile(t, filepath.Join(outDir, "fxmanifest.lua"), "fx_version 'cerulean'")
+	writeCacheTestFile(t, filepath.Join(viewsDir, "index.html"), "<html></html>")
+
+	cache := NewBuildCache(filepath.Join(root, ".opencore", "cache"))
+	task := BuildTask{ResourceName: "chat", Type: TypeResource}
+	outputs := []cacheOutput{{Name: "server", Path: outDir, Exclude: []string{viewsDir}}}
+
+	if err := cache.Store(task, "key-1", outputs, nil); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	if err := os.RemoveAll(outDir); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := cache.Restore(task, "key-2", outputs)
+	if err != nil || restored {
+		t.Fatalf("expected mismatched key to miss, got restored=%v err=%v", restored, err)
+	}
+
+	restored, err = cache.Restore(task, "key-1", outputs)
+	if err != nil || !restored {
+		t.Fatalf("expected cache hit, got restored=%v err=%v", restored, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outDir, "server.js"))
+	if err != nil || string(content) != "server" {
+		t.Fatalf("expected server.js to be restored, got %q (%v)", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "fxmanifest.lua")); err != nil {
+		t.Fatalf("expected fxmanifest.lua to be restored: %v", err)
+	}
+	if _, err := os.Stat(viewsDir); !os.IsNotExist(err) {
+		t.Fatal("expected views output to be excluded from the resource cache entry")
+	}
+}
+
+func TestBuildCacheKeepsEnvironmentsApart(t *testing.T) {
+	root := t.TempDir()
+	cache := NewBuildCache(filepath.Join(root, ".opencore", "cache"))
+	staging, production := cache.forEnvironment("staging"), cache.forEnvironment("production")
+
+	resource := BuildTask{ResourceName: "chat", Type: TypeResource}
+	if staging.entryDir(resource) == production.entryDir(resource) || staging.entryDir(resource) == cache.entryDir(resource) {
+		t.Errorf("expected per-environment entries for resources, got %s", staging.entryDir(resource))
+	}
+
+	views := BuildTask{ResourceName: "chat/ui", Type: TypeViews}
+	if staging.entryDir(views) != production.entryDir(views) {
+		t.Errorf("expected environments to share views entries, got %s and %s", staging.entryDir(views), production.entryDir(views))
+	}
+}
@@ -0,0 +1,358 @@
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// DaemonProtocolVersion is the version of the JSON-RPC protocol spoken with
+// the embedded daemon.js. Both sides refuse to talk across versions.
+const DaemonProtocolVersion = 1
+
+// errDaemonUnavailable means the task should be built by a one-shot process.
+var errDaemonUnavailable = errors.New("build daemon unavailable")
+
+type daemonRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type daemonResponse struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *DaemonError    `json:"error"`
+}
+
+// DaemonError is an error reported by the build daemon, usually a failed
+// build. Output holds what the build printed before failing.
+type DaemonError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Output string `json:"output"`
+	} `json:"data"`
+}
+
+func (e *DaemonError) Error() string {
+	return e.Message
+}
+
+type daemonBuildParams struct {
+	Type         string `json:"type"`
+	ResourcePath string `json:"resourcePath"`
+	OutDir       string `json:"outDir"`
+	Options      any    `json:"options"`
+	Task         string `json:"task"`
+}
+
+// syncBuffer collects a worker's stderr, which exec writes from its own goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.buf.String()
+	b.buf.Reset()
+	return s
+}
+
+// daemonWorker is one long-lived node process running daemon.js from the
+// project at root, whose node_modules it loads esbuild and the plugins from.
+type daemonWorker struct {
+	root   string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr *syncBuffer
+	nextID int
+	tasks  map[string]bool // tasks whose esbuild contexts this worker holds
+	dead   bool
+}
+
+func startDaemonWorker(projectPath, scriptPath string) (*daemonWorker, error) {
+	cmd := exec.Command("node", scriptPath)
+	cmd.Dir = projectPath
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &syncBuffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	w := &daemonWorker{
+		root:   projectPath,
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewReaderSize(stdout, 64*1024),
+		stderr: stderr,
+		tasks:  make(map[string]bool),
+	}
+
+	var hello struct {
+		ProtocolVersion int `json:"protocolVersion"`
+	}
+	params := map[string]int{"protocolVersion": DaemonProtocolVersion}
+	if _, err := w.call(context.Background(), "initialize", params, &hello); err != nil {
+		w.kill()
+		return nil, fmt.Errorf("build daemon handshake failed: %w", err)
+	}
+	if hello.ProtocolVersion != DaemonProtocolVersion {
+		w.kill()
+		return nil, fmt.Errorf("build daemon speaks protocol %d, expected %d", hello.ProtocolVersion, DaemonProtocolVersion)
+	}
+	return w, nil
+}
+
+// call sends one request and waits for its response. A *DaemonError is a
+// failure reported by the daemon; any other error leaves the worker dead.
+func (w *daemonWorker) call(ctx context.Context, method string, params, result any) (string, error) {
+	w.nextID++
+	request, err := json.Marshal(daemonRequest{JSONRPC: "2.0", ID: w.nextID, Method: method, Params: params})
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.stdin.Write(append(request, '\n')); err != nil {
+		w.kill()
+		return w.stderr.take(), err
+	}
+
+	type reply struct {
+		line []byte
+		err  error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		line, err := w.stdout.ReadBytes('\n')
+		done <- reply{line, err}
+	}()
+
+	var r reply
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		// The build cannot be interrupted mid-request; drop the worker.
+		w.kill()
+		return w.stderr.take(), ctx.Err()
+	}
+	if r.err != nil {
+		w.kill()
+		return w.stderr.take(), fmt.Errorf("build daemon exited: %w", r.err)
+	}
+
+	var response daemonResponse
+	if err := json.Unmarshal(r.line, &response); err != nil || response.ID == nil || *response.ID != w.nextID {
+		w.kill()
+		return w.stderr.take(), fmt.Errorf("build daemon sent an invalid response: %s", bytes.TrimSpace(r.line))
+	}
+	if response.Error != nil {
+		return response.Error.Data.Output + w.stderr.take(), response.Error
+	}
+
+	var payload struct {
+		Output string `json:"output"`
+	}
+	_ = json.Unmarshal(response.Result, &payload)
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return payload.Output, err
+		}
+	}
+	return payload.Output + w.stderr.take(), nil
+}
+
+func (w *daemonWorker) kill() {
+	if w.dead {
+		return
+	}
+	w.dead = true
+	w.stdin.Close()
+	if w.cmd.Process != nil {
+		w.cmd.Process.Kill()
+	}
+	w.cmd.Wait()
+}
+
+// shutdown asks the worker to dispose its contexts and exit.
+func (w *daemonWorker) shutdown() {
+	if w.dead {
+		return
+	}
+	w.call(context.Background(), "shutdown", nil, nil)
+	w.dead = true
+	w.stdin.Close()
+	w.cmd.Wait()
+}
+
+// buildDaemon is a small pool of daemon workers. Workers are started on
+// demand, and a task prefers the idle worker that built it last so its
+// esbuild context stays warm. A worker only serves the project it was
+// started for: the projects of a workspace share the pool, but each has its
+// own toolchain in its node_modules.
+type buildDaemon struct {
+	projectPath string
+	script      func() (string, error) // extracts daemon.js for the current project if needed
+
+	slots    chan struct{}
+	mu       sync.Mutex
+	idle     []*daemonWorker
+	disabled map[string]error // by project root
+	closed   bool
+}
+
+func newBuildDaemon(projectPath string, script func() (string, error), workers int) *buildDaemon {
+	if workers < 1 {
+		workers = 1
+	}
+	return &buildDaemon{
+		projectPath: projectPath,
+		script:      script,
+		slots:       make(chan struct{}, workers),
+		disabled:    make(map[string]error),
+	}
+}
+
+func (d *buildDaemon) acquire(ctx context.Context, root, task string) (*daemonWorker, error) {
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	d.mu.Lock()
+	if d.closed || d.disabled[root] != nil {
+		d.mu.Unlock()
+		<-d.slots
+		return nil, errDaemonUnavailable
+	}
+	pick := -1
+	for i, w := range d.idle {
+		if w.root == root && w.tasks[task] {
+			pick = i
+			break
+		}
+	}
+	for i := len(d.idle) - 1; pick == -1 && i >= 0; i-- {
+		if d.idle[i].root == root {
+			pick = i
+		}
+	}
+	if pick >= 0 {
+		w := d.idle[pick]
+		d.idle = append(d.idle[:pick], d.idle[pick+1:]...)
+		d.mu.Unlock()
+		return w, nil
+	}
+	// The idle workers serve other projects. Stop the oldest when the new
+	// worker would exceed the pool size.
+	var stale *daemonWorker
+	if len(d.slots)+len(d.idle) > cap(d.slots) {
+		stale = d.idle[0]
+		d.idle = d.idle[1:]
+	}
+	d.mu.Unlock()
+	if stale != nil {
+		stale.shutdown()
+	}
+
+	scriptPath, err := d.script()
+	var w *daemonWorker
+	if err == nil {
+		w, err = startDaemonWorker(root, scriptPath)
+	}
+	if err != nil {
+		// Without a working daemon every task of the project falls back to
+		// one-shot builds.
+		d.mu.Lock()
+		d.disabled[root] = err
+		d.mu.Unlock()
+		<-d.slots
+		return nil, errDaemonUnavailable
+	}
+	return w, nil
+}
+
+func (d *buildDaemon) release(w *daemonWorker) {
+	d.mu.Lock()
+	if !w.dead {
+		if d.closed {
+			defer w.shutdown()
+		} else {
+			d.idle = append(d.idle, w)
+		}
+	}
+	d.mu.Unlock()
+	<-d.slots
+}
+
+// build runs a build task on a worker. It returns errDaemonUnavailable when
+// the task has to be built by a one-shot process instead.
+func (d *buildDaemon) build(ctx context.Context, task BuildTask) (string, error) {
+	// Task paths are relative to the project being built, which is the
+	// working directory of the workers that serve it.
+	root, err := filepath.Abs(d.projectPath)
+	if err != nil {
+		return "", errDaemonUnavailable
+	}
+	key := taskKey(task)
+	w, err := d.acquire(ctx, root, key)
+	if err != nil {
+		return "", err
+	}
+	defer d.release(w)
+
+	params := daemonBuildParams{
+		Type:         string(task.Type),
+		ResourcePath: task.Path,
+		OutDir:       task.OutDir,
+		Options:      task.Options,
+		Task:         key,
+	}
+	output, err := w.call(ctx, "build", params, nil)
+	w.tasks[key] = true
+
+	var daemonErr *DaemonError
+	if err == nil || errors.As(err, &daemonErr) || ctx.Err() != nil {
+		return output, err
+	}
+	// The worker crashed mid-build; retry the task one-shot.
+	return "", errDaemonUnavailable
+}
+
+// Close stops every worker.
+func (d *buildDaemon) Close() {
+	d.mu.Lock()
+	d.closed = true
+	idle := d.idle
+	d.idle = nil
+	d.mu.Unlock()
+
+	for _, w := range idle {
+		w.shutdown()
+	}
+}
@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDaemonWorkerHandshake(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("skipping: node is not installed")
+	}
+
+	rb := NewResourceBuilder(createFakeRepoWithNodeDeps(t))
+	defer rb.Close()
+	scriptPath, err := rb.daemonScriptPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := startDaemonWorker(rb.projectPath, scriptPath)
+	if err != nil {
+		t.Fatalf("failed to start daemon worker: %v", err)
+	}
+	defer w.shutdown()
+
+	_, err = w.call(context.Background(), "initialize", map[string]int{"protocolVersion": DaemonProtocolVersion + 1}, nil)
+	daemonErr, ok := err.(*DaemonError)
+	if !ok || !strings.Contains(daemonErr.Message, "protocol version") {
+		t.Fatalf("expected a protocol version error, got %v", err)
+	}
+	if _, err := w.call(context.Background(), "dispose", map[string]string{"task": "core"}, nil); err != nil {
+		t.Fatalf("dispose failed: %v", err)
+	}
+	if w.dead {
+		t.Fatal("expected the worker to survive RPC errors")
+	}
+}
+
+func TestResourceBuilderDaemonReportsBuildErrors(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("skipping: node is not installed")
+	}
+
+	repoRoot := createFakeRepoWithNodeDeps(t)
+	rb := NewResourceBuilder(repoRoot)
+	rb.EnableDaemon(1)
+
+	task := BuildTask{ResourceName: "broken", Type: "nope", Path: "missing", OutDir: t.TempDir()}
+	output, handled, err := rb.buildWithDaemon(context.Background(), task)
+	if !handled {
+		t.Fatalf("expected the daemon to build the task, disabled: %v", rb.daemon.disabled)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Unknown resource type: nope") {
+		t.Fatalf("expected the build error to be reported, got %v (output %q)", err, output)
+	}
+
+	// The scripts stay extracted for the daemon until the builder is closed.
+	rb.Cleanup()
+	cacheDir := filepath.Join(repoRoot, "node_modules", ".cache", "opencore")
+	if _, err := os.Stat(filepath.Join(cacheDir, "daemon.js")); err != nil {
+		t.Fatalf("expected daemon.js to be kept while the daemon runs: %v", err)
+	}
+	rb.Close()
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Fatalf("expected Close to remove the extracted scripts, got %v", err)
+	}
+}
+
+func TestBuildWithDaemonFallsBackForCustomCompilers(t *testing.T) {
+	rb := NewResourceBuilder(t.TempDir())
+	rb.EnableDaemon(2)
+	defer rb.Close()
+
+	task := BuildTask{ResourceName: "custom", Type: TypeResource, CustomCompiler: "./compiler.js"}
+	if _, handled, _ := rb.buildWithDaemon(context.Background(), task); handled {
+		t.Fatal("expected custom compilers to run one-shot")
+	}
+	if rb.daemon != nil {
+		t.Fatal("expected no daemon to be started for a custom compiler")
+	}
+
+	rb.Close()
+	if _, handled, _ := rb.buildWithDaemon(context.Background(), BuildTask{ResourceName: "core", Type: TypeCore}); handled {
+		t.Fatal("expected a closed builder to run one-shot")
+	}
+}
+
+func TestBuildDaemonKeepsWorkersPerProject(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("skipping: node is not installed")
+	}
+
+	fivem, redm := createFakeRepoWithNodeDeps(t), createFakeRepoWithNodeDeps(t)
+	rb := NewResourceBuilder(".")
+	defer rb.Close()
+	daemon := newBuildDaemon(".", rb.daemonScriptPath, 1)
+	defer daemon.Close()
+
+	t.Chdir(fivem)
+	first, err := daemon.acquire(context.Background(), fivem, "core")
+	if err != nil {
+		t.Fatalf("failed to start a worker for fivem: %v", err)
+	}
+	first.tasks["core"] = true
+	daemon.release(first)
+
+	t.Chdir(redm)
+	second, err := daemon.acquire(context.Background(), redm, "core")
+	if err != nil {
+		t.Fatalf("failed to start a worker for redm: %v", err)
+	}
+	defer daemon.release(second)
+	if second == first || second.root != redm {
+		t.Fatalf("expected a worker of its own for redm, got one for %s", second.root)
+	}
+	if !first.dead {
+		t.Error("expected the idle fivem worker to be stopped to stay within the pool size")
+	}
+	if _, err := os.Stat(filepath.Join(redm, "node_modules", ".cache", "opencore", "daemon.js")); err != nil {
+		t.Errorf("expected the redm worker to run the scripts extracted in redm: %v", err)
+	}
+}
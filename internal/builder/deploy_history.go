@@ -0,0 +1,309 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DeploymentMetaFile is written into every deployed resource.
+	DeploymentMetaFile = ".opencore-deploy.json"
+	// DeployHistoryDir holds the retained versions of each resource, relative
+	// to the destination.
+	DeployHistoryDir = ".opencore/deploys"
+
+	deploymentIDLayout = "20060102-150405.000"
+)
+
+// Deployment describes one deployed version of a resource.
+type Deployment struct {
+	ID          string    `json:"id"`
+	Resource    string    `json:"resource"`
+	DeployedAt  time.Time `json:"deployedAt"`
+	Environment string    `json:"environment,omitempty"`
+	Runtime     string    `json:"runtime,omitempty"`
+	Commit      string    `json:"commit,omitempty"`
+	Dirty       bool      `json:"dirty,omitempty"`
+
+	Path string `json:"-"` // directory holding this version
+}
+
+// ResourceHistory is the deployed version of a resource and the versions
+// retained for rollback, newest first.
+type ResourceHistory struct {
+	Resource string
+	Current  *Deployment
+	Versions []Deployment
+}
+
+// Previous returns the newest retained version that is not the current one.
+func (h ResourceHistory) Previous() *Deployment {
+	for i := range h.Versions {
+		if h.Current == nil || h.Versions[i].ID != h.Current.ID {
+			return &h.Versions[i]
+		}
+	}
+	return nil
+}
+
+// Find returns the retained version whose ID equals or uniquely starts with id.
+func (h ResourceHistory) Find(id string) (*Deployment, error) {
+	var matches []*Deployment
+	for i := range h.Versions {
+		if h.Versions[i].ID == id {
+			return &h.Versions[i], nil
+		}
+		if strings.HasPrefix(h.Versions[i].ID, id) {
+			matches = append(matches, &h.Versions[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("deployment id %q is ambiguous for %s", id, h.Resource)
+	}
+}
+
+// NewDeployment stamps a deployment with the time, environment and the git
+// commit of the project. A build computes it once and passes it to every
+// Deploy and DeployResource call, so git runs once per build.
+func (d *Deployer) NewDeployment() Deployment {
+	now := time.Now().UTC()
+	deployment := Deployment{
+		ID:          now.Format(deploymentIDLayout),
+		DeployedAt:  now,
+		Environment: d.config.Build.Environment,
+		Runtime:     d.config.RuntimeKind(),
+	}
+	if out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output(); err == nil {
+		deployment.Commit = strings.TrimSpace(string(out))
+		if status, err := exec.Command("git", "status", "--porcelain").Output(); err == nil {
+			deployment.Dirty = len(strings.TrimSpace(string(status))) > 0
+		}
+	}
+	return deployment
+}
+
+// resourceKey identifies a deployed directory relative to the destination,
+// e.g. "chat" or "packages/chat" for RageMP.
+func (d *Deployer) resourceKey(dst string) string {
+	if rel, err := filepath.Rel(d.config.Destination, dst); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.Base(dst)
+}
+
+func (d *Deployer) historyDir(key string) string {
+	return filepath.Join(d.config.Destination, filepath.FromSlash(DeployHistoryDir), url.PathEscape(key))
+}
+
+func writeDeployment(dir string, deployment Deployment) error {
+	data, err := json.MarshalIndent(deployment, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, DeploymentMetaFile), append(data, '\n'), 0644)
+}
+
+// readDeployment loads the metadata of a deployed directory. Directories
+// deployed before metadata existed are identified by their modification time.
+func readDeployment(dir string) (*Deployment, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	deployment := Deployment{Path: dir}
+	if data, err := os.ReadFile(filepath.Join(dir, DeploymentMetaFile)); err == nil {
+		if err := json.Unmarshal(data, &deployment); err != nil {
+			return nil, fmt.Errorf("invalid deployment metadata in %s: %w", dir, err)
+		}
+	}
+	if deployment.ID == "" {
+		deployment.DeployedAt = info.ModTime().UTC()
+		deployment.ID = deployment.DeployedAt.Format(deploymentIDLayout)
+	}
+	deployment.Path = dir
+	return &deployment, nil
+}
+
+// archivePrevious moves .<name>.prev into the history and prunes the history
+// so that, with the new .prev, deploy.keep versions remain.
+func (d *Deployer) archivePrevious(dst string) error {
+	key := d.resourceKey(dst)
+	historyDir := d.historyDir(key)
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		return err
+	}
+
+	prev := PreviousDeploymentPath(dst)
+	if deployment, err := readDeployment(prev); err == nil {
+		target := filepath.Join(historyDir, deployment.ID)
+		if _, err := os.Stat(target); err == nil {
+			// Already retained, e.g. a version restored by a rollback.
+			if err := os.RemoveAll(prev); err != nil {
+				return err
+			}
+		} else {
+			if deployment.Resource == "" {
+				deployment.Resource = key
+				if err := writeDeployment(prev, *deployment); err != nil {
+					return err
+				}
+			}
+			if err := os.Rename(prev, target); err != nil {
+				return err
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	versions, err := d.archivedVersions(key)
+	if err != nil {
+		return err
+	}
+	keep := d.config.Deploy.KeepVersions() - 1
+	for i := keep; i < len(versions); i++ {
+		if err := os.RemoveAll(versions[i].Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archivedVersions lists the history of a resource, newest first.
+func (d *Deployer) archivedVersions(key string) ([]Deployment, error) {
+	historyDir := d.historyDir(key)
+	entries, err := os.ReadDir(historyDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions []Deployment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		deployment, err := readDeployment(filepath.Join(historyDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *deployment)
+	}
+	sortDeployments(versions)
+	return versions, nil
+}
+
+func sortDeployments(versions []Deployment) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		if !versions[i].DeployedAt.Equal(versions[j].DeployedAt) {
+			return versions[i].DeployedAt.After(versions[j].DeployedAt)
+		}
+		return versions[i].ID > versions[j].ID
+	})
+}
+
+// History returns the deployment history of every resource in the
+// destination, or of the resources matching name ("chat" also matches
+// RageMP's "packages/chat" and "client_packages/chat").
+func (d *Deployer) History(name string) ([]ResourceHistory, error) {
+	if d.config.Destination == "" {
+		return nil, fmt.Errorf("no destination configured")
+	}
+	root := filepath.Join(d.config.Destination, filepath.FromSlash(DeployHistoryDir))
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var histories []ResourceHistory
+	for _, entry := range entries {
+		key, err := url.PathUnescape(entry.Name())
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+		if name != "" && key != name && path.Base(key) != name {
+			continue
+		}
+
+		history := ResourceHistory{Resource: key}
+		dst := filepath.Join(d.config.Destination, filepath.FromSlash(key))
+		if current, err := readDeployment(dst); err == nil {
+			history.Current = current
+		}
+		if prev, err := readDeployment(PreviousDeploymentPath(dst)); err == nil {
+			history.Versions = append(history.Versions, *prev)
+		}
+		archived, err := d.archivedVersions(key)
+		if err != nil {
+			return nil, err
+		}
+		history.Versions = append(history.Versions, archived...)
+		sortDeployments(history.Versions)
+		histories = append(histories, history)
+	}
+
+	sort.Slice(histories, func(i, j int) bool { return histories[i].Resource < histories[j].Resource })
+	return histories, nil
+}
+
+// Rollback restores retained versions. With an id, every matching resource
+// that retains that deployment is restored to it; without one, each resource
+// goes back to its previous version. The versions restored are returned.
+func (d *Deployer) Rollback(name, id string) ([]Deployment, error) {
+	histories, err := d.History(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("no deployment history for %q in %s", name, d.config.Destination)
+		}
+		return nil, fmt.Errorf("no deployment history in %s", d.config.Destination)
+	}
+
+	var targets []Deployment
+	for _, history := range histories {
+		var target *Deployment
+		if id != "" {
+			if target, err = history.Find(id); err != nil {
+				return nil, err
+			}
+		} else {
+			target = history.Previous()
+		}
+		if target == nil || (history.Current != nil && target.ID == history.Current.ID) {
+			continue
+		}
+		target.Resource = history.Resource
+		targets = append(targets, *target)
+	}
+
+	if len(targets) == 0 {
+		if id != "" {
+			return nil, fmt.Errorf("deployment %q is not retained for any matching resource", id)
+		}
+		return nil, fmt.Errorf("no previous version to roll back to")
+	}
+
+	for _, target := range targets {
+		dst := filepath.Join(d.config.Destination, filepath.FromSlash(target.Resource))
+		if err := d.deployStaged(target.Path, dst, target); err != nil {
+			return nil, fmt.Errorf("failed to roll back %s: %w", target.Resource, err)
+		}
+	}
+	return targets, nil
+}
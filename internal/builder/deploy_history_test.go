@@ -0,0 +1,102 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func deployVersion(t *testing.T, deployer *Deployer, outDir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(outDir, "chat"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "chat", "server.js"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := deployer.DeployResource("chat", deployer.NewDeployment()); err != nil {
+		t.Fatalf("deploy %s failed: %v", content, err)
+	}
+	// Deployment ids have millisecond resolution.
+	time.Sleep(5 * time.Millisecond)
+}
+
+func deployedContent(t *testing.T, dstDir string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dstDir, "chat", "server.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestDeployHistoryRetention(t *testing.T) {
+	outDir := t.TempDir()
+	dstDir := t.TempDir()
+	deployer := NewDeployer(&config.Config{OutDir: outDir, Destination: dstDir, Deploy: config.DeployConfig{Keep: 2}})
+
+	for _, version := range []string{"v1", "v2", "v3", "v4"} {
+		deployVersion(t, deployer, outDir, version)
+	}
+
+	histories, err := deployer.History("chat")
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(histories) != 1 || histories[0].Resource != "chat" || histories[0].Current == nil {
+		t.Fatalf("unexpected history: %+v", histories)
+	}
+	if len(histories[0].Versions) != 2 {
+		t.Fatalf("expected deploy.keep versions to be retained, got %d", len(histories[0].Versions))
+	}
+	for i, want := range []string{"v3", "v2"} {
+		content, err := os.ReadFile(filepath.Join(histories[0].Versions[i].Path, "server.js"))
+		if err != nil || string(content) != want {
+			t.Errorf("expected version %d to be %s, got %q, %v", i, want, content, err)
+		}
+	}
+	if histories[0].Current.Resource != "chat" || histories[0].Current.Runtime != "fivem" {
+		t.Errorf("unexpected deployment metadata: %+v", histories[0].Current)
+	}
+}
+
+func TestDeployRollback(t *testing.T) {
+	outDir := t.TempDir()
+	dstDir := t.TempDir()
+	deployer := NewDeployer(&config.Config{OutDir: outDir, Destination: dstDir})
+
+	deployVersion(t, deployer, outDir, "v1")
+	histories, _ := deployer.History("chat")
+	first := histories[0].Current.ID
+	deployVersion(t, deployer, outDir, "v2")
+	deployVersion(t, deployer, outDir, "v3")
+
+	restored, err := deployer.Rollback("chat", "")
+	if err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if len(restored) != 1 || deployedContent(t, dstDir) != "v2" {
+		t.Fatalf("expected rollback to the previous version, got %q", deployedContent(t, dstDir))
+	}
+
+	if _, err := deployer.Rollback("", first); err != nil {
+		t.Fatalf("Rollback --to failed: %v", err)
+	}
+	if got := deployedContent(t, dstDir); got != "v1" {
+		t.Fatalf("expected rollback to %s, got %q", first, got)
+	}
+
+	histories, _ = deployer.History("")
+	if histories[0].Current.ID != first {
+		t.Errorf("expected the restored metadata to be kept, got %s", histories[0].Current.ID)
+	}
+	if _, err := deployer.Rollback("chat", "nope"); err == nil {
+		t.Error("expected an unknown deployment id to fail")
+	}
+	if _, err := deployer.Rollback("missing", ""); err == nil {
+		t.Error("expected an unknown resource to fail")
+	}
+}
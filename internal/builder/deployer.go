@@ -19,8 +19,11 @@ func NewDeployer(cfg *config.Config) *Deployer {
 	return &Deployer{config: cfg}
 }
 
-// Deploy copies all built resources to the destination
-func (d *Deployer) Deploy() error {
+// Deploy copies all built resources to the destination. Each resource is
+// staged next to its destination and swapped in with a rename, so the server
+// never sees a half-written resource. Every resource is recorded in its
+// history under the given deployment.
+func (d *Deployer) Deploy(deployment Deployment) error {
 	if d.config.Destination == "" {
 		return nil // No destination configured, skip deploy
 	}
@@ -30,15 +33,11 @@ func (d *Deployer) Deploy() error {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	// Copy from outDir to destination
-	srcDir := d.config.OutDir
-	dstDir := d.config.Destination
-
-	return d.copyDir(srcDir, dstDir)
+	return d.deployDir(d.config.OutDir, d.config.Destination, true, deployment)
 }
 
 // DeployResource copies a single resource to the destination
-func (d *Deployer) DeployResource(resourceName string) error {
+func (d *Deployer) DeployResource(resourceName string, deployment Deployment) error {
 	if d.config.Destination == "" {
 		return nil
 	}
@@ -46,12 +45,124 @@ func (d *Deployer) DeployResource(resourceName string) error {
 	srcPath := filepath.Join(d.config.OutDir, resourceName)
 	dstPath := filepath.Join(d.config.Destination, resourceName)
 
-	// Remove existing destination if present
-	if err := os.RemoveAll(dstPath); err != nil {
-		return fmt.Errorf("failed to clean destination: %w", err)
+	return d.deployStaged(srcPath, dstPath, deployment)
+}
+
+// deployDir deploys every directory in src as a unit. RageMP's packages and
+// client_packages roots hold many resources and are descended into instead.
+func (d *Deployer) deployDir(src, dst string, root bool, deployment Deployment) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("source directory not found: %w", err)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+
+		switch {
+		case entry.IsDir() && root && d.isPackageRoot(entry.Name()):
+			if err := d.deployDir(srcPath, dstPath, false, deployment); err != nil {
+				return err
+			}
+		case entry.IsDir():
+			if err := d.deployStaged(srcPath, dstPath, deployment); err != nil {
+				return err
+			}
+		default:
+			if err := d.copyFileAtomic(srcPath, dstPath); err != nil {
+				return err
+			}
+		}
 	}
 
-	return d.copyDir(srcPath, dstPath)
+	return nil
+}
+
+func (d *Deployer) isPackageRoot(name string) bool {
+	return d.config.RuntimeKind() == "ragemp" && (name == "packages" || name == "client_packages")
+}
+
+// deployStaged copies src into a hidden sibling of dst and renames it into
+// place. The replaced version is kept as .<name>.prev and older versions move
+// to the deployment history. On failure the destination keeps its previous
+// contents.
+func (d *Deployer) deployStaged(src, dst string, deployment Deployment) error {
+	absSrc, srcErr := filepath.Abs(src)
+	absDst, dstErr := filepath.Abs(dst)
+	if srcErr == nil && dstErr == nil && absSrc == absDst {
+		return nil
+	}
+
+	parent, name := filepath.Split(dst)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return fmt.Errorf("failed to create destination: %w", err)
+	}
+	staging := filepath.Join(parent, "."+name+".staging")
+	prev := PreviousDeploymentPath(dst)
+
+	if err := os.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to clean staging directory: %w", err)
+	}
+	if err := d.copyDir(src, staging); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	deployment.Resource = d.resourceKey(dst)
+	if err := writeDeployment(staging, deployment); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+
+	if err := d.archivePrevious(dst); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("failed to archive previous deployment: %w", err)
+	}
+	hadPrevious := false
+	if _, err := os.Lstat(dst); err == nil {
+		if err := os.Rename(dst, prev); err != nil {
+			os.RemoveAll(staging)
+			return fmt.Errorf("failed to move current deployment aside: %w", err)
+		}
+		hadPrevious = true
+	}
+
+	if err := os.Rename(staging, dst); err != nil {
+		if hadPrevious {
+			if restoreErr := os.Rename(prev, dst); restoreErr != nil {
+				return fmt.Errorf("failed to swap in %s: %w (restoring the previous deployment also failed: %v)", name, err, restoreErr)
+			}
+		}
+		os.RemoveAll(staging)
+		return fmt.Errorf("failed to swap in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// PreviousDeploymentPath returns where the version replaced by the last
+// deployment of dst is kept.
+func PreviousDeploymentPath(dst string) string {
+	parent, name := filepath.Split(dst)
+	return filepath.Join(parent, "."+name+".prev")
+}
+
+// copyFileAtomic writes dst through a temporary file and a rename.
+func (d *Deployer) copyFileAtomic(src, dst string) error {
+	tmp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".staging")
+	os.Remove(tmp)
+	if err := d.copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
 }
 
 // copyDir recursively copies a directory
@@ -112,7 +112,7 @@ func TestDeploy(t *testing.T) {
 	}
 
 	deployer := NewDeployer(cfg)
-	err := deployer.Deploy()
+	err := deployer.Deploy(deployer.NewDeployment())
 
 	if err != nil {
 		t.Fatalf("Deploy failed: %v", err)
@@ -148,7 +148,7 @@ func TestDeployNoDestination(t *testing.T) {
 	}
 
 	deployer := NewDeployer(cfg)
-	err := deployer.Deploy()
+	err := deployer.Deploy(deployer.NewDeployment())
 
 	// When destination is not set, Deploy should return nil (skip silently)
 	if err != nil {
@@ -165,7 +165,7 @@ func TestDeployNonExistentSource(t *testing.T) {
 	}
 
 	deployer := NewDeployer(cfg)
-	err := deployer.Deploy()
+	err := deployer.Deploy(deployer.NewDeployment())
 
 	if err == nil {
 		t.Error("Expected error when source directory doesn't exist")
@@ -193,7 +193,7 @@ func TestDeployCreatesDestination(t *testing.T) {
 	}
 
 	deployer := NewDeployer(cfg)
-	err := deployer.Deploy()
+	err := deployer.Deploy(deployer.NewDeployment())
 
 	if err != nil {
 		t.Fatalf("Deploy failed: %v", err)
@@ -226,7 +226,7 @@ func TestDeployPreservesStructure(t *testing.T) {
 	}
 
 	deployer := NewDeployer(cfg)
-	err := deployer.Deploy()
+	err := deployer.Deploy(deployer.NewDeployment())
 
 	if err != nil {
 		t.Fatalf("Deploy failed: %v", err)
@@ -238,3 +238,118 @@ func TestDeployPreservesStructure(t *testing.T) {
 		t.Errorf("Nested file should exist at %s", expectedPath)
 	}
 }
+
+func TestDeployResourceKeepsPrevious(t *testing.T) {
+	outDir := t.TempDir()
+	dstDir := t.TempDir()
+	cfg := &config.Config{OutDir: outDir, Destination: dstDir}
+	deployer := NewDeployer(cfg)
+
+	writeDeployFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(outDir, "chat", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeDeployFile("server.js", "v1")
+	writeDeployFile("stale.js", "v1")
+	if err := deployer.DeployResource("chat", deployer.NewDeployment()); err != nil {
+		t.Fatalf("first deploy failed: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(outDir, "chat", "stale.js")); err != nil {
+		t.Fatal(err)
+	}
+	writeDeployFile("server.js", "v2")
+	if err := deployer.DeployResource("chat", deployer.NewDeployment()); err != nil {
+		t.Fatalf("second deploy failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dstDir, "chat", "server.js"))
+	if err != nil || string(content) != "v2" {
+		t.Fatalf("expected the new version to be deployed, got %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "chat", "stale.js")); !os.IsNotExist(err) {
+		t.Error("expected files removed from the build to be removed from the destination")
+	}
+	previous, err := os.ReadFile(filepath.Join(PreviousDeploymentPath(filepath.Join(dstDir, "chat")), "server.js"))
+	if err != nil || string(previous) != "v1" {
+		t.Fatalf("expected the previous version in .chat.prev, got %q, %v", previous, err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, ".chat.staging")); !os.IsNotExist(err) {
+		t.Error("expected the staging directory to be gone")
+	}
+}
+
+func TestDeployResourceFailureKeepsDestination(t *testing.T) {
+	outDir := t.TempDir()
+	dstDir := t.TempDir()
+	deployer := NewDeployer(&config.Config{OutDir: outDir, Destination: dstDir})
+
+	if err := os.MkdirAll(filepath.Join(dstDir, "chat"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dstDir, "chat", "server.js"), []byte("live"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(outDir, "chat"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A dangling symlink makes the copy fail halfway through.
+	if err := os.Symlink(filepath.Join(outDir, "missing.js"), filepath.Join(outDir, "chat", "server.js")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := deployer.DeployResource("chat", deployer.NewDeployment()); err == nil {
+		t.Fatal("expected deploy to fail")
+	}
+
+	content, err := os.ReadFile(filepath.Join(dstDir, "chat", "server.js"))
+	if err != nil || string(content) != "live" {
+		t.Fatalf("expected the live deployment to be untouched, got %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, ".chat.staging")); !os.IsNotExist(err) {
+		t.Error("expected the staging directory to be removed after a failure")
+	}
+}
+
+func TestDeployRageMPPackages(t *testing.T) {
+	outDir := t.TempDir()
+	dstDir := t.TempDir()
+	cfg := &config.Config{
+		OutDir:      outDir,
+		Destination: dstDir,
+		Adapter: &config.AdapterConfig{
+			Server: &config.AdapterBinding{Runtime: &config.AdapterRuntimeBinding{Runtime: "ragemp"}},
+		},
+	}
+
+	for _, path := range []string{"packages/index.js", "packages/chat/index.js"} {
+		full := filepath.Join(outDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("// built"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Packages not built by the project must survive the deployment.
+	if err := os.MkdirAll(filepath.Join(dstDir, "packages", "other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	deployer := NewDeployer(cfg)
+	if err := deployer.Deploy(deployer.NewDeployment()); err != nil {
+		t.Fatalf("Deploy failed: %v", err)
+	}
+	for _, path := range []string{"packages/index.js", "packages/chat/index.js", "packages/other"} {
+		if _, err := os.Stat(filepath.Join(dstDir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("expected %s in destination: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+// diagnosticsMarker prefixes the output line on which the build scripts send
+// their diagnostics as JSON (see reportDiagnostics in build.js).
+const diagnosticsMarker = "::opencore-diagnostics::"
+
+// Diagnostic is a compiler error or warning reported by the build scripts.
+// Line and Column are 1-based; a diagnostic without a location has neither.
+type Diagnostic struct {
+	Severity string   `json:"severity"` // "error" or "warning"
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Length   int      `json:"length,omitempty"`
+	LineText string   `json:"lineText,omitempty"`
+	Message  string   `json:"message"`
+	Plugin   string   `json:"plugin,omitempty"`
+	Sides    []string `json:"sides,omitempty"` // build sides that reported it
+}
+
+// IsError reports whether the diagnostic failed the build.
+func (d Diagnostic) IsError() bool {
+	return d.Severity != "warning"
+}
+
+// Location returns "file:line:column", or the file alone.
+func (d Diagnostic) Location() string {
+	if d.Line == 0 {
+		return d.File
+	}
+	return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+}
+
+// extractDiagnostics removes the diagnostics lines from a build's output and
+// returns the remaining output and the de-duplicated diagnostics.
+func extractDiagnostics(output string) (string, []Diagnostic) {
+	if !strings.Contains(output, diagnosticsMarker) {
+		return output, nil
+	}
+
+	var diagnostics []Diagnostic
+	var kept []string
+	for _, line := range strings.Split(output, "\n") {
+		payload, found := strings.CutPrefix(strings.TrimSpace(line), diagnosticsMarker)
+		if !found {
+			kept = append(kept, line)
+			continue
+		}
+		var reported []struct {
+			Diagnostic
+			Side string `json:"side"`
+		}
+		if err := json.Unmarshal([]byte(payload), &reported); err != nil {
+			kept = append(kept, line)
+			continue
+		}
+		for _, r := range reported {
+			d := r.Diagnostic
+			if r.Side != "" {
+				d.Sides = []string{r.Side}
+			}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return strings.Join(kept, "\n"), dedupeDiagnostics(diagnostics)
+}
+
+// dedupeDiagnostics merges identical diagnostics, e.g. a shared module that
+// fails in both the server and the client build.
+func dedupeDiagnostics(diagnostics []Diagnostic) []Diagnostic {
+	var unique []Diagnostic
+	index := make(map[string]int)
+	for _, d := range diagnostics {
+		key := strings.Join([]string{d.Severity, d.File, fmt.Sprint(d.Line), fmt.Sprint(d.Column), d.Message, d.Plugin}, "\x00")
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(unique)
+			unique = append(unique, d)
+			continue
+		}
+		for _, side := range d.Sides {
+			if !containsString(unique[i].Sides, side) {
+				unique[i].Sides = append(unique[i].Sides, side)
+			}
+		}
+	}
+	return unique
+}
+
+// countDiagnostics returns the number of errors and warnings.
+func countDiagnostics(diagnostics []Diagnostic) (errors, warnings int) {
+	for _, d := range diagnostics {
+		if d.IsError() {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// diagnosticsSummary describes the counts, e.g. "2 errors, 1 warning".
+func diagnosticsSummary(diagnostics []Diagnostic) string {
+	errors, warnings := countDiagnostics(diagnostics)
+	var parts []string
+	if errors > 0 {
+		parts = append(parts, plural(errors, "error"))
+	}
+	if warnings > 0 {
+		parts = append(parts, plural(warnings, "warning"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// formatDiagnostics renders diagnostics with code frames, errors first.
+func formatDiagnostics(diagnostics []Diagnostic, color bool) string {
+	var b strings.Builder
+	for _, errorsFirst := range []bool{true, false} {
+		for _, d := range diagnostics {
+			if d.IsError() == errorsFirst {
+				b.WriteString(formatDiagnostic(d, color))
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatDiagnostic renders one diagnostic:
+//
+//	error: Could not resolve "./missing" [swc] (server, client)
+//	  --> resources/chat/src/server.ts:3:19
+//	   |
+//	 2 | import { a } from './a'
+//	 3 | import { b } from './missing'
+//	   |                   ^^^^^^^^^^^
+//	 4 |
+func formatDiagnostic(d Diagnostic, color bool) string {
+	style := func(s string, render func(...string) string) string {
+		if !color {
+			return s
+		}
+		return render(s)
+	}
+
+	var b strings.Builder
+	severity := style("error", ui.ErrorStyle.Render)
+	if !d.IsError() {
+		severity = style("warning", ui.WarningStyle.Render)
+	}
+	b.WriteString(severity + ": " + d.Message)
+	if d.Plugin != "" {
+		b.WriteString(style(fmt.Sprintf(" [%s]", d.Plugin), ui.MutedStyle.Render))
+	}
+	if len(d.Sides) > 0 {
+		b.WriteString(style(fmt.Sprintf(" (%s)", strings.Join(d.Sides, ", ")), ui.MutedStyle.Render))
+	}
+	b.WriteString("\n")
+	if d.File == "" {
+		return b.String()
+	}
+	b.WriteString(style("  --> ", ui.MutedStyle.Render) + d.Location() + "\n")
+
+	frame := codeFrame(d)
+	if len(frame) == 0 {
+		return b.String()
+	}
+	width := len(fmt.Sprint(frame[len(frame)-1].number))
+	gutter := func(label string) string {
+		return style(fmt.Sprintf(" %*s | ", width, label), ui.MutedStyle.Render)
+	}
+	b.WriteString(strings.TrimRight(gutter(""), " ") + "\n")
+	for _, line := range frame {
+		b.WriteString(gutter(fmt.Sprint(line.number)) + expandTabs(line.text) + "\n")
+		if line.number == d.Line && d.Column > 0 {
+			marker := strings.Repeat("^", max(d.Length, 1))
+			b.WriteString(gutter("") + caretPadding(line.text, d.Column-1) + style(marker, ui.ErrorStyle.Render) + "\n")
+		}
+	}
+	return b.String()
+}
+
+type frameLine struct {
+	number int
+	text   string
+}
+
+// codeFrame returns the diagnostic's line with one line of context on each
+// side, read from the source file, or just the line esbuild reported when
+// the file cannot be read.
+func codeFrame(d Diagnostic) []frameLine {
+	if d.Line == 0 {
+		return nil
+	}
+	if content, err := os.ReadFile(filepath.FromSlash(d.File)); err == nil {
+		lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+		if d.Line <= len(lines) {
+			var frame []frameLine
+			for n := max(d.Line-1, 1); n <= min(d.Line+1, len(lines)); n++ {
+				frame = append(frame, frameLine{number: n, text: lines[n-1]})
+			}
+			return frame
+		}
+	}
+	if d.LineText == "" {
+		return nil
+	}
+	return []frameLine{{number: d.Line, text: d.LineText}}
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// caretPadding returns the spaces that line the caret up under column
+// (0-based, in bytes) of text once its tabs are expanded.
+func caretPadding(text string, column int) string {
+	if column > len(text) {
+		column = len(text)
+	}
+	return strings.Repeat(" ", len([]rune(expandTabs(text[:column]))))
+}
@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractDiagnostics(t *testing.T) {
+	output := strings.Join([]string{
+		"building chat",
+		`::opencore-diagnostics::[` +
+			`{"side":"server","severity":"error","file":"src/shared/util.ts","line":3,"column":7,"message":"Expected \";\""},` +
+			`{"side":"client","severity":"error","file":"src/shared/util.ts","line":3,"column":7,"message":"Expected \";\""},` +
+			`{"side":"client","severity":"warning","file":"src/client.ts","line":1,"column":1,"message":"unused import","plugin":"swc"}]`,
+		"done",
+	}, "\n")
+
+	rest, diagnostics := extractDiagnostics(output)
+	if rest != "building chat\ndone" {
+		t.Errorf("expected the marker line to be removed, got %q", rest)
+	}
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics after de-duplication, got %+v", diagnostics)
+	}
+	if got := strings.Join(diagnostics[0].Sides, ","); got != "server,client" {
+		t.Errorf("expected the shared error to list both sides, got %s", got)
+	}
+	if diagnostics[1].IsError() || diagnostics[1].Plugin != "swc" {
+		t.Errorf("expected a swc warning, got %+v", diagnostics[1])
+	}
+	if got := diagnosticsSummary(diagnostics); got != "1 error, 1 warning" {
+		t.Errorf("unexpected summary %q", got)
+	}
+
+	if rest, diagnostics := extractDiagnostics("plain output"); rest != "plain output" || diagnostics != nil {
+		t.Errorf("expected output without a marker to be left alone, got %q %+v", rest, diagnostics)
+	}
+}
+
+func TestFormatDiagnosticCodeFrame(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "server.ts")
+	source := "import { a } from './a'\n\tconst b = missing\nexport { a }\n"
+	if err := os.WriteFile(file, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := Diagnostic{Severity: "error", File: filepath.ToSlash(file), Line: 2, Column: 12, Length: 7, Message: `"missing" is not defined`, Sides: []string{"server"}}
+	got := formatDiagnostic(d, false)
+	want := strings.Join([]string{
+		`error: "missing" is not defined (server)`,
+		"  --> " + d.File + ":2:12",
+		"   |",
+		" 1 | import { a } from './a'",
+		" 2 |     const b = missing",
+		"   |               ^^^^^^^",
+		" 3 | export { a }",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected code frame:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without the source file the frame falls back to the reported line.
+	d.File = "missing.ts"
+	d.LineText = "const b = missing"
+	d.Column = 11
+	if got := formatDiagnostic(d, false); !strings.Contains(got, " 2 | const b = missing\n   |           ^^^^^^^\n") {
+		t.Errorf("expected a frame from the reported line text, got:\n%s", got)
+	}
+}
+
+func TestFormatDiagnosticsListsErrorsFirst(t *testing.T) {
+	got := formatDiagnostics([]Diagnostic{
+		{Severity: "warning", Message: "first warning"},
+		{Severity: "error", Message: "an error"},
+	}, false)
+	if !strings.HasPrefix(got, "error: an error\n") {
+		t.Errorf("expected errors before warnings, got:\n%s", got)
+	}
+}
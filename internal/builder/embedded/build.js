@@ -1,5 +1,5 @@
 const path = require('path')
-const { buildCore, buildResource, buildStandalone, copyResource } = require('./build_functions')
+const { buildCore, buildResource, buildStandalone, copyResource, takeDiagnostics, takeInputs, takePhases } = require('./build_functions')
 const { buildViews } = require('./views')
 const { generateSharedDependencyResource } = require('./dependencies')
 
@@ -68,6 +68,45 @@ function checkBaseDependencies(options = {}) {
     }
 }
 
+// Prefix of the output line carrying the build's diagnostics as JSON
+const DIAGNOSTICS_MARKER = '::opencore-diagnostics::'
+
+/**
+ * Print the collected diagnostics for the Go CLI, if there are any
+ */
+function reportDiagnostics() {
+    const diagnostics = takeDiagnostics()
+    if (diagnostics.length > 0) {
+        console.log(DIAGNOSTICS_MARKER + JSON.stringify(diagnostics))
+    }
+}
+
+// Prefix of the output line carrying the timings of a successful build
+const PHASES_MARKER = '::opencore-phases::'
+
+/**
+ * Print the timed build phases for the Go CLI's trace, if there are any
+ */
+function reportPhases() {
+    const phases = takePhases()
+    if (phases.length > 0) {
+        console.log(PHASES_MARKER + JSON.stringify(phases))
+    }
+}
+
+// Prefix of the output line carrying the files a successful build read
+const INPUTS_MARKER = '::opencore-inputs::'
+
+/**
+ * Print the inputs of the build for the Go CLI's build cache, if there are any
+ */
+function reportInputs() {
+    const inputs = takeInputs()
+    if (inputs.length > 0) {
+        console.log(INPUTS_MARKER + JSON.stringify(inputs))
+    }
+}
+
 /**
  * Build a single resource by type (called from Go CLI)
  */
@@ -108,8 +147,12 @@ async function main() {
             checkBaseDependencies(options)
 
             await buildSingle(type, resourcePath, outDir, options)
+            reportPhases()
+            reportInputs()
+            reportDiagnostics()
             console.log(JSON.stringify({ success: true }))
         } catch (error) {
+            reportDiagnostics()
             console.error(error.message)
             process.exit(1)
         }
@@ -134,3 +177,11 @@ async function main() {
 if (require.main === module) {
     main()
 }
+
+module.exports = {
+    buildSingle,
+    checkBaseDependencies,
+    reportDiagnostics,
+    reportInputs,
+    reportPhases
+}
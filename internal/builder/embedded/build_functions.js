@@ -1,6 +1,6 @@
 const path = require('path')
 const fs = require('fs')
-const { getEsbuild, createSwcPlugin, createExcludeNodeAdaptersPlugin, createExternalPackagesPlugin, createSharedResourceDependencyPlugin, preserveFiveMExportsPlugin, createNodeGlobalsShimPlugin, createTsconfigPathsPlugin, createReflectMetadataPlugin, createAutoloadDynamicImportShimPlugin, createAutoloadControllersRedirectPlugin, createEnvironmentAliasPlugin } = require('./plugins')
+const { getEsbuild, createSwcPlugin, createExcludeNodeAdaptersPlugin, createExternalPackagesPlugin, createSharedResourceDependencyPlugin, preserveFiveMExportsPlugin, createNodeGlobalsShimPlugin, createTsconfigPathsPlugin, createReflectMetadataPlugin, createAutoloadDynamicImportShimPlugin, createAutoloadControllersRedirectPlugin, createEnvironmentAliasPlugin, createModuleAliasPlugin } = require('./plugins')
 const { getSharedConfig, getBuildOptions, getExternals } = require('./config')
 const { handleDependencies, shouldHandleDependencies, detectNativePackages, printNativePackageWarnings, checkBundleCompatibility, cleanupDependencyArtifacts } = require('./dependencies')
 
@@ -118,7 +118,7 @@ function optionsWithServerExternals(options = {}, externals = []) {
     }
 }
 
-function buildPlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null, includeFiveMExports = true) {
+function buildPlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null, includeFiveMExports = true, modules = null) {
     const plugins = [
         createReflectMetadataPlugin({ packageManager, resourcePath, target: isServerBuild ? 'server' : 'client' }),
         createAutoloadDynamicImportShimPlugin(),
@@ -140,6 +140,11 @@ function buildPlugins(isServerBuild = false, externals = [], target = 'es2020',
         }
     }
 
+    const modulePlugin = createModuleAliasPlugin(modules)
+    if (modulePlugin) {
+        plugins.unshift(modulePlugin)
+    }
+
     // Must be unshifted after tsconfig-paths so it ends up at index 0 and runs first.
     const envPlugin = createEnvironmentAliasPlugin(environmentAliases)
     if (envPlugin) {
@@ -149,16 +154,16 @@ function buildPlugins(isServerBuild = false, externals = [], target = 'es2020',
     return plugins
 }
 
-function getCorePlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null) {
-    return buildPlugins(isServerBuild, externals, target, format, resourcePath, packageManager, dependencyResolution, usedExternals, environmentAliases, true)
+function getCorePlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null, modules = null) {
+    return buildPlugins(isServerBuild, externals, target, format, resourcePath, packageManager, dependencyResolution, usedExternals, environmentAliases, true, modules)
 }
 
-function getResourcePlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null) {
-    return buildPlugins(isServerBuild, externals, target, format, resourcePath, packageManager, dependencyResolution, usedExternals, environmentAliases, true)
+function getResourcePlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null, modules = null) {
+    return buildPlugins(isServerBuild, externals, target, format, resourcePath, packageManager, dependencyResolution, usedExternals, environmentAliases, true, modules)
 }
 
-function getStandalonePlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null) {
-    return buildPlugins(isServerBuild, externals, target, format, resourcePath, packageManager, dependencyResolution, usedExternals, environmentAliases, false)
+function getStandalonePlugins(isServerBuild = false, externals = [], target = 'es2020', format = 'iife', resourcePath = null, packageManager = null, dependencyResolution = {}, usedExternals = null, environmentAliases = null, modules = null) {
+    return buildPlugins(isServerBuild, externals, target, format, resourcePath, packageManager, dependencyResolution, usedExternals, environmentAliases, false, modules)
 }
 
 /**
@@ -200,6 +205,173 @@ function resolveEntry(resourcePath, side, explicitEntry = null) {
     return null;
 }
 
+// Incremental esbuild contexts kept warm by the build daemon, keyed by task
+// and output file. The scope is only set while the daemon runs a build; in
+// one-shot mode every build starts from scratch.
+const incrementalContexts = new Map()
+let incrementalScope = null
+
+/**
+ * Set the task being built by the daemon ({ task, signature }), or null
+ */
+function setIncrementalScope(scope) {
+    incrementalScope = scope
+}
+
+/**
+ * Dispose the incremental contexts of a task, or of every task
+ */
+async function disposeIncrementalContexts(task) {
+    for (const [key, entry] of incrementalContexts) {
+        if (task && entry.task !== task) continue
+        incrementalContexts.delete(key)
+        await entry.context.dispose()
+    }
+}
+
+// Structured errors and warnings of the current build, sent back to the CLI
+// so it can render them with code frames instead of raw esbuild output.
+let diagnostics = []
+
+/**
+ * Return the diagnostics collected since the last call and reset them
+ */
+function takeDiagnostics() {
+    const collected = diagnostics
+    diagnostics = []
+    return collected
+}
+
+// Timings of the server, client and dependency phases of the current build,
+// sent back to the CLI for `opencore build --trace`.
+let phases = []
+
+/**
+ * Return the phases timed since the last call and reset them
+ */
+function takePhases() {
+    const timed = phases
+    phases = []
+    return timed
+}
+
+/**
+ * Run fn, recording its start and end as a phase. Times are epoch
+ * milliseconds so the CLI can place them on its own timeline.
+ */
+async function timePhase(name, fn) {
+    const start = performance.timeOrigin + performance.now()
+    try {
+        return await fn()
+    } finally {
+        phases.push({ name, start, end: performance.timeOrigin + performance.now() })
+    }
+}
+
+// Files esbuild read for the current build, sent back to the CLI so its
+// build cache also hashes sources imported from outside the resource.
+let inputs = new Set()
+
+/**
+ * Return the absolute paths of the inputs read since the last call and reset them
+ */
+function takeInputs() {
+    const read = [...inputs]
+    inputs = new Set()
+    return read
+}
+
+function recordInputs(metafile) {
+    for (const input of Object.keys(metafile?.inputs || {})) {
+        inputs.add(path.resolve(input))
+    }
+}
+
+function buildSide(buildOptions) {
+    const target = buildOptions.define && buildOptions.define['__OPENCORE_TARGET__']
+    if (target === '"server"' || target === '"client"') return JSON.parse(target)
+    return buildOptions.platform === 'node' ? 'server' : 'client'
+}
+
+function recordDiagnostics(messages, severity, side) {
+    for (const message of messages || []) {
+        const location = message.location || {}
+        diagnostics.push({
+            severity,
+            file: location.file || '',
+            line: location.line || 0,
+            column: location.line ? location.column + 1 : 0,
+            length: location.length || 0,
+            lineText: location.lineText || '',
+            message: message.text,
+            plugin: message.pluginName || '',
+            side,
+        })
+    }
+}
+
+/**
+ * Run esbuild, recording its errors and warnings as diagnostics
+ */
+async function runEsbuild(esbuild, buildOptions, usedExternals) {
+    const side = buildSide(buildOptions)
+    try {
+        const result = await timePhase(side, () => runEsbuildContext(esbuild, buildOptions, usedExternals))
+        recordDiagnostics(result.warnings, 'warning', side)
+        recordInputs(result.metafile)
+        return result
+    } catch (error) {
+        recordDiagnostics(error.errors, 'error', side)
+        recordDiagnostics(error.warnings, 'warning', side)
+        throw error
+    }
+}
+
+/**
+ * Run esbuild, reusing the task's incremental context when the daemon has one
+ * with the same options. Plugins of a reused context report used externals to
+ * the set they were created with, so those are copied to usedExternals.
+ */
+async function runEsbuildContext(esbuild, buildOptions, usedExternals) {
+    if (!incrementalScope) return esbuild.build(buildOptions)
+
+    const key = `${incrementalScope.task}\0${buildOptions.outfile}`
+    const { plugins, ...serializable } = buildOptions
+    const signature = incrementalScope.signature + JSON.stringify(serializable)
+    let entry = incrementalContexts.get(key)
+    if (entry && entry.signature !== signature) {
+        incrementalContexts.delete(key)
+        await entry.context.dispose()
+        entry = null
+    }
+    if (!entry) {
+        entry = { task: incrementalScope.task, signature, usedExternals, context: await esbuild.context(buildOptions) }
+        incrementalContexts.set(key, entry)
+    } else if (entry.usedExternals) {
+        entry.usedExternals.clear()
+    }
+
+    const result = await entry.context.rebuild()
+    if (usedExternals && entry.usedExternals && usedExternals !== entry.usedExternals) {
+        for (const name of entry.usedExternals) usedExternals.add(name)
+    }
+    return result
+}
+
+/**
+ * Run esbuild and, when a metafile path is given, write the esbuild metafile
+ * there for `opencore analyze`. The metafile is always generated, as it lists
+ * the inputs of the build.
+ */
+async function buildWithMetafile(esbuild, metafilePath, buildOptions, usedExternals = null) {
+    const result = await runEsbuild(esbuild, { ...buildOptions, metafile: true }, usedExternals)
+    if (metafilePath && result.metafile) {
+        await fs.promises.mkdir(path.dirname(metafilePath), { recursive: true })
+        await fs.promises.writeFile(metafilePath, JSON.stringify(result.metafile))
+    }
+    return result
+}
+
 function getLayoutOptions(outDir, options = {}) {
     return {
         runtime: options.runtime || 'fivem',
@@ -230,20 +402,20 @@ async function buildCore(resourcePath, outDir, options = {}) {
         const serverExternals = getExternals('server', options)
         const serverTarget = (serverBuildOptions.target || 'es2020').toLowerCase()
         const serverFormat = serverBuildOptions.format || 'cjs'
-        builds.push(esbuild.build({
+        builds.push(buildWithMetafile(esbuild, options.metafile?.server, {
             ...shared,
             ...serverBuildOptions,
             target: serverTarget,
             entryPoints: [serverEntry],
             outfile: path.join(layout.serverOutDir, layout.serverOutFile),
-            plugins: getCorePlugins(true, serverExternals, serverTarget, serverFormat, resourcePath, options.packageManager, options.dependencyResolution, usedServerExternals, options.environmentAliases),
+            plugins: getCorePlugins(true, serverExternals, serverTarget, serverFormat, resourcePath, options.packageManager, options.dependencyResolution, usedServerExternals, options.environmentAliases, options.modules),
             external: esbuildExternals(serverExternals, options.dependencyResolution),
             define: {
                 '__OPENCORE_LOG_LEVEL__': JSON.stringify(options.logLevel || 'INFO'),
                 '__OPENCORE_TARGET__': '"server"',
                 '__OPENCORE_RESOURCE_NAME__': JSON.stringify(options.resourceName || '')
             }
-        }))
+        }, usedServerExternals))
     }
 
     const clientBuildOptions = getBuildOptions('client', options)
@@ -251,13 +423,13 @@ async function buildCore(resourcePath, outDir, options = {}) {
         const clientExternals = getExternals('client', options)
         const clientTarget = (clientBuildOptions.target || 'es2020').toLowerCase()
         const clientFormat = clientBuildOptions.format || 'iife'
-        builds.push(esbuild.build({
+        builds.push(buildWithMetafile(esbuild, options.metafile?.client, {
             ...shared,
             ...clientBuildOptions,
             target: clientTarget,
             entryPoints: [clientEntry],
             outfile: path.join(layout.clientOutDir, layout.clientOutFile),
-            plugins: getCorePlugins(false, clientExternals, clientTarget, clientFormat, resourcePath, options.packageManager, options.dependencyResolution, null, options.environmentAliases),
+            plugins: getCorePlugins(false, clientExternals, clientTarget, clientFormat, resourcePath, options.packageManager, options.dependencyResolution, null, options.environmentAliases, options.modules),
             external: clientExternals,
             define: {
                 '__OPENCORE_LOG_LEVEL__': JSON.stringify(options.logLevel || 'INFO'),
@@ -267,16 +439,10 @@ async function buildCore(resourcePath, outDir, options = {}) {
         }))
     }
 
-    const manifestSrc = path.join(resourcePath, 'fxmanifest.lua')
-    const manifestDst = path.join(layout.serverOutDir, 'fxmanifest.lua')
-    if (layout.manifestKind === 'fxmanifest' && fs.existsSync(manifestSrc)) {
-        await fs.promises.copyFile(manifestSrc, manifestDst)
-    }
-
     await Promise.all(builds)
     const dependencyOptions = optionsWithServerExternals(options, Array.from(usedServerExternals))
     if (shouldHandleDependencies(dependencyOptions)) {
-        await handleDependencies(resourcePath, layout.serverOutDir, dependencyOptions)
+        await timePhase('dependencies', () => handleDependencies(resourcePath, layout.serverOutDir, dependencyOptions))
     } else {
         await cleanupDependencyArtifacts(layout.serverOutDir)
     }
@@ -302,13 +468,13 @@ async function buildResource(resourcePath, outDir, options = {}) {
         const serverExternals = getExternals('server', options)
         const serverTarget = (serverBuildOptions.target || 'es2020').toLowerCase()
         const serverFormat = serverBuildOptions.format || 'cjs'
-        builds.push(esbuild.build({
+        builds.push(buildWithMetafile(esbuild, options.metafile?.server, {
             ...shared,
             ...serverBuildOptions,
             target: serverTarget,
             entryPoints: [serverEntry],
             outfile: path.join(layout.serverOutDir, layout.serverOutFile),
-            plugins: getResourcePlugins(true, serverExternals, serverTarget, serverFormat, resourcePath, options.packageManager, options.dependencyResolution, usedServerExternals, options.environmentAliases),
+            plugins: getResourcePlugins(true, serverExternals, serverTarget, serverFormat, resourcePath, options.packageManager, options.dependencyResolution, usedServerExternals, options.environmentAliases, options.modules),
             external: esbuildExternals(serverExternals, options.dependencyResolution),
             define: {
                 ...shared.define,
@@ -316,7 +482,7 @@ async function buildResource(resourcePath, outDir, options = {}) {
                 '__OPENCORE_TARGET__': '"server"',
                 '__OPENCORE_RESOURCE_NAME__': JSON.stringify(options.resourceName || '')
             }
-        }))
+        }, usedServerExternals))
     }
 
     const clientEntry = resolveEntry(resourcePath, 'client', options.entryPoints?.client)
@@ -325,12 +491,12 @@ async function buildResource(resourcePath, outDir, options = {}) {
         const clientExternals = getExternals('client', options)
         const clientTarget = (clientBuildOptions.target || 'es2020').toLowerCase()
         const clientFormat = clientBuildOptions.format || 'iife'
-        builds.push(esbuild.build({
+        builds.push(buildWithMetafile(esbuild, options.metafile?.client, {
             ...shared, ...clientBuildOptions,
             target: clientTarget,
             entryPoints: [clientEntry],
             outfile: path.join(layout.clientOutDir, layout.clientOutFile),
-            plugins: getResourcePlugins(false, clientExternals, clientTarget, clientFormat, resourcePath, options.packageManager, options.dependencyResolution, null, options.environmentAliases),
+            plugins: getResourcePlugins(false, clientExternals, clientTarget, clientFormat, resourcePath, options.packageManager, options.dependencyResolution, null, options.environmentAliases, options.modules),
             external: clientExternals,
             define: {
                 ...shared.define,
@@ -341,16 +507,10 @@ async function buildResource(resourcePath, outDir, options = {}) {
         }))
     }
 
-    const manifestSrc = path.join(resourcePath, 'fxmanifest.lua')
-    const manifestDst = path.join(layout.serverOutDir, 'fxmanifest.lua')
-    if (layout.manifestKind === 'fxmanifest' && fs.existsSync(manifestSrc)) {
-        await fs.promises.copyFile(manifestSrc, manifestDst)
-    }
-
     if (builds.length > 0) await Promise.all(builds)
     const dependencyOptions = optionsWithServerExternals(options, Array.from(usedServerExternals))
     if (shouldHandleDependencies(dependencyOptions)) {
-        await handleDependencies(resourcePath, layout.serverOutDir, dependencyOptions)
+        await timePhase('dependencies', () => handleDependencies(resourcePath, layout.serverOutDir, dependencyOptions))
     } else {
         await cleanupDependencyArtifacts(layout.serverOutDir)
     }
@@ -376,12 +536,12 @@ async function buildStandalone(resourcePath, outDir, options = {}) {
         const serverExternals = getExternals('server', options)
         const serverTarget = (serverBuildOptions.target || 'es2020').toLowerCase()
         const serverFormat = serverBuildOptions.format || 'cjs'
-        builds.push(esbuild.build({
+        builds.push(buildWithMetafile(esbuild, options.metafile?.server, {
             ...shared, ...serverBuildOptions,
             target: serverTarget,
             entryPoints: [serverEntry],
             outfile: path.join(layout.serverOutDir, layout.serverOutFile),
-            plugins: getStandalonePlugins(true, serverExternals, serverTarget, serverFormat, resourcePath, options.packageManager, options.dependencyResolution, usedServerExternals, options.environmentAliases),
+            plugins: getStandalonePlugins(true, serverExternals, serverTarget, serverFormat, resourcePath, options.packageManager, options.dependencyResolution, usedServerExternals, options.environmentAliases, options.modules),
             external: esbuildExternals(serverExternals, options.dependencyResolution),
             define: {
                 ...shared.define,
@@ -389,7 +549,7 @@ async function buildStandalone(resourcePath, outDir, options = {}) {
                 '__OPENCORE_TARGET__': '"server"',
                 '__OPENCORE_RESOURCE_NAME__': JSON.stringify(options.resourceName || '')
             }
-        }))
+        }, usedServerExternals))
     }
 
     const clientEntry = resolveEntry(resourcePath, 'client', options.entryPoints?.client)
@@ -398,12 +558,12 @@ async function buildStandalone(resourcePath, outDir, options = {}) {
         const clientExternals = getExternals('client', options)
         const clientTarget = (clientBuildOptions.target || 'es2020').toLowerCase()
         const clientFormat = clientBuildOptions.format || 'iife'
-        builds.push(esbuild.build({
+        builds.push(buildWithMetafile(esbuild, options.metafile?.client, {
             ...shared, ...clientBuildOptions,
             target: clientTarget,
             entryPoints: [clientEntry],
             outfile: path.join(layout.clientOutDir, layout.clientOutFile),
-            plugins: getStandalonePlugins(false, clientExternals, clientTarget, clientFormat, resourcePath, options.packageManager, options.dependencyResolution, null, options.environmentAliases),
+            plugins: getStandalonePlugins(false, clientExternals, clientTarget, clientFormat, resourcePath, options.packageManager, options.dependencyResolution, null, options.environmentAliases, options.modules),
             external: clientExternals,
             define: {
                 ...shared.define,
@@ -414,16 +574,10 @@ async function buildStandalone(resourcePath, outDir, options = {}) {
         }))
     }
 
-    const manifestSrc = path.join(resourcePath, 'fxmanifest.lua')
-    const manifestDst = path.join(layout.serverOutDir, 'fxmanifest.lua')
-    if (layout.manifestKind === 'fxmanifest' && fs.existsSync(manifestSrc)) {
-        await fs.promises.copyFile(manifestSrc, manifestDst)
-    }
-
     if (builds.length > 0) await Promise.all(builds)
     const dependencyOptions = optionsWithServerExternals(options, Array.from(usedServerExternals))
     if (shouldHandleDependencies(dependencyOptions)) {
-        await handleDependencies(resourcePath, layout.serverOutDir, dependencyOptions)
+        await timePhase('dependencies', () => handleDependencies(resourcePath, layout.serverOutDir, dependencyOptions))
     } else {
         await cleanupDependencyArtifacts(layout.serverOutDir)
     }
@@ -438,7 +592,7 @@ async function copyResource(resourcePath, outDir, options = {}) {
 
     if (absSrcPath === absOutDir) {
         if (shouldHandleDependencies(options)) {
-            await handleDependencies(resourcePath, outDir, options)
+            await timePhase('dependencies', () => handleDependencies(resourcePath, outDir, options))
         }
         return
     }
@@ -461,13 +615,13 @@ async function copyResource(resourcePath, outDir, options = {}) {
             }
             await copyDirRecursive(src, dst)
         } else {
-            if (entry.name === 'package.json') continue 
+            if (entry.name === 'package.json' || entry.name === 'opencore.resource.ts' || entry.name === 'opencore.resource.json') continue
             await fs.promises.copyFile(src, dst)
         }
     }
 
     if (shouldHandleDependencies(options)) {
-        await handleDependencies(resourcePath, outDir, options)
+        await timePhase('dependencies', () => handleDependencies(resourcePath, outDir, options))
     }
 
     const copyServerEntry = resolveEntry(resourcePath, 'server', options.entryPoints?.server)
@@ -496,5 +650,10 @@ module.exports = {
     buildCore,
     buildResource,
     buildStandalone,
-    copyResource
+    copyResource,
+    setIncrementalScope,
+    disposeIncrementalContexts,
+    takeDiagnostics,
+    takeInputs,
+    takePhases
 }
@@ -7,7 +7,8 @@ let clientExternalsWarningShown = false
 function getSharedConfig(options = {}) {
     const config = {
         bundle: true,
-        sourcemap: options.sourceMaps ? 'inline' : false,
+        // Dev mode keeps maps out of the bundle but next to it, for stack traces
+        sourcemap: options.sourceMaps ? 'inline' : (options.devSourceMaps ? 'external' : false),
         minifyWhitespace: options.minify !== false,
         minifySyntax: options.minify !== false,
         minifyIdentifiers: false,
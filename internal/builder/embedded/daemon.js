@@ -0,0 +1,140 @@
+const readline = require('readline')
+const util = require('util')
+const { buildSingle, checkBaseDependencies, reportDiagnostics, reportInputs, reportPhases } = require('./build')
+const { setIncrementalScope, disposeIncrementalContexts, takeDiagnostics, takeInputs, takePhases } = require('./build_functions')
+
+// =============================================================================
+// Build daemon: a long-lived worker driven by the Go CLI over stdio.
+//
+// Requests and responses are JSON-RPC 2.0 messages, one per line. stdout is
+// reserved for responses, so anything the build prints is captured and
+// returned as the `output` of the request that produced it.
+// =============================================================================
+
+const PROTOCOL_VERSION = 1
+
+const writeMessage = process.stdout.write.bind(process.stdout)
+
+let captured = null
+
+function capture(chunk) {
+    if (captured !== null) {
+        captured.push(typeof chunk === 'string' ? chunk : chunk.toString())
+    }
+    return true
+}
+
+for (const stream of [process.stdout, process.stderr]) {
+    stream.write = (chunk, encoding, callback) => {
+        capture(chunk)
+        if (typeof encoding === 'function') encoding()
+        else if (typeof callback === 'function') callback()
+        return true
+    }
+}
+
+for (const method of ['log', 'info', 'warn', 'error', 'debug']) {
+    console[method] = (...args) => {
+        capture(util.format(...args) + '\n')
+    }
+}
+
+function send(message) {
+    writeMessage(JSON.stringify({ jsonrpc: '2.0', ...message }) + '\n')
+}
+
+class RPCError extends Error {
+    constructor(code, message, data) {
+        super(message)
+        this.code = code
+        this.data = data
+    }
+}
+
+const methods = {
+    initialize(params = {}) {
+        if (params.protocolVersion !== PROTOCOL_VERSION) {
+            throw new RPCError(-32001, `Unsupported protocol version ${params.protocolVersion}, expected ${PROTOCOL_VERSION}`)
+        }
+        return { protocolVersion: PROTOCOL_VERSION, pid: process.pid }
+    },
+
+    async build(params = {}) {
+        const { type, resourcePath, outDir, options = {}, task } = params
+        checkBaseDependencies(options)
+        takeDiagnostics()
+        takeInputs()
+        takePhases()
+        setIncrementalScope(task ? { task, signature: JSON.stringify([type, resourcePath, outDir, options]) } : null)
+        try {
+            await buildSingle(type, resourcePath, outDir, options)
+            reportPhases()
+            reportInputs()
+        } finally {
+            setIncrementalScope(null)
+            // Diagnostics travel in the output, as they do for one-shot builds.
+            reportDiagnostics()
+        }
+        return {}
+    },
+
+    async dispose(params = {}) {
+        await disposeIncrementalContexts(params.task)
+        return {}
+    },
+
+    async shutdown() {
+        await disposeIncrementalContexts()
+        setImmediate(() => process.exit(0))
+        return {}
+    },
+}
+
+async function handle(request) {
+    const method = methods[request.method]
+    if (!method) {
+        throw new RPCError(-32601, `Unknown method: ${request.method}`)
+    }
+    return method(request.params)
+}
+
+let queue = Promise.resolve()
+
+function dispatch(line) {
+    if (!line.trim()) return
+
+    let request
+    try {
+        request = JSON.parse(line)
+    } catch (error) {
+        send({ id: null, error: { code: -32700, message: `Parse error: ${error.message}` } })
+        return
+    }
+
+    // Requests run one at a time so captured output belongs to one request.
+    queue = queue.then(async () => {
+        captured = []
+        try {
+            const result = await handle(request)
+            send({ id: request.id, result: { ...result, output: captured.join('') } })
+        } catch (error) {
+            send({
+                id: request.id,
+                error: {
+                    code: error.code || -32000,
+                    message: error.message,
+                    data: { output: captured.join('') },
+                },
+            })
+        } finally {
+            captured = null
+        }
+    })
+}
+
+const input = readline.createInterface({ input: process.stdin, crlfDelay: Infinity })
+input.on('line', dispatch)
+input.on('close', () => {
+    // The CLI exited or closed the pipe: finish pending work and stop.
+    queue.then(() => disposeIncrementalContexts()).finally(() => process.exit(0))
+})
@@ -302,7 +302,9 @@ function stableHash(value) {
     return crypto.createHash('sha256').update(JSON.stringify(value)).digest('hex').slice(0, 24)
 }
 
-function dependencyCacheRoot() {
+function dependencyCacheRoot(options = {}) {
+    // The projects of a workspace share one cache.
+    if (options.dependencyCacheDir) return options.dependencyCacheDir
     return path.join(process.cwd(), 'node_modules', '.cache', 'opencore', 'dependencies')
 }
 
@@ -444,7 +446,7 @@ async function installIsolatedDependencies(resourcePath, outDir, options = {}) {
     if (fs.existsSync(nodeModules)) await fs.promises.rm(nodeModules, { recursive: true, force: true })
 
     if (useCache) {
-        const cacheDir = path.join(dependencyCacheRoot(), cacheKey)
+        const cacheDir = path.join(dependencyCacheRoot(options), cacheKey)
         const cacheReady = path.join(cacheDir, '.ready')
 
         await withCacheLock(cacheDir, async () => {
@@ -545,7 +547,7 @@ async function generateSharedDependencyResource(outDir, options = {}) {
         if (fs.existsSync(nodeModules)) await fs.promises.rm(nodeModules, { recursive: true, force: true })
         const useCache = options.cache !== false
         if (useCache) {
-            const cacheDir = path.join(dependencyCacheRoot(), cacheKey)
+            const cacheDir = path.join(dependencyCacheRoot(options), cacheKey)
             const cacheReady = path.join(cacheDir, '.ready')
 
             await withCacheLock(cacheDir, async () => {
@@ -17,6 +17,12 @@ func TestEmbeddedBuildScript(t *testing.T) {
 	if !strings.Contains(entryScript, "require('./build_functions')") {
 		t.Error("build.js missing build_functions require")
 	}
+	if !strings.Contains(entryScript, "::opencore-diagnostics::") {
+		t.Error("build.js missing the diagnostics marker")
+	}
+	if !strings.Contains(entryScript, "::opencore-phases::") {
+		t.Error("build.js missing the phases marker")
+	}
 
 	// 2. Check plugins.js
 	pluginsScript, _ := BuildFS.ReadFile("plugins.js")
@@ -54,6 +60,10 @@ func TestEmbeddedBuildScript(t *testing.T) {
 		"function optionsWithServerExternals",
 		"function esbuildExternals",
 		"createEnvironmentAliasPlugin",
+		"function setIncrementalScope",
+		"function disposeIncrementalContexts",
+		"function takeDiagnostics",
+		"function takePhases",
 	}
 	for _, fn := range requiredFunctions {
 		if !strings.Contains(buildFuncsContent, fn) {
@@ -89,7 +99,16 @@ func TestEmbeddedBuildScript(t *testing.T) {
 		t.Error("views.js should pass the absolute local vite config path to vite")
 	}
 
-	// 5. Check dependency installer layout for FXServer sandbox compatibility.
+	// 5. Check the build daemon speaks the protocol version the CLI expects.
+	daemonScript, _ := BuildFS.ReadFile("daemon.js")
+	daemonContent := string(daemonScript)
+	for _, symbol := range []string{"const PROTOCOL_VERSION = 1", "initialize(", "async build(", "async dispose(", "async shutdown("} {
+		if !strings.Contains(daemonContent, symbol) {
+			t.Errorf("daemon.js missing required symbol: %s", symbol)
+		}
+	}
+
+	// 6. Check dependency installer layout for FXServer sandbox compatibility.
 	depsScript, _ := BuildFS.ReadFile("dependencies.js")
 	depsContent := string(depsScript)
 	if !strings.Contains(depsContent, "--config.node-linker=hoisted") {
@@ -218,10 +237,13 @@ async function main() {
     environmentAliases: { '@opencore/environment': path.join(resourceDir, 'env.ts') },
     server: { platform: 'node', format: 'cjs', target: 'es2020', external: ['unused-external'] },
     client: false,
+    metafile: { server: path.join(scriptDir, 'meta', 'resource.server.json') },
   })
   const output = fs.readFileSync(path.join(outDir, 'server.js'), 'utf8')
   if (!output.includes('merged-env')) throw new Error('environment alias was not bundled')
   if (fs.existsSync(path.join(outDir, 'node_modules'))) throw new Error('unused external should not install node_modules')
+  const meta = JSON.parse(fs.readFileSync(path.join(scriptDir, 'meta', 'resource.server.json'), 'utf8'))
+  if (!Object.keys(meta.inputs).some(input => input.endsWith('server.ts'))) throw new Error('metafile was not written')
 }
 main().catch(err => { console.error(err.stack || err.message); process.exit(1) })
 `), 0644); err != nil {
@@ -691,6 +691,38 @@ function createEnvironmentAliasPlugin(aliases) {
     }
 }
 
+/**
+ * Resolves @modules/<name> imports, and subpaths such as
+ * @modules/<name>/items, to the shared module directories from
+ * opencore.config.ts. The modules are bundled like the resource's own sources.
+ */
+function createModuleAliasPlugin(modules) {
+    if (!modules || Object.keys(modules).length === 0) {
+        return null
+    }
+    return {
+        name: 'opencore-modules',
+        setup(build) {
+            build.onResolve({ filter: /^@modules\// }, async (args) => {
+                const rest = args.path.slice('@modules/'.length)
+                const slash = rest.indexOf('/')
+                const name = slash === -1 ? rest : rest.slice(0, slash)
+                const dir = modules[name]
+                if (!dir) {
+                    return { errors: [{ text: `Unknown module "@modules/${name}". Add its directory to modules in opencore.config.ts.` }] }
+                }
+
+                const subpath = slash === -1 ? '.' : `./${rest.slice(slash + 1)}`
+                const result = await build.resolve(subpath, { kind: args.kind, importer: args.importer, resolveDir: dir })
+                if (result.errors.length > 0) {
+                    return { errors: result.errors }
+                }
+                return { path: result.path, namespace: result.namespace, external: result.external, sideEffects: result.sideEffects }
+            })
+        }
+    }
+}
+
 module.exports = {
     getEsbuild,
     createSwcPlugin,
@@ -704,4 +736,5 @@ module.exports = {
     createAutoloadDynamicImportShimPlugin,
     createAutoloadControllersRedirectPlugin,
     createEnvironmentAliasPlugin,
+    createModuleAliasPlugin,
 }
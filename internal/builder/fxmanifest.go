@@ -0,0 +1,234 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const redmWarning = "I acknowledge that this is a prerelease build of RedM, and I am aware my resources *will* become incompatible once RedM ships."
+
+// generatedManifestDirectives are derived from the build layout. They are
+// stripped from the source manifest before it is appended as the overlay.
+var generatedManifestDirectives = map[string]bool{
+	"fx_version":     true,
+	"game":           true,
+	"games":          true,
+	"rdr3_warning":   true,
+	"node_version":   true,
+	"server_script":  true,
+	"server_scripts": true,
+	"client_script":  true,
+	"client_scripts": true,
+	"ui_page":        true,
+}
+
+var manifestBlankLines = regexp.MustCompile(`\n[ \t]*\n(?:[ \t]*\n)+`)
+
+// writeManifests generates fxmanifest.lua for every successfully built
+// resource whose runtime uses one.
+func (b *Builder) writeManifests(results []BuildResult) error {
+	for _, result := range results {
+		if !result.Success || result.Skipped {
+			continue
+		}
+		switch result.Task.Type {
+		case TypeCore, TypeResource, TypeStandalone:
+		default:
+			continue
+		}
+		layout := b.resourceLayout(result.Task.ResourceName)
+		if layout.ManifestKind != "fxmanifest" || (layout.Runtime != "fivem" && layout.Runtime != "redm") {
+			continue
+		}
+		if err := writeFxManifest(result.Task, layout); err != nil {
+			return fmt.Errorf("failed to generate fxmanifest.lua for %s: %w", result.Task.ResourceName, err)
+		}
+	}
+	return nil
+}
+
+func writeFxManifest(task BuildTask, layout resourceBuildLayout) error {
+	source := ""
+	content, err := os.ReadFile(filepath.Join(task.Path, "fxmanifest.lua"))
+	if err == nil {
+		source = string(content)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	manifest, err := generateFxManifest(task, layout, source)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(layout.ServerOutDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(layout.ServerOutDir, "fxmanifest.lua"), []byte(manifest), 0644)
+}
+
+// generateFxManifest renders the manifest for a built resource. Scripts, game,
+// node_version and the UI page come from the build layout; everything else in
+// the source manifest (metadata, dependencies, shared scripts, files, custom
+// Lua) is kept verbatim as the overlay section.
+func generateFxManifest(task BuildTask, layout resourceBuildLayout, source string) (string, error) {
+	user := parseFxManifest(source)
+
+	var serverScripts, clientScripts []string
+	if task.Options.Server.Enabled {
+		script, err := manifestPath(layout.ServerOutDir, filepath.Join(layout.ServerOutDir, layout.ServerOutFile))
+		if err != nil {
+			return "", err
+		}
+		serverScripts = append(serverScripts, script)
+	}
+	if task.Options.Client.Enabled {
+		script, err := manifestPath(layout.ServerOutDir, filepath.Join(layout.ClientOutDir, layout.ClientOutFile))
+		if err != nil {
+			return "", err
+		}
+		clientScripts = append(clientScripts, script)
+	}
+	serverScripts = appendManifestScripts(serverScripts, user.Values("server_script", "server_scripts"), "server.js", layout.ServerOutFile)
+	clientScripts = appendManifestScripts(clientScripts, user.Values("client_script", "client_scripts"), "client.js", layout.ClientOutFile)
+
+	uiPage := lastValue(user.Values("ui_page"))
+	var uiFiles []string
+	if _, err := os.Stat(filepath.Join(layout.ViewsOutDir, "index.html")); err == nil {
+		viewsDir, err := manifestPath(layout.ServerOutDir, layout.ViewsOutDir)
+		if err != nil {
+			return "", err
+		}
+		uiPage = viewsDir + "/index.html"
+		if pattern := viewsDir + "/**/*"; !containsString(user.Values("file", "files"), pattern) {
+			uiFiles = append(uiFiles, pattern)
+		}
+	}
+
+	fxVersion := lastValue(user.Values("fx_version"))
+	if fxVersion == "" {
+		fxVersion = "cerulean"
+	}
+	game := "gta5"
+	if layout.Runtime == "redm" {
+		game = "rdr3"
+	}
+	nodeVersion := lastValue(user.Values("node_version"))
+	if nodeVersion == "" && len(serverScripts) > 0 {
+		nodeVersion = "22"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("-- Generated by OpenCore CLI from the build layout. Do not edit this file:\n")
+	sb.WriteString("-- directives added to the resource's fxmanifest.lua are kept in the section below.\n")
+	fmt.Fprintf(&sb, "fx_version %s\n", luaString(fxVersion))
+	fmt.Fprintf(&sb, "game %s\n", luaString(game))
+	if game == "rdr3" {
+		warning := lastValue(user.Values("rdr3_warning"))
+		if warning == "" {
+			warning = redmWarning
+		}
+		fmt.Fprintf(&sb, "rdr3_warning %s\n", luaString(warning))
+	}
+	if nodeVersion != "" {
+		fmt.Fprintf(&sb, "node_version %s\n", luaString(nodeVersion))
+	}
+	writeManifestList(&sb, "server_scripts", serverScripts)
+	writeManifestList(&sb, "client_scripts", clientScripts)
+	if uiPage != "" {
+		fmt.Fprintf(&sb, "\nui_page %s\n", luaString(uiPage))
+	}
+	writeManifestList(&sb, "files", uiFiles)
+
+	if dep := sharedDependencyOf(task); dep != "" && !containsString(user.Dependencies(), dep) {
+		fmt.Fprintf(&sb, "\ndependency %s\n", luaString(dep))
+	}
+
+	if overlay := manifestOverlay(source); overlay != "" {
+		sb.WriteString("\n-- Resource manifest overlay\n")
+		sb.WriteString(overlay)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// manifestOverlay returns the source manifest without the generated directives.
+func manifestOverlay(source string) string {
+	runes := []rune(source)
+	var sb strings.Builder
+	last := 0
+	for _, stmt := range fxManifestStatements(source) {
+		if !generatedManifestDirectives[stmt.Name] {
+			continue
+		}
+		sb.WriteString(string(runes[last:stmt.Start]))
+		last = stmt.End
+	}
+	sb.WriteString(string(runes[last:]))
+
+	overlay := strings.ReplaceAll(sb.String(), "\r\n", "\n")
+	overlay = manifestBlankLines.ReplaceAllString(overlay, "\n\n")
+	return strings.TrimSpace(overlay)
+}
+
+// appendManifestScripts adds the user's extra scripts, dropping the default
+// bundle names that the generated entries replace.
+func appendManifestScripts(scripts, extra []string, replaced ...string) []string {
+	for _, script := range extra {
+		if containsString(replaced, script) || containsString(scripts, script) {
+			continue
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts
+}
+
+func sharedDependencyOf(task BuildTask) string {
+	if dependencyResolutionMode(task.Options) != "shared-resource" || len(serverExternalsFromTask(task)) == 0 {
+		return ""
+	}
+	return sharedResourceName(task.Options)
+}
+
+func manifestPath(base, target string) (string, error) {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func writeManifestList(sb *strings.Builder, directive string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n%s {\n", directive)
+	for _, value := range values {
+		fmt.Fprintf(sb, "    %s,\n", luaString(value))
+	}
+	sb.WriteString("}\n")
+}
+
+func luaString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+func lastValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
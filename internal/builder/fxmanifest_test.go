@@ -0,0 +1,163 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+const chatSourceManifest = `fx_version 'bodacious'
+game 'gta5'
+
+name 'chat'
+version '1.2.0'
+node_version '22'
+
+server_scripts {
+    'server.js'
+}
+client_scripts { 'client.js', 'vendor/legacy.lua' }
+shared_script '@ox_lib/init.lua'
+
+ui_page 'ui/index.html'
+
+files {
+    'ui/**/*'
+}
+
+dependencies {
+    'core'
+}
+`
+
+func TestGenerateFxManifest(t *testing.T) {
+	outDir := t.TempDir()
+	layout := resourceBuildLayout{
+		Runtime:       "fivem",
+		ManifestKind:  "fxmanifest",
+		ServerOutDir:  outDir,
+		ClientOutDir:  outDir,
+		ViewsOutDir:   filepath.Join(outDir, "ui"),
+		ServerOutFile: "server.js",
+		ClientOutFile: "client.js",
+	}
+	task := BuildTask{
+		ResourceName: "chat",
+		Type:         TypeResource,
+		Options: BuildOptions{
+			Server:               SideConfigValue{Enabled: true, Options: &BuildSideOptions{External: []string{"typeorm"}}},
+			Client:               SideConfigValue{Enabled: true},
+			DependencyResolution: &DependencyResolutionConfig{Mode: "shared-resource", SharedResourceName: "__deps"},
+		},
+	}
+
+	manifest, err := generateFxManifest(task, layout, chatSourceManifest)
+	if err != nil {
+		t.Fatalf("generateFxManifest failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"fx_version 'bodacious'\ngame 'gta5'\nnode_version '22'\n",
+		"server_scripts {\n    'server.js',\n}",
+		"client_scripts {\n    'client.js',\n    'vendor/legacy.lua',\n}",
+		"ui_page 'ui/index.html'",
+		"dependency '__deps'",
+		"-- Resource manifest overlay\nname 'chat'\nversion '1.2.0'\n\nshared_script '@ox_lib/init.lua'\n\nfiles {",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("expected manifest to contain %q, got:\n%s", want, manifest)
+		}
+	}
+	if strings.Count(manifest, "ui_page") != 1 {
+		t.Errorf("expected the user's ui_page to be kept once, got:\n%s", manifest)
+	}
+
+	parsed := parseFxManifest(manifest)
+	if got := parsed.Values("server_script", "server_scripts"); len(got) != 1 {
+		t.Errorf("expected a single server script, got %v", got)
+	}
+	if got := parsed.Dependencies(); strings.Join(got, ",") != "__deps,core" {
+		t.Errorf("unexpected dependencies: %v", got)
+	}
+}
+
+func TestGenerateFxManifestLayout(t *testing.T) {
+	outDir := t.TempDir()
+	layout := resourceBuildLayout{
+		Runtime:       "redm",
+		ManifestKind:  "fxmanifest",
+		ServerOutDir:  filepath.Join(outDir, "server", "chat"),
+		ClientOutDir:  filepath.Join(outDir, "client", "chat"),
+		ViewsOutDir:   filepath.Join(outDir, "client", "chat", "ui"),
+		ServerOutFile: "main.js",
+		ClientOutFile: "main.js",
+	}
+	if err := os.MkdirAll(layout.ViewsOutDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(layout.ViewsOutDir, "index.html"), []byte("<html></html>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	task := BuildTask{
+		ResourceName: "chat",
+		Type:         TypeResource,
+		Options:      BuildOptions{Client: SideConfigValue{Enabled: true}},
+	}
+
+	manifest, err := generateFxManifest(task, layout, "")
+	if err != nil {
+		t.Fatalf("generateFxManifest failed: %v", err)
+	}
+	for _, want := range []string{
+		"fx_version 'cerulean'\ngame 'rdr3'\nrdr3_warning 'I acknowledge",
+		"client_scripts {\n    '../../client/chat/main.js',\n}",
+		"ui_page '../../client/chat/ui/index.html'",
+		"files {\n    '../../client/chat/ui/**/*',\n}",
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("expected manifest to contain %q, got:\n%s", want, manifest)
+		}
+	}
+	for _, unwanted := range []string{"server_scripts", "node_version", "overlay"} {
+		if strings.Contains(manifest, unwanted) {
+			t.Errorf("did not expect %q in manifest:\n%s", unwanted, manifest)
+		}
+	}
+}
+
+func TestWriteManifestsSkipsViewsAndFailures(t *testing.T) {
+	root := t.TempDir()
+	resourceDir := filepath.Join(root, "resources", "chat")
+	if err := os.MkdirAll(resourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(resourceDir, "fxmanifest.lua"), []byte(chatSourceManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(&config.Config{OutDir: filepath.Join(root, "build")})
+	results := []BuildResult{
+		{Task: BuildTask{Path: resourceDir, ResourceName: "chat", Type: TypeResource, Options: BuildOptions{Server: SideConfigValue{Enabled: true}}}, Success: true},
+		{Task: BuildTask{Path: resourceDir, ResourceName: "chat/ui", Type: TypeViews}, Success: true},
+		{Task: BuildTask{Path: resourceDir, ResourceName: "broken", Type: TypeResource}, Success: false},
+	}
+	if err := b.writeRuntimeArtifacts(results); err != nil {
+		t.Fatalf("writeRuntimeArtifacts failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "build", "chat", "fxmanifest.lua"))
+	if err != nil {
+		t.Fatalf("expected generated manifest: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "-- Generated by OpenCore CLI") {
+		t.Errorf("unexpected manifest:\n%s", content)
+	}
+	for _, dir := range []string{filepath.Join("chat", "ui"), "broken"} {
+		if _, err := os.Stat(filepath.Join(root, "build", dir, "fxmanifest.lua")); !os.IsNotExist(err) {
+			t.Errorf("did not expect a manifest in %s", dir)
+		}
+	}
+}
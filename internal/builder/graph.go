@@ -0,0 +1,443 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TaskGraph orders build tasks by the dependencies their resources declare.
+// Edges are drawn between base resources, so a resource's views task shares
+// the dependencies of the resource itself.
+type TaskGraph struct {
+	tasks      []BuildTask
+	deps       [][]int
+	dependents [][]int
+	excluded   []bool        // skipped before the build started
+	skipped    []BuildResult // results of the excluded tasks
+}
+
+// FailurePolicy decides what happens to pending tasks once a task fails.
+// Dependents of a failed task are skipped under either policy.
+type FailurePolicy string
+
+const (
+	// KeepGoing builds every task that does not depend on a failed task.
+	KeepGoing FailurePolicy = "keep-going"
+	// FailFast starts no new task after the first failure.
+	FailFast FailurePolicy = "fail-fast"
+)
+
+// ErrDependencyCycle is returned when resources depend on each other.
+type ErrDependencyCycle struct {
+	Cycle []string
+}
+
+func (e *ErrDependencyCycle) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
+
+func baseResourceName(resourceName string) string {
+	return strings.Split(resourceName, "/")[0]
+}
+
+// declaredDependencies returns the resource names a task's resource depends
+// on, read from its fxmanifest.lua dependencies, oc.manifest.json
+// requires.templates and the shared dependency resource it imports from.
+func declaredDependencies(task BuildTask) []string {
+	if task.Type == TypeViews {
+		return nil
+	}
+
+	var deps []string
+	if manifest, err := readFxManifest(filepath.Join(task.Path, "fxmanifest.lua")); err == nil {
+		deps = append(deps, manifest.Dependencies()...)
+	}
+	deps = append(deps, resourceManifestRequires(task.Path)...)
+	if usesSharedResource(task) {
+		deps = append(deps, sharedResourceName(task.Options))
+	}
+	return deps
+}
+
+// usesSharedResource reports whether a task imports its server externals
+// from the shared dependency resource.
+func usesSharedResource(task BuildTask) bool {
+	return dependencyResolutionMode(task.Options) == "shared-resource" && len(serverExternalsFromTask(task)) > 0
+}
+
+// NewTaskGraph builds the dependency graph for tasks. Dependencies on
+// resources outside tasks (e.g. oxmysql, or resources not being rebuilt) are
+// ignored. A cycle is reported as *ErrDependencyCycle.
+func NewTaskGraph(tasks []BuildTask) (*TaskGraph, error) {
+	return newTaskGraph(tasks, declaredDependencies)
+}
+
+func newTaskGraph(tasks []BuildTask, dependenciesOf func(BuildTask) []string) (*TaskGraph, error) {
+	g := &TaskGraph{
+		tasks:      tasks,
+		deps:       make([][]int, len(tasks)),
+		dependents: make([][]int, len(tasks)),
+		excluded:   make([]bool, len(tasks)),
+	}
+
+	// Resources can be referenced by resource name or by source folder name
+	// (template names in oc.manifest.json match the folder).
+	byName := make(map[string]string)
+	tasksByBase := make(map[string][]int)
+	for i, task := range tasks {
+		base := baseResourceName(task.ResourceName)
+		tasksByBase[base] = append(tasksByBase[base], i)
+		byName[base] = base
+	}
+	for _, task := range tasks {
+		if task.Type == TypeViews {
+			continue
+		}
+		folder := filepath.Base(filepath.Clean(task.Path))
+		if _, exists := byName[folder]; !exists {
+			byName[folder] = baseResourceName(task.ResourceName)
+		}
+	}
+
+	baseDeps := make(map[string]map[string]struct{})
+	for _, task := range tasks {
+		base := baseResourceName(task.ResourceName)
+		for _, dep := range dependenciesOf(task) {
+			target, ok := byName[strings.TrimSpace(dep)]
+			if !ok || target == base {
+				continue
+			}
+			if baseDeps[base] == nil {
+				baseDeps[base] = make(map[string]struct{})
+			}
+			baseDeps[base][target] = struct{}{}
+		}
+	}
+
+	for i, task := range tasks {
+		targets := make([]string, 0, len(baseDeps[baseResourceName(task.ResourceName)]))
+		for target := range baseDeps[baseResourceName(task.ResourceName)] {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			for _, j := range tasksByBase[target] {
+				g.deps[i] = append(g.deps[i], j)
+				g.dependents[j] = append(g.dependents[j], i)
+			}
+		}
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		return nil, &ErrDependencyCycle{Cycle: cycle}
+	}
+
+	return g, nil
+}
+
+func (g *TaskGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(g.tasks))
+	stack := make([]int, 0, len(g.tasks))
+
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		state[i] = visiting
+		stack = append(stack, i)
+		for _, dep := range g.deps[i] {
+			switch state[dep] {
+			case visiting:
+				var cycle []string
+				for k := len(stack) - 1; k >= 0; k-- {
+					cycle = append([]string{baseResourceName(g.tasks[stack[k]].ResourceName)}, cycle...)
+					if stack[k] == dep {
+						break
+					}
+				}
+				return append(cycle, baseResourceName(g.tasks[dep].ResourceName))
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range g.tasks {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Tasks returns the tasks of the graph in their original order.
+func (g *TaskGraph) Tasks() []BuildTask {
+	return g.tasks
+}
+
+// Dependencies returns the tasks the task at index i waits for.
+func (g *TaskGraph) Dependencies(i int) []BuildTask {
+	deps := make([]BuildTask, 0, len(g.deps[i]))
+	for _, j := range g.deps[i] {
+		deps = append(deps, g.tasks[j])
+	}
+	return deps
+}
+
+// Order returns the tasks in a topological order that keeps the original
+// order wherever dependencies allow it.
+func (g *TaskGraph) Order() []BuildTask {
+	ordered := make([]BuildTask, 0, len(g.tasks))
+	for _, i := range g.order() {
+		ordered = append(ordered, g.tasks[i])
+	}
+	return ordered
+}
+
+func (g *TaskGraph) order() []int {
+	remaining := make([]int, len(g.tasks))
+	for i := range g.tasks {
+		remaining[i] = len(g.deps[i])
+	}
+
+	done := make([]bool, len(g.tasks))
+	ordered := make([]int, 0, len(g.tasks))
+	for len(ordered) < len(g.tasks) {
+		for i := range g.tasks {
+			if done[i] || remaining[i] > 0 {
+				continue
+			}
+			done[i] = true
+			ordered = append(ordered, i)
+			for _, dependent := range g.dependents[i] {
+				remaining[dependent]--
+			}
+			break
+		}
+	}
+	return ordered
+}
+
+// Skip excludes the tasks matching skip, and their transitive dependents,
+// from the build, e.g. because something they need failed before the build
+// started. Schedule and Walk report them as skipped first.
+func (g *TaskGraph) Skip(skip func(BuildTask) bool, reason error) []BuildResult {
+	var skipped []BuildResult
+	for i, task := range g.tasks {
+		if g.excluded[i] || !skip(task) {
+			continue
+		}
+		g.excluded[i] = true
+		skipped = append(skipped, BuildResult{Task: task, Skipped: true, Error: reason})
+		skipped = append(skipped, g.skipDependents(i, g.excluded)...)
+	}
+	g.skipped = append(g.skipped, skipped...)
+	return skipped
+}
+
+// Schedule submits tasks to the pool as soon as all of their dependencies
+// built successfully and streams every result on the returned channel. When a
+// task fails, its transitive dependents are reported as skipped instead of
+// being built; with FailFast, every task not started yet is skipped too. At
+// most one task per worker is submitted at a time, so a stopped build leaves
+// nothing queued. The channel is closed once every task has a result.
+func (g *TaskGraph) Schedule(ctx context.Context, pool *WorkerPool, policy FailurePolicy) <-chan BuildResult {
+	out := make(chan BuildResult, len(g.tasks))
+
+	remaining := make([]int, len(g.tasks))
+	for i := range g.tasks {
+		remaining[i] = len(g.deps[i])
+	}
+	finished := append([]bool(nil), g.excluded...)
+	completed := 0
+	index := make(map[string]int, len(g.tasks))
+	for i, task := range g.tasks {
+		index[taskKey(task)] = i
+		if finished[i] {
+			completed++
+		}
+	}
+
+	ready := make([]int, 0, len(g.tasks))
+	for i := range g.tasks {
+		if remaining[i] == 0 && !finished[i] {
+			ready = append(ready, i)
+		}
+	}
+
+	go func() {
+		defer close(out)
+
+		for _, skipped := range g.skipped {
+			out <- skipped
+		}
+		inFlight := 0
+		stoppedBy := ""
+		for completed < len(g.tasks) {
+			for stoppedBy == "" && inFlight < pool.workers && len(ready) > 0 {
+				pool.Submit(g.tasks[ready[0]])
+				ready = ready[1:]
+				inFlight++
+			}
+			if inFlight == 0 {
+				for _, skipped := range g.skipRemaining(finished, stoppedBy) {
+					completed++
+					out <- skipped
+				}
+				return
+			}
+
+			var result BuildResult
+			select {
+			case <-ctx.Done():
+				return
+			case <-pool.Done():
+				return
+			case r, ok := <-pool.Results():
+				if !ok {
+					return
+				}
+				result = r
+			}
+
+			i, ok := index[taskKey(result.Task)]
+			if !ok || finished[i] {
+				continue
+			}
+			inFlight--
+			finished[i] = true
+			completed++
+			out <- result
+
+			if !result.Success {
+				for _, skipped := range g.skipDependents(i, finished) {
+					completed++
+					out <- skipped
+				}
+				if policy == FailFast && stoppedBy == "" {
+					stoppedBy = result.Task.ResourceName
+				}
+				continue
+			}
+
+			for _, dependent := range g.dependents[i] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 && !finished[dependent] {
+					ready = append(ready, dependent)
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Walk builds the tasks one at a time in dependency order with the same
+// failure policy as Schedule. Every result, built or skipped, is passed to
+// report and returned. A cancelled build stops with the context's error.
+func (g *TaskGraph) Walk(ctx context.Context, policy FailurePolicy, build func(BuildTask) BuildResult, report func(BuildResult)) ([]BuildResult, error) {
+	results := make([]BuildResult, 0, len(g.tasks))
+	emit := func(result BuildResult) {
+		results = append(results, result)
+		report(result)
+	}
+
+	for _, skipped := range g.skipped {
+		emit(skipped)
+	}
+	finished := append([]bool(nil), g.excluded...)
+	for _, i := range g.order() {
+		if finished[i] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result := build(g.tasks[i])
+		finished[i] = true
+		emit(result)
+		if result.Success {
+			continue
+		}
+		if errors.Is(result.Error, context.Canceled) {
+			return results, result.Error
+		}
+		for _, skipped := range g.skipDependents(i, finished) {
+			emit(skipped)
+		}
+		if policy == FailFast {
+			for _, skipped := range g.skipRemaining(finished, result.Task.ResourceName) {
+				emit(skipped)
+			}
+			break
+		}
+	}
+	return results, nil
+}
+
+// skipRemaining marks every unfinished task as finished and returns skipped
+// results for them, for a build stopped after failedTask failed.
+func (g *TaskGraph) skipRemaining(finished []bool, failedTask string) []BuildResult {
+	var skipped []BuildResult
+	for _, i := range g.order() {
+		if finished[i] {
+			continue
+		}
+		finished[i] = true
+		skipped = append(skipped, BuildResult{
+			Task:    g.tasks[i],
+			Skipped: true,
+			Error:   fmt.Errorf("skipped: build stopped after %s failed", failedTask),
+		})
+	}
+	return skipped
+}
+
+// drainScheduled waits for a cancelled schedule to stop submitting tasks so
+// the pool can be closed safely.
+func drainScheduled(scheduled <-chan BuildResult) {
+	for range scheduled {
+	}
+}
+
+// skipDependents marks every unfinished transitive dependent of task i as
+// finished and returns skipped results for them.
+func (g *TaskGraph) skipDependents(i int, finished []bool) []BuildResult {
+	var skipped []BuildResult
+	failed := g.tasks[i].ResourceName
+	queue := append([]int{}, g.dependents[i]...)
+	for len(queue) > 0 {
+		j := queue[0]
+		queue = queue[1:]
+		if finished[j] {
+			continue
+		}
+		finished[j] = true
+		skipped = append(skipped, BuildResult{
+			Task:    g.tasks[j],
+			Skipped: true,
+			Error:   fmt.Errorf("skipped: dependency %s failed", failed),
+		})
+		queue = append(queue, g.dependents[j]...)
+	}
+	return skipped
+}
+
+func taskKey(task BuildTask) string {
+	return string(task.Type) + "\x00" + task.ResourceName + "\x00" + task.Path
+}
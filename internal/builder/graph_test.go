@@ -0,0 +1,286 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func staticDependencies(deps map[string][]string) func(BuildTask) []string {
+	return func(task BuildTask) []string {
+		if task.Type == TypeViews {
+			return nil
+		}
+		return deps[task.ResourceName]
+	}
+}
+
+func TestParseFxManifestDependencies(t *testing.T) {
+	manifest := parseFxManifest(`
+fx_version 'cerulean'
+game 'gta5'
+
+-- dependency 'commented'
+--[[
+dependency 'block-commented'
+]]
+
+dependency "core"
+dependencies {
+    '/server:5181',
+    'chat', -- trailing comment
+    "oxmysql",
+}
+server_script 'server.js'
+client_scripts { 'client.js' }
+`)
+
+	deps := manifest.Dependencies()
+	expected := []string{"core", "chat", "oxmysql"}
+	if strings.Join(deps, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected dependencies %v, got %v", expected, deps)
+	}
+	if got := manifest.Values("server_script", "server_scripts"); len(got) != 1 || got[0] != "server.js" {
+		t.Fatalf("unexpected server scripts: %v", got)
+	}
+	if got := manifest.Values("client_scripts"); len(got) != 1 || got[0] != "client.js" {
+		t.Fatalf("unexpected client scripts: %v", got)
+	}
+}
+
+func TestDeclaredDependenciesReadsManifests(t *testing.T) {
+	resourcePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(resourcePath, "fxmanifest.lua"), []byte("dependency 'core'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(resourcePath, "oc.manifest.json"), []byte(`{"schemaVersion":1,"name":"chat","kind":"resource","requires":{"templates":["identity"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deps := declaredDependencies(BuildTask{Path: resourcePath, ResourceName: "chat", Type: TypeResource})
+	if strings.Join(deps, ",") != "core,identity" {
+		t.Fatalf("unexpected dependencies: %v", deps)
+	}
+}
+
+func TestTaskGraphOrder(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./resources/chat", ResourceName: "chat", Type: TypeResource},
+		{Path: "./resources/chat/ui", ResourceName: "chat/ui", Type: TypeViews},
+		{Path: "./resources/identity", ResourceName: "identity", Type: TypeResource},
+		{Path: "./core", ResourceName: "core", Type: TypeCore},
+	}
+
+	graph, err := newTaskGraph(tasks, staticDependencies(map[string][]string{
+		"chat":     {"identity", "oxmysql"},
+		"identity": {"core"},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, task := range graph.Order() {
+		names = append(names, task.ResourceName)
+	}
+	if strings.Join(names, ",") != "core,identity,chat,chat/ui" {
+		t.Fatalf("unexpected order: %v", names)
+	}
+}
+
+func TestTaskGraphResolvesFolderNames(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./resources/inventory", ResourceName: "oc-inventory", Type: TypeResource},
+		{Path: "./resources/shop", ResourceName: "shop", Type: TypeResource},
+	}
+
+	graph, err := newTaskGraph(tasks, staticDependencies(map[string][]string{"shop": {"inventory"}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deps := graph.Dependencies(1); len(deps) != 1 || deps[0].ResourceName != "oc-inventory" {
+		t.Fatalf("expected shop to depend on oc-inventory, got %v", deps)
+	}
+}
+
+func TestTaskGraphDetectsCycle(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./resources/a", ResourceName: "a", Type: TypeResource},
+		{Path: "./resources/b", ResourceName: "b", Type: TypeResource},
+		{Path: "./resources/c", ResourceName: "c", Type: TypeResource},
+	}
+
+	_, err := newTaskGraph(tasks, staticDependencies(map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a"},
+	}))
+
+	var cycleErr *ErrDependencyCycle
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Fatalf("unexpected cycle message: %v", err)
+	}
+}
+
+func TestTaskGraphScheduleSkipsDependentsOfFailedTasks(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./core", ResourceName: "core", Type: TypeCore},
+		{Path: "./resources/chat", ResourceName: "chat", Type: TypeResource},
+		{Path: "./resources/chat/ui", ResourceName: "chat/ui", Type: TypeViews},
+		{Path: "./resources/admin", ResourceName: "admin", Type: TypeResource},
+		{Path: "./resources/radio", ResourceName: "radio", Type: TypeResource},
+	}
+
+	graph, err := newTaskGraph(tasks, staticDependencies(map[string][]string{
+		"chat":  {"core"},
+		"admin": {"chat"},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pool := NewWorkerPool(2)
+	pool.StartWithContext(func(_ context.Context, task BuildTask) BuildResult {
+		time.Sleep(time.Millisecond)
+		return BuildResult{Task: task, Success: task.ResourceName != "core"}
+	})
+
+	results := make(map[string]BuildResult)
+	for result := range graph.Schedule(context.Background(), pool, KeepGoing) {
+		results[result.Task.ResourceName] = result
+	}
+	pool.Close()
+
+	if len(results) != len(tasks) {
+		t.Fatalf("expected %d results, got %d", len(tasks), len(results))
+	}
+	if results["core"].Success || results["core"].Skipped {
+		t.Fatal("expected core to fail")
+	}
+	for _, name := range []string{"chat", "chat/ui", "admin"} {
+		if !results[name].Skipped {
+			t.Errorf("expected %s to be skipped", name)
+		}
+	}
+	if !strings.Contains(results["admin"].Error.Error(), "dependency core failed") {
+		t.Errorf("unexpected skip reason: %v", results["admin"].Error)
+	}
+	if !results["radio"].Success {
+		t.Error("expected unrelated resource to build")
+	}
+}
+
+func TestTaskGraphScheduleFailFastSkipsPendingTasks(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./core", ResourceName: "core", Type: TypeCore},
+		{Path: "./resources/chat", ResourceName: "chat", Type: TypeResource},
+		{Path: "./resources/admin", ResourceName: "admin", Type: TypeResource},
+		{Path: "./resources/radio", ResourceName: "radio", Type: TypeResource},
+	}
+	graph, err := newTaskGraph(tasks, staticDependencies(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pool := NewWorkerPool(1)
+	built := 0
+	pool.StartWithContext(func(_ context.Context, task BuildTask) BuildResult {
+		built++
+		return BuildResult{Task: task, Success: task.ResourceName != "chat"}
+	})
+
+	var results []BuildResult
+	for result := range graph.Schedule(context.Background(), pool, FailFast) {
+		results = append(results, result)
+	}
+	pool.Close()
+
+	if len(results) != len(tasks) || built != 2 {
+		t.Fatalf("expected 4 results from 2 builds, got %d results from %d builds", len(results), built)
+	}
+	for _, result := range results[2:] {
+		if !result.Skipped || !strings.Contains(result.Error.Error(), "build stopped after chat failed") {
+			t.Errorf("expected %s to be skipped by fail-fast, got %+v", result.Task.ResourceName, result)
+		}
+	}
+}
+
+func TestTaskGraphWalkAppliesFailurePolicy(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./core", ResourceName: "core", Type: TypeCore},
+		{Path: "./resources/chat", ResourceName: "chat", Type: TypeResource},
+		{Path: "./resources/radio", ResourceName: "radio", Type: TypeResource},
+	}
+	build := func(task BuildTask) BuildResult {
+		return BuildResult{Task: task, Success: task.ResourceName != "core", Error: errors.New("boom")}
+	}
+
+	for _, tc := range []struct {
+		policy FailurePolicy
+		radio  bool
+	}{
+		{KeepGoing, true},
+		{FailFast, false},
+	} {
+		graph, err := newTaskGraph(tasks, staticDependencies(map[string][]string{"chat": {"core"}}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var reported []string
+		results, err := graph.Walk(context.Background(), tc.policy, build, func(r BuildResult) {
+			reported = append(reported, r.Task.ResourceName)
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.policy, err)
+		}
+		if len(results) != len(tasks) || len(reported) != len(tasks) {
+			t.Fatalf("%s: expected every task to have a result, got %v", tc.policy, reported)
+		}
+		byName := make(map[string]BuildResult)
+		for _, r := range results {
+			byName[r.Task.ResourceName] = r
+		}
+		if !byName["chat"].Skipped || !strings.Contains(byName["chat"].Error.Error(), "dependency core failed") {
+			t.Errorf("%s: expected chat to be skipped after core failed, got %+v", tc.policy, byName["chat"])
+		}
+		if byName["radio"].Success != tc.radio || byName["radio"].Skipped == tc.radio {
+			t.Errorf("%s: unexpected radio result %+v", tc.policy, byName["radio"])
+		}
+	}
+}
+
+func TestTaskGraphSkipExcludesDependents(t *testing.T) {
+	tasks := []BuildTask{
+		{Path: "./resources/chat", ResourceName: "chat", Type: TypeResource},
+		{Path: "./resources/admin", ResourceName: "admin", Type: TypeResource},
+		{Path: "./resources/radio", ResourceName: "radio", Type: TypeResource},
+	}
+	graph, err := newTaskGraph(tasks, staticDependencies(map[string][]string{"admin": {"chat"}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	skipped := graph.Skip(func(task BuildTask) bool { return task.ResourceName == "chat" }, errors.New("skipped: dependency shared failed"))
+	if len(skipped) != 2 {
+		t.Fatalf("expected chat and admin to be skipped, got %+v", skipped)
+	}
+
+	var built []string
+	results, err := graph.Walk(context.Background(), KeepGoing, func(task BuildTask) BuildResult {
+		built = append(built, task.ResourceName)
+		return BuildResult{Task: task, Success: true}
+	}, func(BuildResult) {})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(built) != 1 || built[0] != "radio" || len(results) != 3 {
+		t.Fatalf("expected only radio to build, built %v with %d results", built, len(results))
+	}
+}
@@ -0,0 +1,242 @@
+package builder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// fxManifest holds the directives read from an fxmanifest.lua file. Singular
+// and plural forms are kept under the key they were written with, in order.
+type fxManifest struct {
+	Directives map[string][]string
+}
+
+// Values returns every value declared for the given directive names.
+func (m *fxManifest) Values(names ...string) []string {
+	if m == nil {
+		return nil
+	}
+	var values []string
+	for _, name := range names {
+		values = append(values, m.Directives[name]...)
+	}
+	return values
+}
+
+// Dependencies returns the resources declared with dependency/dependencies,
+// skipping runtime constraints such as '/server:5181' or '/onesync'.
+func (m *fxManifest) Dependencies() []string {
+	var deps []string
+	for _, dep := range m.Values("dependency", "dependencies") {
+		dep = strings.TrimSpace(dep)
+		if dep == "" || strings.HasPrefix(dep, "/") {
+			continue
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+func readFxManifest(path string) (*fxManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFxManifest(string(content)), nil
+}
+
+type manifestTokenKind int
+
+const (
+	manifestTokenIdent manifestTokenKind = iota
+	manifestTokenString
+	manifestTokenSymbol
+)
+
+type manifestToken struct {
+	kind  manifestTokenKind
+	value string
+	start int // rune offset of the token
+	end   int // rune offset just past the token
+}
+
+// fxManifestStatement is a single `key value` or `key { values }` directive
+// and its rune span in the source, including a trailing `)` for call syntax.
+type fxManifestStatement struct {
+	Name   string
+	Values []string
+	Start  int
+	End    int
+}
+
+// parseFxManifest reads the declarative subset of Lua used by fxmanifest.lua:
+// `key 'value'`, `key { 'a', 'b' }` and `key('value')`. Anything else, such as
+// arbitrary Lua expressions, is ignored.
+func parseFxManifest(content string) *fxManifest {
+	manifest := &fxManifest{Directives: make(map[string][]string)}
+	for _, stmt := range fxManifestStatements(content) {
+		manifest.Directives[stmt.Name] = append(manifest.Directives[stmt.Name], stmt.Values...)
+	}
+	return manifest
+}
+
+func fxManifestStatements(content string) []fxManifestStatement {
+	tokens := tokenizeFxManifest(content)
+	isSymbol := func(i int, value string) bool {
+		return i < len(tokens) && tokens[i].kind == manifestTokenSymbol && tokens[i].value == value
+	}
+
+	var statements []fxManifestStatement
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].kind != manifestTokenIdent {
+			continue
+		}
+		stmt := fxManifestStatement{Name: tokens[i].value, Values: []string{}, Start: tokens[i].start}
+		j := i + 1
+		call := isSymbol(j, "(")
+		if call {
+			j++
+		}
+		if j >= len(tokens) {
+			break
+		}
+
+		switch {
+		case tokens[j].kind == manifestTokenString:
+			stmt.Values = append(stmt.Values, tokens[j].value)
+			stmt.End = tokens[j].end
+		case isSymbol(j, "{"):
+			depth := 1
+			k := j + 1
+			for ; k < len(tokens) && depth > 0; k++ {
+				switch {
+				case isSymbol(k, "{"):
+					depth++
+				case isSymbol(k, "}"):
+					depth--
+				case tokens[k].kind == manifestTokenString && depth == 1:
+					stmt.Values = append(stmt.Values, tokens[k].value)
+				}
+			}
+			j = k - 1
+			stmt.End = tokens[j].end
+		default:
+			continue
+		}
+
+		if call && isSymbol(j+1, ")") {
+			j++
+			stmt.End = tokens[j].end
+		}
+		statements = append(statements, stmt)
+		i = j
+	}
+
+	return statements
+}
+
+func tokenizeFxManifest(content string) []manifestToken {
+	var tokens []manifestToken
+	runes := []rune(content)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			if end, ok := longBracketEnd(runes, i+2); ok {
+				i = end
+				continue
+			}
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '\'' || r == '"':
+			start := i
+			quote := r
+			var sb strings.Builder
+			i++
+			for i < len(runes) && runes[i] != quote && runes[i] != '\n' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			i++
+			tokens = append(tokens, manifestToken{kind: manifestTokenString, value: sb.String(), start: start, end: i})
+		case r == '[' && i+1 < len(runes) && (runes[i+1] == '[' || runes[i+1] == '='):
+			end, ok := longBracketEnd(runes, i)
+			if !ok {
+				tokens = append(tokens, manifestToken{kind: manifestTokenSymbol, value: "[", start: i, end: i + 1})
+				i++
+				continue
+			}
+			level := 0
+			for runes[i+1+level] == '=' {
+				level++
+			}
+			body := string(runes[i+2+level : end-2-level])
+			tokens = append(tokens, manifestToken{kind: manifestTokenString, value: strings.TrimPrefix(body, "\n"), start: i, end: end})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, manifestToken{kind: manifestTokenIdent, value: string(runes[start:i]), start: start, end: i})
+		case unicode.IsSpace(r):
+			i++
+		default:
+			tokens = append(tokens, manifestToken{kind: manifestTokenSymbol, value: string(r), start: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// longBracketEnd returns the index after the closing bracket of a Lua long
+// bracket ([[...]], [==[...]==]) starting at i.
+func longBracketEnd(runes []rune, i int) (int, bool) {
+	if i >= len(runes) || runes[i] != '[' {
+		return 0, false
+	}
+	level := 0
+	j := i + 1
+	for j < len(runes) && runes[j] == '=' {
+		level++
+		j++
+	}
+	if j >= len(runes) || runes[j] != '[' {
+		return 0, false
+	}
+	closing := "]" + strings.Repeat("=", level) + "]"
+	rest := string(runes[j+1:])
+	idx := strings.Index(rest, closing)
+	if idx < 0 {
+		return len(runes), true
+	}
+	return j + 1 + len([]rune(rest[:idx])) + len([]rune(closing)), true
+}
+
+// resourceManifestRequires reads requires.templates from a resource's
+// oc.manifest.json, if present.
+func resourceManifestRequires(resourcePath string) []string {
+	content, err := os.ReadFile(filepath.Join(resourcePath, "oc.manifest.json"))
+	if err != nil {
+		return nil
+	}
+
+	var manifest struct {
+		Requires *struct {
+			Templates []string `json:"templates"`
+		} `json:"requires"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil || manifest.Requires == nil {
+		return nil
+	}
+	return manifest.Requires.Templates
+}
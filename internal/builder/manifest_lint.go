@@ -0,0 +1,269 @@
+package builder
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+// ManifestIssue is a problem found in a built fxmanifest.lua.
+type ManifestIssue struct {
+	Resource  string `json:"resource"`
+	Directive string `json:"directive"`
+	Value     string `json:"value"`
+	Message   string `json:"message"`
+	Warning   bool   `json:"warning,omitempty"`
+}
+
+func (i ManifestIssue) String() string {
+	return fmt.Sprintf("[%s] %s '%s': %s", i.Resource, i.Directive, i.Value, i.Message)
+}
+
+// manifestFileDirectives list the directives whose values must resolve to
+// files inside the built resource.
+var manifestFileDirectives = []string{
+	"server_script", "server_scripts",
+	"client_script", "client_scripts",
+	"shared_script", "shared_scripts",
+	"file", "files",
+	"ui_page",
+}
+
+// LintManifests checks the output manifest of every resource in the project.
+// Resources that have not been built yet are skipped; the second return value
+// is the number of manifests checked.
+func (b *Builder) LintManifests() ([]ManifestIssue, int) {
+	return b.lintManifests(b.collectAllTasks())
+}
+
+func (b *Builder) lintManifests(tasks []BuildTask) ([]ManifestIssue, int) {
+	known := b.knownResources()
+	seen := make(map[string]bool)
+	var issues []ManifestIssue
+	checked := 0
+
+	for _, task := range tasks {
+		if task.Type == TypeViews {
+			continue
+		}
+		name := baseResourceName(task.ResourceName)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		layout := b.resourceLayout(name)
+		if layout.ManifestKind != "fxmanifest" || (layout.Runtime != "fivem" && layout.Runtime != "redm") {
+			continue
+		}
+		manifest, err := readFxManifest(filepath.Join(layout.ServerOutDir, "fxmanifest.lua"))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				issues = append(issues, ManifestIssue{Resource: name, Directive: "fxmanifest", Value: "fxmanifest.lua", Message: err.Error()})
+			}
+			continue
+		}
+		checked++
+		issues = append(issues, b.lintManifest(name, layout, manifest, known)...)
+	}
+
+	return issues, checked
+}
+
+func (b *Builder) lintManifest(name string, layout resourceBuildLayout, manifest *fxManifest, known map[string]bool) []ManifestIssue {
+	var issues []ManifestIssue
+	for _, directive := range manifestFileDirectives {
+		for _, value := range manifest.Directives[directive] {
+			if skipManifestPath(value) {
+				continue
+			}
+			if !manifestPathExists(layout.ServerOutDir, value) {
+				message := "file not found in build output"
+				if strings.ContainsAny(value, "*?[") {
+					message = "pattern matches no files in build output"
+				}
+				issues = append(issues, ManifestIssue{Resource: name, Directive: directive, Value: value, Message: message})
+			}
+		}
+	}
+
+	for _, dep := range manifest.Dependencies() {
+		if known[dep] || b.deployedResourceExists(dep) {
+			continue
+		}
+		issue := ManifestIssue{Resource: name, Directive: "dependency", Value: dep}
+		if b.config.Destination == "" {
+			issue.Message = "not built by this project (no destination configured to check)"
+			issue.Warning = true
+		} else {
+			issue.Message = fmt.Sprintf("not built by this project and not found in %s", b.config.Destination)
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// knownResources returns every resource the project builds, including the
+// shared dependency resource.
+func (b *Builder) knownResources() map[string]bool {
+	known := make(map[string]bool)
+	tasks := b.collectAllTasks()
+	for _, task := range tasks {
+		known[baseResourceName(task.ResourceName)] = true
+	}
+	if _, name, err := b.sharedDependencyOptions(tasks); err == nil && name != "" {
+		known[name] = true
+	}
+	return known
+}
+
+// deployedResourceExists looks for a resource in the destination. Like
+// FXServer, it searches [category] folders at any depth, starting from the
+// server's resources folder when the destination is a [category] in it.
+func (b *Builder) deployedResourceExists(name string) bool {
+	if b.config.Destination == "" {
+		return false
+	}
+	root := filepath.Clean(b.config.Destination)
+	for isCategoryFolder(filepath.Base(root)) && filepath.Dir(root) != root {
+		root = filepath.Dir(root)
+	}
+	return findDeployedResource(root, name)
+}
+
+func findDeployedResource(dir, name string) bool {
+	if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+		return true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !isCategoryFolder(entry.Name()) {
+			continue
+		}
+		category := filepath.Join(dir, entry.Name())
+		if info, err := os.Stat(category); err == nil && info.IsDir() && findDeployedResource(category, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCategoryFolder reports whether name is an FXServer [category] folder.
+func isCategoryFolder(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]")
+}
+
+// skipManifestPath reports values that do not refer to files of the resource:
+// other resources ('@ox_lib/init.lua') and URLs.
+func skipManifestPath(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || strings.HasPrefix(value, "@") || strings.Contains(value, "://")
+}
+
+// manifestPathExists resolves a manifest entry relative to the resource
+// directory. Patterns support * and ? within a segment and ** across segments,
+// like FXServer's file globbing.
+func manifestPathExists(resourceDir, value string) bool {
+	value = path.Clean(filepath.ToSlash(value))
+	if !strings.ContainsAny(value, "*?[") {
+		_, err := os.Stat(filepath.Join(resourceDir, filepath.FromSlash(value)))
+		return err == nil
+	}
+
+	segments := strings.Split(value, "/")
+	static := 0
+	for static < len(segments) && !strings.ContainsAny(segments[static], "*?[") {
+		static++
+	}
+	root := filepath.Join(resourceDir, filepath.FromSlash(strings.Join(segments[:static], "/")))
+	pattern := segments[static:]
+
+	found := false
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if found {
+			return filepath.SkipAll
+		}
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		if matchManifestGlob(pattern, strings.Split(filepath.ToSlash(rel), "/")) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+func matchManifestGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchManifestGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchManifestGlob(pattern[1:], segments[1:])
+}
+
+// checkManifests lints the manifests of the built resources and fails the
+// build when a manifest references a missing file or resource.
+func (b *Builder) checkManifests(results []BuildResult, plain bool) error {
+	var tasks []BuildTask
+	for _, result := range results {
+		if result.Success && !result.Skipped {
+			tasks = append(tasks, result.Task)
+		}
+	}
+	issues, _ := b.lintManifests(tasks)
+	if len(issues) == 0 {
+		return nil
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Resource < issues[j].Resource })
+
+	errorsFound := 0
+	fmt.Fprintln(b.out)
+	for _, issue := range issues {
+		switch {
+		case plain && issue.Warning:
+			fmt.Fprintf(b.out, "WARN  manifest: %s\n", issue)
+		case plain:
+			fmt.Fprintf(b.out, "FAIL  manifest: %s\n", issue)
+		case issue.Warning:
+			fmt.Fprintln(b.out, ui.Warning(fmt.Sprintf("Manifest: %s", issue)))
+		default:
+			fmt.Fprintln(b.out, ui.Error(fmt.Sprintf("Manifest: %s", issue)))
+		}
+		if !issue.Warning {
+			errorsFound++
+		}
+	}
+
+	if errorsFound > 0 {
+		return fmt.Errorf("%d manifest problem(s) found", errorsFound)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func writeLintFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchManifestGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"**/*", "index.html", true},
+		{"**/*", "assets/app.js", true},
+		{"*.js", "assets/app.js", false},
+		{"assets/*.js", "assets/app.js", true},
+		{"**/*.css", "assets/css/app.css", true},
+		{"**/*.css", "assets/app.js", false},
+	}
+	for _, tc := range cases {
+		if got := matchManifestGlob(strings.Split(tc.pattern, "/"), strings.Split(tc.path, "/")); got != tc.match {
+			t.Errorf("matchManifestGlob(%q, %q) = %v, expected %v", tc.pattern, tc.path, got, tc.match)
+		}
+	}
+}
+
+func TestLintManifests(t *testing.T) {
+	root := t.TempDir()
+	outDir := filepath.Join(root, "build")
+	destination := filepath.Join(root, "server", "resources")
+
+	writeLintFile(t, filepath.Join(outDir, "core", "server.js"), "")
+	writeLintFile(t, filepath.Join(outDir, "core", "ui", "index.html"), "")
+	writeLintFile(t, filepath.Join(outDir, "core", "ui", "assets", "app.js"), "")
+	writeLintFile(t, filepath.Join(destination, "[standalone]", "oxmysql", "fxmanifest.lua"), "")
+	writeLintFile(t, filepath.Join(outDir, "core", "fxmanifest.lua"), `fx_version 'cerulean'
+game 'gta5'
+server_scripts { 'server.js' }
+client_script 'clinet.js'
+shared_script '@ox_lib/init.lua'
+ui_page 'ui/index.html'
+files { 'ui/**/*.js', 'ui/**/*.css' }
+dependencies { 'oxmysql', 'chat', 'missing', '/onesync' }
+`)
+
+	cfg := &config.Config{
+		OutDir:      outDir,
+		Destination: destination,
+		Core:        config.CoreConfig{Path: filepath.Join(root, "core"), ResourceName: "core"},
+		Resources: config.ResourcesConfig{
+			Explicit: []config.ExplicitResource{{Path: filepath.Join(root, "resources", "chat"), ResourceName: "chat"}},
+		},
+	}
+
+	issues, checked := New(cfg).LintManifests()
+	if checked != 1 {
+		t.Fatalf("expected one built manifest to be checked, got %d", checked)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Directive+" "+issue.Value)
+	}
+	expected := "client_script clinet.js,files ui/**/*.css,dependency missing"
+	if strings.Join(got, ",") != expected {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+
+	cfg.Destination = ""
+	issues, _ = New(cfg).LintManifests()
+	warnings := 0
+	for _, issue := range issues {
+		if issue.Warning {
+			warnings++
+			if issue.Directive != "dependency" {
+				t.Errorf("expected only dependency issues to be warnings, got %s", issue)
+			}
+		}
+	}
+	if warnings != 2 {
+		t.Fatalf("expected unverifiable dependencies to be warnings, got %v", issues)
+	}
+}
+
+func TestDeployedResourceExists(t *testing.T) {
+	resources := filepath.Join(t.TempDir(), "resources")
+	writeLintFile(t, filepath.Join(resources, "[ox]", "[core]", "ox_lib", "fxmanifest.lua"), "")
+	writeLintFile(t, filepath.Join(resources, "[demo]", "chat", "fxmanifest.lua"), "")
+	writeLintFile(t, filepath.Join(resources, "[ox]", "docs", "ox_target", "fxmanifest.lua"), "")
+
+	b := New(&config.Config{Destination: filepath.Join(resources, "[demo]")})
+	for name, want := range map[string]bool{"ox_lib": true, "chat": true, "ox_target": false, "missing": false} {
+		if got := b.deployedResourceExists(name); got != want {
+			t.Errorf("deployedResourceExists(%q) = %v, expected %v", name, got, want)
+		}
+	}
+}
+
+func TestCheckManifestsFailsBuild(t *testing.T) {
+	root := t.TempDir()
+	outDir := filepath.Join(root, "build")
+	writeLintFile(t, filepath.Join(outDir, "core", "fxmanifest.lua"), "server_script 'server.js'\n")
+
+	cfg := &config.Config{
+		OutDir: outDir,
+		Core:   config.CoreConfig{Path: filepath.Join(root, "core"), ResourceName: "core"},
+	}
+	b := New(cfg)
+	var out strings.Builder
+	b.SetOutput(&out)
+
+	results := []BuildResult{{Task: b.collectAllTasks()[0], Success: true}}
+	err := b.checkManifests(results, true)
+	if err == nil || !strings.Contains(err.Error(), "1 manifest problem(s)") {
+		t.Fatalf("expected manifest problem, got %v", err)
+	}
+	if !strings.Contains(out.String(), "FAIL  manifest: [core] server_script 'server.js': file not found in build output") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
@@ -0,0 +1,31 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MetafileDir holds the esbuild metafiles written with `opencore build --metafile`.
+var MetafileDir = filepath.Join(".opencore", "meta")
+
+// MetafilePath returns where the metafile of one side ("server" or "client")
+// of a resource is written: .opencore/meta/<resource>.<side>.json.
+func MetafilePath(resourceName string, side string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(resourceName)
+	return filepath.Join(MetafileDir, name+"."+side+".json")
+}
+
+// removeStaleMetafiles deletes a task's previous metafiles before it is
+// rebuilt or restored from the build cache, so a side that is no longer built
+// doesn't keep an old metafile.
+func removeStaleMetafiles(task BuildTask) {
+	if task.Options.Metafile == nil {
+		return
+	}
+	for _, path := range []string{task.Options.Metafile.Server, task.Options.Metafile.Client} {
+		if path != "" {
+			_ = os.Remove(path)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+// moduleImportExpr matches imports of shared modules, e.g.
+// from '@modules/inventory-types' or import('@modules/inventory-types/items').
+var moduleImportExpr = regexp.MustCompile(`['"` + "`" + `]` + regexp.QuoteMeta(config.ModuleImportPrefix) + `([A-Za-z0-9_.-]+)`)
+
+var moduleSourceExtensions = map[string]bool{
+	".ts": true, ".tsx": true, ".mts": true, ".cts": true,
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+}
+
+// attachModules gives compiled tasks the directories of the shared modules,
+// which build.js resolves @modules/<name> imports against, and records the
+// modules each task imports directly or through another module. Changes to
+// those modules invalidate the task's cache entry and rebuild it in dev mode.
+func (b *Builder) attachModules(tasks []BuildTask) {
+	modules, err := b.config.SharedModules()
+	if err != nil || len(modules) == 0 {
+		return
+	}
+
+	dirs := make(map[string]string, len(modules))
+	paths := make(map[string]string, len(modules))
+	imports := make(map[string][]string, len(modules))
+	for _, module := range modules {
+		abs, err := filepath.Abs(module.Path)
+		if err != nil {
+			abs = module.Path
+		}
+		dirs[module.Name] = filepath.ToSlash(abs)
+		paths[module.Name] = module.Path
+		imports[module.Name] = scanModuleImports(module.Path)
+	}
+
+	for i := range tasks {
+		if tasks[i].Type == TypeViews || tasks[i].Type == TypeCopy {
+			continue
+		}
+		tasks[i].Options.Modules = dirs
+
+		seen := make(map[string]bool)
+		pending := scanModuleImports(tasks[i].Path)
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			pending = append(pending, imports[name]...)
+		}
+
+		tasks[i].Modules = nil
+		for name := range seen {
+			if path, ok := paths[name]; ok {
+				tasks[i].Modules = append(tasks[i].Modules, path)
+			}
+		}
+		sort.Strings(tasks[i].Modules)
+	}
+}
+
+// validateModules checks that every shared module directory exists.
+func (b *Builder) validateModules() error {
+	modules, err := b.config.SharedModules()
+	if err != nil {
+		return err
+	}
+	for _, module := range modules {
+		info, err := os.Stat(module.Path)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("module %s%s: directory %s not found", config.ModuleImportPrefix, module.Name, module.Path)
+		}
+	}
+	return nil
+}
+
+// ErrTypeScriptMissing means the project has no typescript package to
+// type-check its shared modules with.
+var ErrTypeScriptMissing = errors.New("typescript is not installed; shared modules are type-checked with tsc (install it with e.g. 'pnpm add -D typescript')")
+
+// ModuleTypeErrors is the output of a failed type-check of the shared
+// modules, one tsc error per line.
+type ModuleTypeErrors []string
+
+func (e ModuleTypeErrors) Error() string {
+	return fmt.Sprintf("module type-check failed with %s", plural(len(e), "error"))
+}
+
+// TypeCheckModules runs tsc --noEmit over the shared modules of the project
+// in the working directory, with the compiler options of its tsconfig.json.
+// esbuild strips types without checking them, so this is where type errors
+// in modules surface before they are bundled into every importing resource.
+// It returns ModuleTypeErrors when tsc reports errors and
+// ErrTypeScriptMissing when typescript cannot be found.
+func TypeCheckModules(ctx context.Context, modules []config.SharedModule) error {
+	if len(modules) == 0 {
+		return nil
+	}
+	tsc, err := findTypeScript()
+	if err != nil {
+		return err
+	}
+
+	// tsc only takes a project or a file list; a generated project extends
+	// the project's tsconfig.json and includes just the modules.
+	project := map[string]any{
+		"compilerOptions": map[string]any{"noEmit": true, "composite": false},
+		"files":           []string{},
+	}
+	if tsconfig, err := filepath.Abs("tsconfig.json"); err == nil {
+		if _, err := os.Stat(tsconfig); err == nil {
+			project["extends"] = filepath.ToSlash(tsconfig)
+		}
+	}
+	include := make([]string, 0, len(modules))
+	for _, module := range modules {
+		abs, err := filepath.Abs(module.Path)
+		if err != nil {
+			return err
+		}
+		include = append(include, filepath.ToSlash(abs)+"/**/*")
+	}
+	project["include"] = include
+
+	dir := filepath.Join(".opencore", "tsc")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return err
+	}
+	projectPath := filepath.Join(dir, "tsconfig.modules.json")
+	if err := os.WriteFile(projectPath, content, 0644); err != nil {
+		return err
+	}
+
+	output, err := exec.CommandContext(ctx, "node", tsc, "-p", projectPath, "--pretty", "false").CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	var typeErrors ModuleTypeErrors
+	for _, line := range strings.Split(string(output), "\n") {
+		// TS18003 (no inputs) only means the modules hold no TypeScript.
+		if strings.Contains(line, "error TS") && !strings.Contains(line, "error TS18003") {
+			typeErrors = append(typeErrors, strings.TrimSpace(line))
+		}
+	}
+	if len(typeErrors) > 0 {
+		return typeErrors
+	}
+	if strings.Contains(string(output), "error TS18003") {
+		return nil
+	}
+	return fmt.Errorf("tsc failed: %w\nOutput:\n%s", err, output)
+}
+
+// findTypeScript returns the tsc script of the typescript package installed
+// in the project or one of its parents, such as a workspace root.
+func findTypeScript() (string, error) {
+	dir, err := filepath.Abs(".")
+	if err != nil {
+		return "", err
+	}
+	for {
+		tsc := filepath.Join(dir, "node_modules", "typescript", "bin", "tsc")
+		if _, err := os.Stat(tsc); err == nil {
+			return tsc, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrTypeScriptMissing
+		}
+		dir = parent
+	}
+}
+
+// typeCheckModules type-checks the shared modules once per build, however
+// many environments it builds, and prints the errors tsc reports.
+func (b *Builder) typeCheckModules(ctx context.Context) error {
+	modules, err := b.config.SharedModules()
+	if err != nil {
+		return err
+	}
+	var pending []config.SharedModule
+	for _, module := range modules {
+		if !b.validatedSources[filepath.Clean(module.Path)] {
+			pending = append(pending, module)
+		}
+	}
+
+	err = TypeCheckModules(ctx, pending)
+	var typeErrors ModuleTypeErrors
+	if errors.As(err, &typeErrors) {
+		fmt.Fprintln(b.out, ui.Warning("Module type-check failed. Build cancelled."))
+		for _, line := range typeErrors {
+			fmt.Fprintln(b.out, ui.Warning(line))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if b.validatedSources != nil {
+		for _, module := range pending {
+			b.validatedSources[filepath.Clean(module.Path)] = true
+		}
+	}
+	return nil
+}
+
+// scanModuleImports returns the names of the shared modules imported by the
+// source files below root.
+func scanModuleImports(root string) []string {
+	seen := make(map[string]bool)
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case "node_modules", "dist", ".git", ".opencore":
+				if path != root {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if !moduleSourceExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, match := range moduleImportExpr.FindAllSubmatch(content, -1) {
+			seen[string(match[1])] = true
+		}
+		return nil
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// moduleDependents returns the tasks that import the module containing
+// changedAbs.
+func moduleDependents(all []BuildTask, changedAbs string) []BuildTask {
+	var dependents []BuildTask
+	for _, task := range all {
+		for _, module := range task.Modules {
+			moduleAbs, err := filepath.Abs(module)
+			if err != nil {
+				moduleAbs = module
+			}
+			moduleAbs = filepath.Clean(moduleAbs)
+			if changedAbs == moduleAbs || strings.HasPrefix(changedAbs, moduleAbs+string(os.PathSeparator)) {
+				dependents = append(dependents, task)
+				break
+			}
+		}
+	}
+	return dependents
+}
@@ -0,0 +1,129 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func TestSharedModules(t *testing.T) {
+	root := t.TempDir()
+	writeCacheTestFile(t, filepath.Join(root, "core", "src", "server.ts"), "import { Item } from '@modules/inventory-types'\n")
+	writeCacheTestFile(t, filepath.Join(root, "resources", "shop", "src", "client.ts"), "const items = await import(\"@modules/items/catalog\")\n")
+	writeCacheTestFile(t, filepath.Join(root, "resources", "chat", "src", "server.ts"), "export {}\n")
+	writeCacheTestFile(t, filepath.Join(root, "modules", "inventory-types", "index.ts"), "export interface Item { id: string }\n")
+	writeCacheTestFile(t, filepath.Join(root, "modules", "items", "catalog.ts"), "import type { Item } from '@modules/inventory-types'\n")
+	t.Chdir(root)
+
+	cfg := &config.Config{
+		Name:      "test-project",
+		OutDir:    "./dist",
+		Core:      config.CoreConfig{Path: "./core", ResourceName: "core"},
+		Resources: config.ResourcesConfig{Include: []string{"./resources/*"}},
+		Modules:   []string{"./modules/inventory-types", "./modules/items"},
+	}
+	b := New(cfg)
+	if err := b.validateModules(); err != nil {
+		t.Fatalf("expected the modules to exist: %v", err)
+	}
+	tasks := b.collectAllTasks()
+
+	modules := map[string][]string{}
+	for _, task := range tasks {
+		modules[task.ResourceName] = task.Modules
+		if task.Options.Modules["items"] != filepath.ToSlash(filepath.Join(root, "modules", "items")) {
+			t.Errorf("%s: expected the module directories in its options, got %v", task.ResourceName, task.Options.Modules)
+		}
+	}
+	inventory, items := filepath.Join("modules", "inventory-types"), filepath.Join("modules", "items")
+	want := map[string][]string{
+		"core": {inventory},
+		"shop": {inventory, items},
+		"chat": nil,
+	}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("expected imported modules %v, got %v", want, modules)
+	}
+
+	changed := filepath.Join("modules", "inventory-types", "index.ts")
+	if got := taskNames(TasksForChangedFile(tasks, changed)); got != "core,shop" {
+		t.Errorf("expected a module change to rebuild its dependents, got %s", got)
+	}
+	if got := taskNames(TasksForChangedFiles(tasks, []string{filepath.Join("modules", "items", "catalog.ts")})); got != "shop" {
+		t.Errorf("expected --changed-since to select the dependents, got %s", got)
+	}
+
+	cache := NewBuildCache(filepath.Join(root, ".opencore", "cache"))
+	shop := tasks[len(tasks)-1]
+	key1, err := cache.Key(shop, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeCacheTestFile(t, changed, "export interface Item { id: number }\n")
+	if key, _ := cache.Key(shop, nil); key == key1 {
+		t.Error("expected a module change to invalidate its dependents' cache entries")
+	}
+
+	cfg.Modules = append(cfg.Modules, "./modules/missing")
+	if err := New(cfg).validateModules(); err == nil {
+		t.Error("expected a missing module directory to fail")
+	}
+}
+
+// fakeTypeScript installs a tsc in root/node_modules that reports an error for
+// every included file containing "@ts-fail", the way tsc --pretty false does.
+const fakeTypeScript = `const fs = require('fs')
+const path = require('path')
+const project = JSON.parse(fs.readFileSync(process.argv[process.argv.indexOf('-p') + 1], 'utf8'))
+let failed = false
+const walk = (dir) => {
+    for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+        const file = path.join(dir, entry.name)
+        if (entry.isDirectory()) walk(file)
+        else if (fs.readFileSync(file, 'utf8').includes('@ts-fail')) {
+            console.log(path.relative(process.cwd(), file) + "(1,7): error TS2322: Type 'string' is not assignable to type 'number'.")
+            failed = true
+        }
+    }
+}
+for (const pattern of project.include) walk(pattern.replace('/**/*', ''))
+process.exit(failed ? 2 : 0)
+`
+
+func TestTypeCheckModules(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("skipping: node is not installed")
+	}
+
+	root := t.TempDir()
+	writeCacheTestFile(t, filepath.Join(root, "modules", "items", "index.ts"), "export const rarity: number = 'rare' // @ts-fail\n")
+	writeCacheTestFile(t, filepath.Join(root, "modules", "types", "index.ts"), "export interface Item { id: string }\n")
+	t.Chdir(root)
+	modules := []config.SharedModule{{Name: "items", Path: "modules/items"}, {Name: "types", Path: "modules/types"}}
+
+	if err := TypeCheckModules(context.Background(), modules); !errors.Is(err, ErrTypeScriptMissing) {
+		t.Fatalf("expected typescript to be required, got %v", err)
+	}
+
+	writeCacheTestFile(t, filepath.Join(root, "node_modules", "typescript", "bin", "tsc"), fakeTypeScript)
+	err := TypeCheckModules(context.Background(), modules)
+	var typeErrors ModuleTypeErrors
+	if !errors.As(err, &typeErrors) || len(typeErrors) != 1 || !strings.HasPrefix(typeErrors[0], "modules/items/index.ts(1,7): error TS2322") {
+		t.Fatalf("expected the type error of the items module, got %v", err)
+	}
+	if err := TypeCheckModules(context.Background(), modules[1:]); err != nil {
+		t.Errorf("expected the types module to type-check, got %v", err)
+	}
+
+	b := &Builder{config: &config.Config{Modules: []string{"./modules/items"}}, out: io.Discard, validatedSources: make(map[string]bool)}
+	if err := b.typeCheckModules(context.Background()); err == nil || err.Error() != "module type-check failed with 1 error" {
+		t.Errorf("expected the build to fail on module type errors, got %v", err)
+	}
+}
@@ -55,7 +55,7 @@ func (wp *WorkerPool) worker(id int) {
 			if !ok {
 				return
 			}
-			result := wp.buildFunc(wp.ctx, task)
+			result := wp.buildFunc(withWorker(wp.ctx, id+1), task)
 			select {
 			case wp.resultChan <- result:
 			case <-wp.ctx.Done():
@@ -95,6 +95,11 @@ func (wp *WorkerPool) Close() {
 	close(wp.resultChan)
 }
 
+// Done returns a channel that is closed once the pool is cancelled
+func (wp *WorkerPool) Done() <-chan struct{} {
+	return wp.ctx.Done()
+}
+
 // Cancel cancels all workers immediately
 func (wp *WorkerPool) Cancel() {
 	wp.cancel()
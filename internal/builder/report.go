@@ -0,0 +1,252 @@
+package builder
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReportOptions selects the machine-readable reports written after a build.
+// A path of "-" writes the report to stdout.
+type ReportOptions struct {
+	JSONPath     string
+	JUnitPath    string
+	SnapshotPath string // size snapshot written after a successful build
+}
+
+// ReportVersion is bumped whenever the JSON report shape changes incompatibly.
+const ReportVersion = 1
+
+// BuildReport is the JSON report produced by `opencore build --json`.
+type BuildReport struct {
+	Version     int               `json:"version"`
+	Success     bool              `json:"success"`
+	Environment string            `json:"environment,omitempty"`
+	Runtime     string            `json:"runtime"`
+	OutDir      string            `json:"outDir"`
+	DurationMs  int64             `json:"durationMs"`
+	TotalSize   int64             `json:"totalSize"`
+	Tasks       []TaskReport      `json:"tasks"`
+	Budgets     []BudgetViolation `json:"budgetViolations,omitempty"`
+}
+
+// TaskReport describes a single build task in a BuildReport.
+type TaskReport struct {
+	Name        string       `json:"name"`
+	Type        ResourceType `json:"type"`
+	Path        string       `json:"path"`
+	DurationMs  int64        `json:"durationMs"`
+	Success     bool         `json:"success"`
+	Cached      bool         `json:"cached,omitempty"`
+	Skipped     bool         `json:"skipped,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Output      string       `json:"output,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+	Size        *SizeReport  `json:"size,omitempty"`
+	Baseline    *SizeReport  `json:"baseline,omitempty"` // sizes from the --compare snapshot
+	Outputs     []string     `json:"outputs,omitempty"`
+}
+
+// SizeReport holds output sizes in bytes. Views only report a total.
+type SizeReport struct {
+	Server int64 `json:"server,omitempty"`
+	Client int64 `json:"client,omitempty"`
+	Total  int64 `json:"total"`
+}
+
+// NewBuildReport assembles a report from build results and the output
+// currently on disk.
+func (b *Builder) NewBuildReport(results []BuildResult, duration time.Duration) BuildReport {
+	report := BuildReport{
+		Version:     ReportVersion,
+		Success:     buildFailureError(results) == nil,
+		Environment: b.config.Build.Environment,
+		Runtime:     b.runtimeKind(),
+		OutDir:      filepath.ToSlash(b.config.OutDir),
+		DurationMs:  duration.Milliseconds(),
+		Tasks:       make([]TaskReport, 0, len(results)),
+	}
+
+	sizes := make(map[string]ResourceSize)
+	for _, s := range b.getResourceSizes(results) {
+		sizes[s.Name] = s
+		report.TotalSize += s.TotalSize
+	}
+
+	for _, r := range results {
+		task := TaskReport{
+			Name:        r.Task.ResourceName,
+			Type:        r.Task.Type,
+			Path:        filepath.ToSlash(r.Task.Path),
+			DurationMs:  r.Duration.Milliseconds(),
+			Success:     r.Success,
+			Cached:      r.Cached,
+			Skipped:     r.Skipped,
+			Output:      r.Output,
+			Diagnostics: r.Diagnostics,
+		}
+		if r.Error != nil {
+			task.Error = r.Error.Error()
+		}
+		if r.Success {
+			if s, ok := sizes[r.Task.ResourceName]; ok {
+				task.Size = &SizeReport{Server: s.ServerSize, Client: s.ClientSize, Total: s.TotalSize}
+			}
+			if previous, ok := b.baseline.Resource(r.Task.ResourceName); ok {
+				task.Baseline = &SizeReport{Server: previous.Server, Client: previous.Client, Total: previous.Total}
+			}
+			task.Outputs = b.taskOutputPaths(r.Task)
+		}
+		report.Tasks = append(report.Tasks, task)
+	}
+
+	report.Budgets = b.checkBudgets(results)
+	for _, v := range report.Budgets {
+		if !v.Soft {
+			report.Success = false
+		}
+	}
+
+	return report
+}
+
+// taskOutputPaths lists the files and directories a task produced.
+func (b *Builder) taskOutputPaths(task BuildTask) []string {
+	if task.Type == TypeViews {
+		return []string{filepath.ToSlash(task.OutDir)}
+	}
+
+	layout := b.resourceLayout(task.ResourceName)
+	var paths []string
+	for _, path := range []string{
+		filepath.Join(layout.ServerOutDir, layout.ServerOutFile),
+		filepath.Join(layout.ClientOutDir, layout.ClientOutFile),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, filepath.ToSlash(path))
+		}
+	}
+	if len(paths) == 0 && task.OutDir != "" {
+		// Standalone and copy tasks don't follow the server/client layout.
+		paths = append(paths, filepath.ToSlash(task.OutDir))
+	}
+	return paths
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r BuildReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// WriteJUnit writes the report as JUnit XML, one test case per task.
+func (r BuildReport) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:  "opencore build",
+		Tests: len(r.Tasks),
+		Time:  junitSeconds(r.DurationMs),
+	}
+	for _, task := range r.Tasks {
+		testCase := junitTestCase{
+			Name:      task.Name,
+			ClassName: "opencore." + string(task.Type),
+			Time:      junitSeconds(task.DurationMs),
+		}
+		switch {
+		case task.Skipped:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: task.Error}
+		case !task.Success:
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: task.Error, Body: task.Output}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeReports writes the configured reports. It runs for failed builds too,
+// so CI can still collect per-task errors.
+func (b *Builder) writeReports(results []BuildResult, duration time.Duration) error {
+	if b.report.JSONPath == "" && b.report.JUnitPath == "" {
+		return nil
+	}
+
+	report := b.NewBuildReport(results, duration)
+	if b.report.JSONPath != "" {
+		if err := writeReportFile(b.report.JSONPath, report.WriteJSON); err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+	}
+	if b.report.JUnitPath != "" {
+		if err := writeReportFile(b.report.JUnitPath, report.WriteJUnit); err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeReportFile(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func TestBuildReport(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "build")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "server.js"), "server")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "client.js"), "cl")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "ui", "index.html"), "<html></html>")
+
+	b := New(&config.Config{OutDir: outDir, Build: config.BuildConfig{Environment: "production"}})
+	results := []BuildResult{
+		{Task: BuildTask{ResourceName: "chat", Type: TypeResource, Path: "./resources/chat"}, Success: true, Duration: 1500 * time.Millisecond},
+		{Task: BuildTask{ResourceName: "chat/ui", Type: TypeViews, OutDir: filepath.Join(outDir, "chat", "ui")}, Success: true, Cached: true},
+		{Task: BuildTask{ResourceName: "admin", Type: TypeResource}, Error: errors.New("exit status 1"), Output: "src/index.ts: error"},
+		{Task: BuildTask{ResourceName: "radio", Type: TypeResource}, Skipped: true, Error: errors.New("skipped: dependency admin failed")},
+	}
+
+	report := b.NewBuildReport(results, 2*time.Second)
+	if report.Success {
+		t.Fatal("expected report to be unsuccessful")
+	}
+	if report.Environment != "production" || report.DurationMs != 2000 {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	if report.TotalSize != int64(len("server")+len("cl")+len("<html></html>")) {
+		t.Fatalf("unexpected total size %d", report.TotalSize)
+	}
+
+	chat := report.Tasks[0]
+	if chat.Size == nil || chat.Size.Server != 6 || chat.Size.Client != 2 || chat.DurationMs != 1500 {
+		t.Fatalf("unexpected chat report: %+v", chat)
+	}
+	if len(chat.Outputs) != 2 || !strings.HasSuffix(chat.Outputs[0], "chat/server.js") {
+		t.Fatalf("unexpected chat outputs: %v", chat.Outputs)
+	}
+	if !report.Tasks[1].Cached || report.Tasks[1].Size == nil {
+		t.Fatalf("unexpected views report: %+v", report.Tasks[1])
+	}
+	if admin := report.Tasks[2]; admin.Error != "exit status 1" || admin.Output == "" || admin.Size != nil {
+		t.Fatalf("unexpected failed task report: %+v", admin)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded BuildReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if len(decoded.Tasks) != 4 || decoded.Version != ReportVersion {
+		t.Fatalf("unexpected decoded report: %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := report.WriteJUnit(&buf); err != nil {
+		t.Fatalf("WriteJUnit failed: %v", err)
+	}
+	junit := buf.String()
+	for _, want := range []string{
+		`tests="4" failures="1" skipped="1"`,
+		`<testcase name="chat" classname="opencore.resource" time="1.500">`,
+		`<failure message="exit status 1">src/index.ts: error</failure>`,
+		`<skipped message="skipped: dependency admin failed"></skipped>`,
+	} {
+		if !strings.Contains(junit, want) {
+			t.Errorf("expected JUnit output to contain %q, got:\n%s", want, junit)
+		}
+	}
+}
@@ -18,9 +18,12 @@ import (
 // ResourceBuilder handles building individual resources
 type ResourceBuilder struct {
 	projectPath         string
-	embeddedScriptPath  string
 	embeddedScriptMutex sync.Mutex
-	embeddedScriptReady bool
+	embeddedScripts     map[string]string // extracted build.js by project root
+
+	daemonWorkers int
+	daemonMutex   sync.Mutex
+	daemon        *buildDaemon
 }
 
 type SharedDependencyResource struct {
@@ -34,6 +37,7 @@ type SharedDependencyOptions struct {
 	VerifySandboxPaths  *bool                      `json:"verifySandboxPaths,omitempty"`
 	AllowInstallScripts *bool                      `json:"allowInstallScripts,omitempty"`
 	Cache               *bool                      `json:"cache,omitempty"`
+	DependencyCacheDir  string                     `json:"dependencyCacheDir,omitempty"`
 	Dependencies        []SharedDependencyResource `json:"dependencies"`
 }
 
@@ -44,19 +48,25 @@ func NewResourceBuilder(projectPath string) *ResourceBuilder {
 	}
 }
 
-// ensureEmbeddedScript extracts the embedded build script to the project directory
-// so it can resolve node_modules properly
+// ensureEmbeddedScript extracts the embedded build script to the directory of
+// the project being built, so it resolves that project's node_modules. The
+// builder is shared by the projects of a workspace, so each project root gets
+// its own copy.
 func (rb *ResourceBuilder) ensureEmbeddedScript() (string, error) {
 	rb.embeddedScriptMutex.Lock()
 	defer rb.embeddedScriptMutex.Unlock()
 
-	if rb.embeddedScriptReady && rb.embeddedScriptPath != "" {
-		if _, err := os.Stat(rb.embeddedScriptPath); err == nil {
-			return rb.embeddedScriptPath, nil
+	root, err := filepath.Abs(rb.projectPath)
+	if err != nil {
+		return "", err
+	}
+	if scriptPath, ok := rb.embeddedScripts[root]; ok {
+		if _, err := os.Stat(scriptPath); err == nil {
+			return scriptPath, nil
 		}
 	}
 
-	cacheDir := filepath.Join(rb.projectPath, "node_modules", ".cache", "opencore")
+	cacheDir := filepath.Join(root, "node_modules", ".cache", "opencore")
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create cache directory: %w", err)
 	}
@@ -80,22 +90,87 @@ func (rb *ResourceBuilder) ensureEmbeddedScript() (string, error) {
 		}
 	}
 
-	rb.embeddedScriptPath = filepath.Join(cacheDir, "build.js")
-	rb.embeddedScriptReady = true
-	return rb.embeddedScriptPath, nil
+	if rb.embeddedScripts == nil {
+		rb.embeddedScripts = make(map[string]string)
+	}
+	scriptPath := filepath.Join(cacheDir, "build.js")
+	rb.embeddedScripts[root] = scriptPath
+	return scriptPath, nil
+}
+
+// EnableDaemon builds core, resource and standalone tasks on a pool of up to
+// workers long-lived node processes instead of one process per task.
+func (rb *ResourceBuilder) EnableDaemon(workers int) {
+	rb.daemonMutex.Lock()
+	defer rb.daemonMutex.Unlock()
+	rb.daemonWorkers = workers
+}
+
+// buildWithDaemon builds a task on the build daemon. handled is false when
+// the task must be built by a one-shot process: the daemon is disabled or
+// unavailable, or the task uses a custom compiler.
+func (rb *ResourceBuilder) buildWithDaemon(ctx context.Context, task BuildTask) (output string, handled bool, err error) {
+	if task.CustomCompiler != "" {
+		return "", false, nil
+	}
+
+	rb.daemonMutex.Lock()
+	if rb.daemonWorkers == 0 {
+		rb.daemonMutex.Unlock()
+		return "", false, nil
+	}
+	if rb.daemon == nil {
+		rb.daemon = newBuildDaemon(rb.projectPath, rb.daemonScriptPath, rb.daemonWorkers)
+	}
+	daemon := rb.daemon
+	rb.daemonMutex.Unlock()
+
+	output, err = daemon.build(ctx, task)
+	if errors.Is(err, errDaemonUnavailable) {
+		return "", false, nil
+	}
+	return output, true, err
+}
+
+func (rb *ResourceBuilder) daemonScriptPath() (string, error) {
+	scriptPath, err := rb.ensureEmbeddedScript()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(scriptPath), "daemon.js"), nil
+}
+
+// Close stops the build daemon and removes temporary files.
+func (rb *ResourceBuilder) Close() {
+	rb.daemonMutex.Lock()
+	daemon := rb.daemon
+	rb.daemon = nil
+	rb.daemonWorkers = 0
+	rb.daemonMutex.Unlock()
+
+	if daemon != nil {
+		daemon.Close()
+	}
+	rb.Cleanup()
 }
 
-// Cleanup removes temporary files created by the builder
+// Cleanup removes temporary files created by the builder. While the build
+// daemon runs they are kept for its workers; Close removes them.
 func (rb *ResourceBuilder) Cleanup() {
+	rb.daemonMutex.Lock()
+	running := rb.daemon != nil
+	rb.daemonMutex.Unlock()
+	if running {
+		return
+	}
+
 	rb.embeddedScriptMutex.Lock()
 	defer rb.embeddedScriptMutex.Unlock()
 
-	if rb.embeddedScriptPath != "" {
-		cacheDir := filepath.Dir(rb.embeddedScriptPath)
-		os.RemoveAll(cacheDir)
-		rb.embeddedScriptPath = ""
-		rb.embeddedScriptReady = false
+	for _, scriptPath := range rb.embeddedScripts {
+		os.RemoveAll(filepath.Dir(scriptPath))
 	}
+	rb.embeddedScripts = nil
 }
 
 // getBuildScriptPath returns the build script path for a task
@@ -157,8 +232,11 @@ func (rb *ResourceBuilder) BuildWithContext(ctx context.Context, task BuildTask)
 	var err error
 	var output string
 
+	var phases []TracePhase
 	if task.Type != TypeViews {
-		if autoloadErr := rb.generateAutoloadControllers(task.Path); autoloadErr != nil {
+		autoloadErr := rb.generateAutoloadControllers(task.Path)
+		phases = append(phases, TracePhase{Name: "autoload", Start: start, End: time.Now()})
+		if autoloadErr != nil {
 			duration := time.Since(start)
 			return BuildResult{
 				Task:     task,
@@ -187,17 +265,41 @@ func (rb *ResourceBuilder) BuildWithContext(ctx context.Context, task BuildTask)
 
 	duration := time.Since(start)
 
+	output, reported := extractPhases(output)
+	phases = append(phases, reported...)
+	output, inputs := extractInputs(output)
+	output, diagnostics := extractDiagnostics(output)
+	if err != nil && ctx.Err() == nil && len(diagnostics) > 0 {
+		// The diagnostics replace the raw output the error would otherwise carry.
+		if errorCount, _ := countDiagnostics(diagnostics); errorCount > 0 {
+			err = fmt.Errorf("%s build failed with %s", task.Type, plural(errorCount, "error"))
+		} else {
+			message, _ := extractDiagnostics(err.Error())
+			err = errors.New(message)
+		}
+	}
+
 	return BuildResult{
-		Task:     task,
-		Success:  err == nil,
-		Duration: duration,
-		Error:    err,
-		Output:   output,
+		Task:        task,
+		Success:     err == nil,
+		Duration:    duration,
+		Error:       err,
+		Output:      output,
+		Diagnostics: diagnostics,
+		Phases:      phases,
+		Inputs:      inputs,
 	}
 }
 
 // buildCore builds the core resource
 func (rb *ResourceBuilder) buildCore(ctx context.Context, task BuildTask) (string, error) {
+	if output, handled, err := rb.buildWithDaemon(ctx, task); handled {
+		if err != nil && ctx.Err() == nil {
+			return output, fmt.Errorf("core build failed: %w\nOutput:\n%s", err, output)
+		}
+		return output, err
+	}
+
 	scriptPath, err := rb.getBuildScriptPath(task)
 	if err != nil {
 		return "", err
@@ -225,6 +327,13 @@ func (rb *ResourceBuilder) buildCore(ctx context.Context, task BuildTask) (strin
 
 // buildResource builds a satellite resource
 func (rb *ResourceBuilder) buildResource(ctx context.Context, task BuildTask) (string, error) {
+	if output, handled, err := rb.buildWithDaemon(ctx, task); handled {
+		if err != nil && ctx.Err() == nil {
+			return output, fmt.Errorf("resource build failed: %w\nOutput:\n%s", err, output)
+		}
+		return output, err
+	}
+
 	scriptPath, err := rb.getBuildScriptPath(task)
 	if err != nil {
 		return "", err
@@ -252,6 +361,13 @@ func (rb *ResourceBuilder) buildResource(ctx context.Context, task BuildTask) (s
 
 // buildStandalone builds a standalone resource
 func (rb *ResourceBuilder) buildStandalone(ctx context.Context, task BuildTask) (string, error) {
+	if output, handled, err := rb.buildWithDaemon(ctx, task); handled {
+		if err != nil && ctx.Err() == nil {
+			return output, fmt.Errorf("standalone build failed: %w\nOutput:\n%s", err, output)
+		}
+		return output, err
+	}
+
 	scriptPath, err := rb.getBuildScriptPath(task)
 	if err != nil {
 		return "", err
@@ -107,12 +107,8 @@ func TestNewResourceBuilder(t *testing.T) {
 		t.Errorf("Expected projectPath '/test/project', got '%s'", rb.projectPath)
 	}
 
-	if rb.embeddedScriptReady {
-		t.Error("embeddedScriptReady should be false initially")
-	}
-
-	if rb.embeddedScriptPath != "" {
-		t.Error("embeddedScriptPath should be empty initially")
+	if len(rb.embeddedScripts) != 0 {
+		t.Error("embeddedScripts should be empty initially")
 	}
 }
 
@@ -163,6 +159,36 @@ func TestEnsureEmbeddedScript(t *testing.T) {
 	}
 }
 
+func TestEnsureEmbeddedScriptPerProject(t *testing.T) {
+	workspace := t.TempDir()
+	rb := NewResourceBuilder(".")
+	defer rb.Cleanup()
+
+	var scripts []string
+	for _, project := range []string{"fivem", "redm"} {
+		root := filepath.Join(workspace, project)
+		if err := os.MkdirAll(root, 0755); err != nil {
+			t.Fatal(err)
+		}
+		t.Chdir(root)
+		scriptPath, err := rb.ensureEmbeddedScript()
+		if err != nil {
+			t.Fatalf("ensureEmbeddedScript failed: %v", err)
+		}
+		if want := filepath.Join(root, "node_modules", ".cache", "opencore", "build.js"); scriptPath != want {
+			t.Errorf("expected the script of %s at %s, got %s", project, want, scriptPath)
+		}
+		scripts = append(scripts, scriptPath)
+	}
+
+	rb.Cleanup()
+	for _, scriptPath := range scripts {
+		if _, err := os.Stat(scriptPath); !os.IsNotExist(err) {
+			t.Errorf("expected Cleanup to remove %s", scriptPath)
+		}
+	}
+}
+
 func TestCleanup(t *testing.T) {
 	rb := NewResourceBuilder(".")
 
@@ -186,12 +212,8 @@ func TestCleanup(t *testing.T) {
 	}
 
 	// Verify state is reset
-	if rb.embeddedScriptReady {
-		t.Error("embeddedScriptReady should be false after cleanup")
-	}
-
-	if rb.embeddedScriptPath != "" {
-		t.Error("embeddedScriptPath should be empty after cleanup")
+	if len(rb.embeddedScripts) != 0 {
+		t.Error("embeddedScripts should be empty after cleanup")
 	}
 
 	// Cleanup again should not panic
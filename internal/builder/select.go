@@ -0,0 +1,223 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Selection limits a build to some of the project's tasks.
+type Selection struct {
+	// Resources are names or globs matched against task resource names.
+	// "chat" selects the chat resource and its views, "chat/ui" only the views.
+	Resources []string
+	// ChangedSince selects the tasks affected by files changed since this git ref.
+	ChangedSince string
+}
+
+// Active reports whether the selection excludes anything.
+func (s Selection) Active() bool {
+	return len(s.Resources) > 0 || s.ChangedSince != ""
+}
+
+// SelectTasks returns the tasks matching any of patterns, in their original
+// order. A pattern matches a task's resource name or its base resource name,
+// with path.Match globs. A pattern matching no task is an error.
+func SelectTasks(all []BuildTask, patterns []string) ([]BuildTask, error) {
+	selected := make([]bool, len(all))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid resource pattern %q: %w", pattern, err)
+		}
+		matched := false
+		for i, task := range all {
+			full, _ := path.Match(pattern, task.ResourceName)
+			base, _ := path.Match(pattern, baseResourceName(task.ResourceName))
+			if full || base {
+				selected[i] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no resource matches %q", pattern)
+		}
+	}
+
+	var tasks []BuildTask
+	for i, task := range all {
+		if selected[i] {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// TasksForChangedFile returns the tasks affected by a change to file. The
+// task whose path is the longest prefix of the file owns it. A change in a
+// views task affects only the views; any other change affects every task of
+// the resource.
+func TasksForChangedFile(all []BuildTask, changedFile string) []BuildTask {
+	changedAbs, err := filepath.Abs(changedFile)
+	if err != nil {
+		changedAbs = changedFile
+	}
+	// Normalize path separators for Windows
+	changedAbs = filepath.Clean(changedAbs)
+
+	// Shared modules live outside the resources that import them.
+	if dependents := moduleDependents(all, changedAbs); len(dependents) > 0 {
+		return dependents
+	}
+	// So do the environment files every compiled task is aliased to.
+	if dependents := aliasDependents(all, changedAbs); len(dependents) > 0 {
+		return dependents
+	}
+
+	// Find best matching task (longest path prefix)
+	bestIdx := -1
+	bestLen := -1
+	for i, t := range all {
+		taskAbs, err := filepath.Abs(t.Path)
+		if err != nil {
+			taskAbs = t.Path
+		}
+		taskAbs = filepath.Clean(taskAbs)
+
+		if strings.HasPrefix(changedAbs, taskAbs+string(os.PathSeparator)) || changedAbs == taskAbs {
+			if len(taskAbs) > bestLen {
+				bestLen = len(taskAbs)
+				bestIdx = i
+			}
+		}
+	}
+
+	if bestIdx == -1 {
+		return nil
+	}
+
+	best := all[bestIdx]
+	base := baseResourceName(best.ResourceName)
+
+	// If the change is in a views task, rebuild only the views task.
+	if best.Type == TypeViews || strings.HasSuffix(best.ResourceName, "/ui") {
+		return []BuildTask{best}
+	}
+
+	// Otherwise rebuild all tasks belonging to the base resource (e.g., resource + its views).
+	var affected []BuildTask
+	for _, t := range all {
+		if baseResourceName(t.ResourceName) == base {
+			affected = append(affected, t)
+		}
+	}
+	return affected
+}
+
+// aliasDependents returns the tasks whose environment aliases point at
+// changedAbs, such as environments/environment.<name>.ts.
+func aliasDependents(all []BuildTask, changedAbs string) []BuildTask {
+	var dependents []BuildTask
+	for _, task := range all {
+		for _, target := range task.Options.EnvironmentAliases {
+			if filepath.Clean(filepath.FromSlash(target)) == changedAbs {
+				dependents = append(dependents, task)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// ChangedFiles lists the files under the current directory that differ from
+// ref: committed, staged and unstaged changes plus untracked files.
+func ChangedFiles(ref string) ([]string, error) {
+	diff, err := exec.Command("git", "diff", "--name-only", "--relative", ref, "--").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff against %q failed: %w", ref, gitError(err))
+	}
+	untracked, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", gitError(err))
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(diff)+"\n"+string(untracked), "\n") {
+		file := strings.TrimSpace(line)
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, filepath.FromSlash(file))
+	}
+	return files, nil
+}
+
+func gitError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// TasksForChangedFiles returns the tasks affected by any of files, in their
+// original order. A change to a project-level input such as
+// opencore.config.ts or a lockfile affects every task.
+func TasksForChangedFiles(all []BuildTask, files []string) []BuildTask {
+	affected := make(map[string]bool)
+	for _, file := range files {
+		for _, input := range projectCacheInputs {
+			if filepath.Clean(file) == input {
+				return all
+			}
+		}
+		for _, task := range TasksForChangedFile(all, file) {
+			affected[taskKey(task)] = true
+		}
+	}
+
+	var tasks []BuildTask
+	for _, task := range all {
+		if affected[taskKey(task)] {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// selectTasks applies the builder's selection to tasks.
+func (b *Builder) selectTasks(tasks []BuildTask) ([]BuildTask, error) {
+	var err error
+	if len(b.selection.Resources) > 0 {
+		if tasks, err = SelectTasks(tasks, b.selection.Resources); err != nil {
+			return nil, err
+		}
+	}
+	if b.selection.ChangedSince != "" {
+		files, err := ChangedFiles(b.selection.ChangedSince)
+		if err != nil {
+			return nil, err
+		}
+		if !b.changesPreset(files) {
+			tasks = TasksForChangedFiles(tasks, files)
+		}
+	}
+	return tasks, nil
+}
+
+// changesPreset reports whether files include a preset the config extends,
+// which affects every task like opencore.config.ts itself.
+func (b *Builder) changesPreset(files []string) bool {
+	for _, preset := range b.config.Presets() {
+		for _, file := range files {
+			if abs, err := filepath.Abs(file); err == nil && abs == filepath.Clean(preset.File) {
+				return true
+			}
+		}
+	}
+	return false
+}
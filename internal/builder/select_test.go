@@ -0,0 +1,147 @@
+package builder
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func selectionTasks() []BuildTask {
+	return []BuildTask{
+		{Path: "core", ResourceName: "core", Type: TypeCore},
+		{Path: filepath.Join("core", "views"), ResourceName: "core/ui", Type: TypeViews},
+		{Path: filepath.Join("resources", "chat"), ResourceName: "chat", Type: TypeResource},
+		{Path: filepath.Join("resources", "chat", "ui"), ResourceName: "chat/ui", Type: TypeViews},
+		{Path: filepath.Join("resources", "shop-items"), ResourceName: "shop-items", Type: TypeResource},
+		{Path: filepath.Join("resources", "shop-admin"), ResourceName: "shop-admin", Type: TypeResource},
+	}
+}
+
+func taskNames(tasks []BuildTask) string {
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		names = append(names, task.ResourceName)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSelectTasks(t *testing.T) {
+	all := selectionTasks()
+	cases := []struct {
+		patterns []string
+		want     string
+	}{
+		{[]string{"chat"}, "chat,chat/ui"},
+		{[]string{"core/ui"}, "core/ui"},
+		{[]string{"shop-*"}, "shop-items,shop-admin"},
+		{[]string{"*/ui", "shop-admin"}, "core/ui,chat/ui,shop-admin"},
+	}
+	for _, tc := range cases {
+		tasks, err := SelectTasks(all, tc.patterns)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.patterns, err)
+		}
+		if got := taskNames(tasks); got != tc.want {
+			t.Errorf("%v: expected %s, got %s", tc.patterns, tc.want, got)
+		}
+	}
+
+	if _, err := SelectTasks(all, []string{"missing"}); err == nil || !strings.Contains(err.Error(), `no resource matches "missing"`) {
+		t.Errorf("expected an unmatched pattern to fail, got %v", err)
+	}
+	if _, err := SelectTasks(all, []string{"[chat"}); err == nil {
+		t.Error("expected an invalid glob to fail")
+	}
+}
+
+func TestTasksForChangedFiles(t *testing.T) {
+	all := selectionTasks()
+	cases := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{filepath.Join("resources", "chat", "src", "server.ts")}, "chat,chat/ui"},
+		{[]string{filepath.Join("resources", "chat", "ui", "App.tsx")}, "chat/ui"},
+		{[]string{filepath.Join("core", "views", "index.html"), filepath.Join("resources", "shop-admin", "src", "client.ts")}, "core/ui,shop-admin"},
+		{[]string{"README.md"}, ""},
+		{[]string{"README.md", "pnpm-lock.yaml"}, taskNames(all)},
+	}
+	for _, tc := range cases {
+		if got := taskNames(TasksForChangedFiles(all, tc.files)); got != tc.want {
+			t.Errorf("%v: expected %q, got %q", tc.files, tc.want, got)
+		}
+	}
+}
+
+func TestTasksForChangedEnvironmentFile(t *testing.T) {
+	envFile, err := filepath.Abs(filepath.Join("environments", "environment.production.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := selectionTasks()
+	for i := range all {
+		if all[i].Type != TypeViews {
+			all[i].Options.EnvironmentAliases = map[string]string{"@opencore/environment": filepath.ToSlash(envFile)}
+		}
+	}
+	if got := taskNames(TasksForChangedFiles(all, []string{filepath.Join("environments", "environment.production.ts")})); got != "core,chat,shop-items,shop-admin" {
+		t.Errorf("expected every compiled task, got %q", got)
+	}
+	if got := taskNames(TasksForChangedFiles(all, []string{filepath.Join("environments", "environment.staging.ts")})); got != "" {
+		t.Errorf("expected an unused environment file to select nothing, got %q", got)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("skipping: git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("resources/chat/src/server.ts", "v1")
+	write("resources/radio/src/server.ts", "v1")
+	git("add", ".")
+	git("commit", "-qm", "initial")
+	git("tag", "base")
+
+	write("resources/chat/src/server.ts", "v2")
+	git("commit", "-qam", "change chat")
+	write("resources/shop/src/server.ts", "new")
+
+	t.Chdir(dir)
+	files, err := ChangedFiles("base")
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	got := strings.Join(files, ",")
+	want := strings.Join([]string{filepath.Join("resources", "chat", "src", "server.ts"), filepath.Join("resources", "shop", "src", "server.ts")}, ",")
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if _, err := ChangedFiles("no-such-ref"); err == nil {
+		t.Error("expected an unknown ref to fail")
+	}
+}
@@ -0,0 +1,244 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultSnapshotPath is where `--save-snapshot` writes when no path is given.
+const DefaultSnapshotPath = ".opencore/size-snapshot.json"
+
+const sizeSnapshotVersion = 1
+
+// SizeSnapshot stores the output sizes of a build so a later build can be
+// compared against it with `opencore build --compare`.
+type SizeSnapshot struct {
+	Version   int                `json:"version"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Resources []SnapshotResource `json:"resources"`
+}
+
+// SnapshotResource holds the sizes of one resource or views bundle in bytes.
+type SnapshotResource struct {
+	Name   string `json:"name"`
+	Views  bool   `json:"views,omitempty"`
+	Server int64  `json:"server,omitempty"`
+	Client int64  `json:"client,omitempty"`
+	Total  int64  `json:"total"`
+}
+
+// NewSizeSnapshot captures the given resource sizes.
+func NewSizeSnapshot(sizes []ResourceSize) *SizeSnapshot {
+	snapshot := &SizeSnapshot{
+		Version:   sizeSnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Resources: make([]SnapshotResource, 0, len(sizes)),
+	}
+	for _, s := range sizes {
+		snapshot.Resources = append(snapshot.Resources, SnapshotResource{
+			Name:   s.Name,
+			Views:  s.IsViews,
+			Server: s.ServerSize,
+			Client: s.ClientSize,
+			Total:  s.TotalSize,
+		})
+	}
+	return snapshot
+}
+
+// LoadSizeSnapshot reads a snapshot written by `--save-snapshot`.
+func LoadSizeSnapshot(path string) (*SizeSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read size snapshot: %w", err)
+	}
+
+	var snapshot SizeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse size snapshot %s: %w", path, err)
+	}
+	if snapshot.Version != sizeSnapshotVersion {
+		return nil, fmt.Errorf("unsupported size snapshot version %d in %s", snapshot.Version, path)
+	}
+	return &snapshot, nil
+}
+
+// Save writes the snapshot as JSON, creating parent directories as needed.
+func (s *SizeSnapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Resource returns the stored sizes for a resource name.
+func (s *SizeSnapshot) Resource(name string) (SnapshotResource, bool) {
+	if s == nil {
+		return SnapshotResource{}, false
+	}
+	for _, r := range s.Resources {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return SnapshotResource{}, false
+}
+
+// Total returns the combined size of every resource in the snapshot.
+func (s *SizeSnapshot) Total() int64 {
+	var total int64
+	for _, r := range s.Resources {
+		total += r.Total
+	}
+	return total
+}
+
+// Removed returns the resources in the snapshot that are missing from sizes.
+func (s *SizeSnapshot) Removed(sizes []ResourceSize) []SnapshotResource {
+	if s == nil {
+		return nil
+	}
+	present := make(map[string]bool, len(sizes))
+	for _, size := range sizes {
+		present[size.Name] = true
+	}
+
+	var removed []SnapshotResource
+	for _, r := range s.Resources {
+		if !present[r.Name] {
+			removed = append(removed, r)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Name < removed[j].Name })
+	return removed
+}
+
+// Only returns a copy of the snapshot with just the resources in sizes.
+func (s *SizeSnapshot) Only(sizes []ResourceSize) *SizeSnapshot {
+	if s == nil {
+		return nil
+	}
+	present := make(map[string]bool, len(sizes))
+	for _, size := range sizes {
+		present[size.Name] = true
+	}
+	only := *s
+	only.Resources = nil
+	for _, r := range s.Resources {
+		if present[r.Name] {
+			only.Resources = append(only.Resources, r)
+		}
+	}
+	return &only
+}
+
+// Update returns a copy of the snapshot with the resources of newer replaced
+// or added, taking newer's creation time.
+func (s *SizeSnapshot) Update(newer *SizeSnapshot) *SizeSnapshot {
+	updated := *newer
+	updated.Resources = nil
+	replaced := make(map[string]bool, len(newer.Resources))
+	for _, r := range newer.Resources {
+		replaced[r.Name] = true
+	}
+	for _, r := range s.Resources {
+		if !replaced[r.Name] {
+			updated.Resources = append(updated.Resources, r)
+		}
+	}
+	updated.Resources = append(updated.Resources, newer.Resources...)
+	return &updated
+}
+
+// comparisonBaseline is the baseline the summary compares sizes against. A
+// selective build compares only the resources it built, so the others are
+// neither reported as removed nor counted in the total.
+func (b *Builder) comparisonBaseline(sizes []ResourceSize) *SizeSnapshot {
+	if b.selection.Active() {
+		return b.baseline.Only(sizes)
+	}
+	return b.baseline
+}
+
+// formatSizeDelta formats the change from previous to current, e.g.
+// "+42.0 KB, +12.5%". A zero previous size is reported as "new".
+func formatSizeDelta(previous, current int64) string {
+	if previous == current {
+		return "unchanged"
+	}
+	if previous == 0 {
+		return "new"
+	}
+
+	delta := current - previous
+	sign := "+"
+	magnitude := delta
+	if delta < 0 {
+		sign = "-"
+		magnitude = -delta
+	}
+	percent := float64(delta) / float64(previous) * 100
+	return fmt.Sprintf("%s%s, %+.1f%%", sign, formatSize(magnitude), percent)
+}
+
+// sizeDelta returns the formatted delta for one side ("server", "client" or
+// "total") of a resource against the comparison baseline, or "" when no
+// baseline is set.
+func (b *Builder) sizeDelta(s ResourceSize, side string) string {
+	if b.baseline == nil {
+		return ""
+	}
+	previous, ok := b.baseline.Resource(s.Name)
+	if !ok {
+		return "new"
+	}
+	switch side {
+	case "server":
+		return formatSizeDelta(previous.Server, s.ServerSize)
+	case "client":
+		return formatSizeDelta(previous.Client, s.ClientSize)
+	default:
+		return formatSizeDelta(previous.Total, s.TotalSize)
+	}
+}
+
+// saveSnapshot writes the sizes of a successful build when --save-snapshot
+// was given. A selective build updates the built resources in the existing
+// snapshot instead of replacing it.
+func (b *Builder) saveSnapshot(results []BuildResult) error {
+	if b.report.SnapshotPath == "" {
+		return nil
+	}
+	snapshot := NewSizeSnapshot(b.getResourceSizes(results))
+	if b.selection.Active() {
+		previous, err := LoadSizeSnapshot(b.report.SnapshotPath)
+		if err != nil {
+			return err
+		}
+		snapshot = previous.Update(snapshot)
+	}
+	if err := snapshot.Save(b.report.SnapshotPath); err != nil {
+		return fmt.Errorf("failed to save size snapshot: %w", err)
+	}
+	return nil
+}
+
+// checkSelectiveSnapshot refuses --save-snapshot on a selective build when
+// there is no snapshot to update, since it would hold only some resources.
+func (b *Builder) checkSelectiveSnapshot() error {
+	if b.report.SnapshotPath == "" || !b.selection.Active() {
+		return nil
+	}
+	if _, err := os.Stat(b.report.SnapshotPath); err != nil {
+		return fmt.Errorf("--save-snapshot on a selective build updates an existing snapshot, and %s was not found; save one with a full build first", b.report.SnapshotPath)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package builder
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func TestFormatSizeDelta(t *testing.T) {
+	cases := []struct {
+		previous, current int64
+		expected          string
+	}{
+		{1024, 1024, "unchanged"},
+		{0, 2048, "new"},
+		{100 * 1024, 142 * 1024, "+42.0 KB, +42.0%"},
+		{2048, 1024, "-1.0 KB, -50.0%"},
+	}
+	for _, c := range cases {
+		if got := formatSizeDelta(c.previous, c.current); got != c.expected {
+			t.Errorf("formatSizeDelta(%d, %d) = %q, expected %q", c.previous, c.current, got, c.expected)
+		}
+	}
+}
+
+func TestSizeSnapshotSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "snapshot.json")
+	snapshot := NewSizeSnapshot([]ResourceSize{
+		{Name: "chat", ServerSize: 10, ClientSize: 20, TotalSize: 30},
+		{Name: "chat/ui", TotalSize: 5, IsViews: true},
+	})
+	if err := snapshot.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadSizeSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSizeSnapshot failed: %v", err)
+	}
+	if chat, ok := loaded.Resource("chat"); !ok || chat.Client != 20 {
+		t.Fatalf("unexpected chat entry: %+v", chat)
+	}
+	if ui, ok := loaded.Resource("chat/ui"); !ok || !ui.Views {
+		t.Fatalf("unexpected views entry: %+v", ui)
+	}
+	if loaded.Total() != 35 {
+		t.Fatalf("expected total 35, got %d", loaded.Total())
+	}
+
+	if _, err := LoadSizeSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected missing snapshot to fail")
+	}
+}
+
+func TestShowSummaryPlainComparesAgainstBaseline(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "build")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "server.js"), strings.Repeat("s", 1000))
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "client.js"), strings.Repeat("c", 3000))
+	writeCacheTestFile(t, filepath.Join(outDir, "radio", "server.js"), strings.Repeat("s", 100))
+
+	b := New(&config.Config{OutDir: outDir})
+	var out bytes.Buffer
+	b.SetOutput(&out)
+	b.SetBaseline(&SizeSnapshot{
+		Version: sizeSnapshotVersion,
+		Resources: []SnapshotResource{
+			{Name: "chat", Server: 1000, Client: 2000, Total: 3000},
+			{Name: "admin", Server: 500, Total: 500},
+		},
+	})
+
+	results := []BuildResult{
+		{Task: BuildTask{ResourceName: "chat", Type: TypeResource}, Success: true, Duration: time.Second},
+		{Task: BuildTask{ResourceName: "radio", Type: TypeResource}, Success: true},
+	}
+	b.showSummary(results, true)
+
+	summary := out.String()
+	for _, want := range []string{
+		"- chat: server=1000 B (unchanged) client=2.9 KB (+1000 B, +50.0%)",
+		"- radio: server=100 B (new) client=-",
+		"- admin: removed (was 500 B)",
+		"Total: 4.0 KB (+600 B, +17.1%)",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+}
+
+func TestSelectiveBuildComparesSelectedResources(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "build")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "server.js"), strings.Repeat("s", 1500))
+
+	b := New(&config.Config{OutDir: outDir})
+	var out bytes.Buffer
+	b.SetOutput(&out)
+	b.SetSelection(Selection{Resources: []string{"chat"}})
+	b.SetBaseline(&SizeSnapshot{
+		Version: sizeSnapshotVersion,
+		Resources: []SnapshotResource{
+			{Name: "chat", Server: 1000, Total: 1000},
+			{Name: "admin", Server: 500, Total: 500},
+		},
+	})
+	b.showSummary([]BuildResult{{Task: BuildTask{ResourceName: "chat", Type: TypeResource}, Success: true}}, true)
+
+	summary := out.String()
+	if strings.Contains(summary, "admin") {
+		t.Errorf("expected unselected resources not to be reported as removed, got:\n%s", summary)
+	}
+	if !strings.Contains(summary, "Total: 1.5 KB (+500 B, +50.0%)") {
+		t.Errorf("expected the total to be compared for chat only, got:\n%s", summary)
+	}
+}
+
+func TestSelectiveBuildUpdatesSnapshot(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "build")
+	writeCacheTestFile(t, filepath.Join(outDir, "chat", "server.js"), strings.Repeat("s", 1500))
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	b := New(&config.Config{OutDir: outDir})
+	b.SetSelection(Selection{Resources: []string{"chat"}})
+	b.SetReport(ReportOptions{SnapshotPath: path})
+	if err := b.checkSelectiveSnapshot(); err == nil || !strings.Contains(err.Error(), "full build") {
+		t.Fatalf("expected a selective build without a snapshot to be refused, got %v", err)
+	}
+
+	previous := &SizeSnapshot{
+		Version: sizeSnapshotVersion,
+		Resources: []SnapshotResource{
+			{Name: "admin", Server: 500, Total: 500},
+			{Name: "chat", Server: 1000, Total: 1000},
+		},
+	}
+	if err := previous.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.checkSelectiveSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.saveSnapshot([]BuildResult{{Task: BuildTask{ResourceName: "chat", Type: TypeResource}, Success: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadSizeSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, _ := saved.Resource("admin")
+	chat, _ := saved.Resource("chat")
+	if len(saved.Resources) != 2 || admin.Total != 500 || chat.Total != 1500 {
+		t.Errorf("expected chat to be updated and admin kept, got %+v", saved.Resources)
+	}
+}
@@ -0,0 +1,226 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// phasesMarker prefixes the output line on which the build scripts send the
+// timings of a successful build (see reportPhases in build.js).
+const phasesMarker = "::opencore-phases::"
+
+// Trace lanes. The CLI's own steps run on the main thread of the first
+// process; every build worker is a process with one thread for its tasks
+// and one per phase, since the server and client builds of a task overlap.
+const (
+	tracePid        = 1
+	traceMainThread = 1
+	traceTaskThread = 1
+)
+
+// tracePhaseThreads are the threads of a worker process, by phase.
+var tracePhaseThreads = []string{"autoload", "server", "client", "views", "dependencies"}
+
+// TracePhase is a timed part of a task's build, such as its server bundle.
+type TracePhase struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Tracer records a build timeline in the Chrome trace-event format, which
+// chrome://tracing and ui.perfetto.dev can open. A nil Tracer records
+// nothing, so callers do not need to check whether tracing is enabled.
+type Tracer struct {
+	mu      sync.Mutex
+	start   time.Time
+	events  []traceEvent
+	workers map[int]bool
+}
+
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"` // microseconds since the trace started
+	Dur  int64          `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// NewTracer starts a trace.
+func NewTracer() *Tracer {
+	return &Tracer{start: time.Now(), workers: make(map[int]bool)}
+}
+
+// Span runs fn and records it as a step of the CLI.
+func (t *Tracer) Span(name string, fn func() error) error {
+	if t == nil {
+		return fn()
+	}
+	start := time.Now()
+	err := fn()
+	args := map[string]any{}
+	if err != nil {
+		args["error"] = err.Error()
+	}
+	t.add(traceEvent{Name: name, Cat: "cli", Pid: tracePid, Tid: traceMainThread, Args: args}, start, time.Now())
+	return err
+}
+
+// task records a task built by worker, with the phases its build reported.
+func (t *Tracer) task(worker int, start, end time.Time, result BuildResult) {
+	if t == nil {
+		return
+	}
+	args := map[string]any{
+		"type":    string(result.Task.Type),
+		"path":    filepath.ToSlash(result.Task.Path),
+		"success": result.Success,
+	}
+	if result.Cached {
+		args["cached"] = true
+	}
+	if result.Error != nil {
+		args["error"] = result.Error.Error()
+	}
+
+	pid := tracePid + worker
+	t.add(traceEvent{Name: result.Task.ResourceName, Cat: "task", Pid: pid, Tid: traceTaskThread, Args: args}, start, end)
+	phases := result.Phases
+	if result.Task.Type == TypeViews && !result.Cached {
+		phases = append(phases, TracePhase{Name: "views", Start: start, End: end})
+	}
+	for _, phase := range phases {
+		t.add(traceEvent{Name: result.Task.ResourceName, Cat: phase.Name, Pid: pid, Tid: tracePhaseThread(phase.Name)}, phase.Start, phase.End)
+	}
+
+	t.mu.Lock()
+	t.workers[worker] = true
+	t.mu.Unlock()
+}
+
+func tracePhaseThread(name string) int {
+	for i, phase := range tracePhaseThreads {
+		if phase == name {
+			return traceTaskThread + 1 + i
+		}
+	}
+	return traceTaskThread + 1 + len(tracePhaseThreads)
+}
+
+func (t *Tracer) add(event traceEvent, start, end time.Time) {
+	event.Ph = "X"
+	event.Ts = start.Sub(t.start).Microseconds()
+	event.Dur = max(end.Sub(start).Microseconds(), 1)
+	t.mu.Lock()
+	t.events = append(t.events, event)
+	t.mu.Unlock()
+}
+
+// WriteFile writes the trace as JSON to path.
+func (t *Tracer) WriteFile(path string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metadata := func(name string, pid, tid int, args map[string]any) traceEvent {
+		return traceEvent{Name: name, Ph: "M", Pid: pid, Tid: tid, Args: args}
+	}
+	events := []traceEvent{
+		metadata("process_name", tracePid, 0, map[string]any{"name": "opencore build"}),
+		metadata("thread_name", tracePid, traceMainThread, map[string]any{"name": "main"}),
+	}
+	workers := make([]int, 0, len(t.workers))
+	for worker := range t.workers {
+		workers = append(workers, worker)
+	}
+	sort.Ints(workers)
+	for _, worker := range workers {
+		pid := tracePid + worker
+		events = append(events,
+			metadata("process_name", pid, 0, map[string]any{"name": fmt.Sprintf("worker %d", worker)}),
+			metadata("process_sort_index", pid, 0, map[string]any{"sort_index": pid}),
+			metadata("thread_name", pid, traceTaskThread, map[string]any{"name": "tasks"}),
+		)
+		for _, phase := range tracePhaseThreads {
+			events = append(events, metadata("thread_name", pid, tracePhaseThread(phase), map[string]any{"name": phase}))
+		}
+	}
+	events = append(events, t.events...)
+
+	data, err := json.MarshalIndent(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+type workerKey struct{}
+
+// withWorker marks ctx as belonging to the numbered pool worker.
+func withWorker(ctx context.Context, worker int) context.Context {
+	return context.WithValue(ctx, workerKey{}, worker)
+}
+
+// workerFromContext returns the pool worker running a task; sequential
+// builds run on worker 1.
+func workerFromContext(ctx context.Context) int {
+	if worker, ok := ctx.Value(workerKey{}).(int); ok {
+		return worker
+	}
+	return 1
+}
+
+// extractPhases removes the phases line from a build's output and returns
+// the remaining output and the phases.
+func extractPhases(output string) (string, []TracePhase) {
+	if !strings.Contains(output, phasesMarker) {
+		return output, nil
+	}
+
+	var phases []TracePhase
+	var kept []string
+	for _, line := range strings.Split(output, "\n") {
+		payload, found := strings.CutPrefix(strings.TrimSpace(line), phasesMarker)
+		if !found {
+			kept = append(kept, line)
+			continue
+		}
+		var reported []struct {
+			Name  string  `json:"name"`
+			Start float64 `json:"start"` // epoch milliseconds
+			End   float64 `json:"end"`
+		}
+		if err := json.Unmarshal([]byte(payload), &reported); err != nil {
+			kept = append(kept, line)
+			continue
+		}
+		for _, r := range reported {
+			phases = append(phases, TracePhase{Name: r.Name, Start: epochMillis(r.Start), End: epochMillis(r.End)})
+		}
+	}
+	return strings.Join(kept, "\n"), phases
+}
+
+func epochMillis(ms float64) time.Time {
+	return time.UnixMicro(int64(ms * 1000))
+}
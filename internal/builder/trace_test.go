@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTracerWritesChromeTraceEvents(t *testing.T) {
+	tracer := NewTracer()
+	if err := tracer.Span("load config", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracer.Span("deploy", func() error { return errors.New("boom") }); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected the span to return fn's error, got %v", err)
+	}
+
+	start := time.Now()
+	server := TracePhase{Name: "server", Start: start, End: start.Add(40 * time.Millisecond)}
+	client := TracePhase{Name: "client", Start: start.Add(time.Millisecond), End: start.Add(30 * time.Millisecond)}
+	tracer.task(2, start, start.Add(50*time.Millisecond), BuildResult{
+		Task:    BuildTask{ResourceName: "chat", Type: TypeResource, Path: "resources/chat"},
+		Success: true,
+		Phases:  []TracePhase{server, client},
+	})
+	tracer.task(1, start, start.Add(20*time.Millisecond), BuildResult{
+		Task:    BuildTask{ResourceName: "chat/ui", Type: TypeViews},
+		Success: true,
+	})
+
+	path := filepath.Join(t.TempDir(), "traces", "build.json")
+	if err := tracer.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatalf("trace is not valid JSON: %v", err)
+	}
+
+	find := func(name, cat string) *traceEvent {
+		for i, event := range trace.TraceEvents {
+			if event.Name == name && event.Cat == cat {
+				return &trace.TraceEvents[i]
+			}
+		}
+		t.Fatalf("missing %s event %q", cat, name)
+		return nil
+	}
+	if event := find("deploy", "cli"); event.Pid != tracePid || event.Args["error"] != "boom" {
+		t.Errorf("expected the failed step on the main thread with its error, got %+v", event)
+	}
+	task := find("chat", "task")
+	if task.Ph != "X" || task.Pid != tracePid+2 || task.Dur != 50000 {
+		t.Errorf("unexpected task event %+v", task)
+	}
+	if event := find("chat", "server"); event.Pid != task.Pid || event.Tid != tracePhaseThread("server") || event.Dur != 40000 {
+		t.Errorf("unexpected server phase %+v", event)
+	}
+	if event := find("chat", "client"); event.Tid == tracePhaseThread("server") || event.Ts != task.Ts+1000 {
+		t.Errorf("expected the client phase on its own thread, got %+v", event)
+	}
+	if event := find("chat/ui", "views"); event.Pid != tracePid+1 || event.Dur != 20000 {
+		t.Errorf("expected views tasks to record a views phase, got %+v", event)
+	}
+
+	workers := 0
+	for _, event := range trace.TraceEvents {
+		if event.Ph == "M" && event.Name == "process_name" && event.Pid != tracePid {
+			workers++
+		}
+	}
+	if workers != 2 {
+		t.Errorf("expected a process per worker, got %d", workers)
+	}
+}
+
+func TestNilTracerRunsSpans(t *testing.T) {
+	var tracer *Tracer
+	ran := false
+	if err := tracer.Span("build", func() error { ran = true; return nil }); err != nil || !ran {
+		t.Fatalf("expected a nil tracer to run the span, ran=%v err=%v", ran, err)
+	}
+	tracer.task(1, time.Now(), time.Now(), BuildResult{})
+	if err := tracer.WriteFile(filepath.Join(t.TempDir(), "trace.json")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractPhases(t *testing.T) {
+	output := "[core] built\n::opencore-phases::[{\"name\":\"server\",\"start\":1700000000000.5,\"end\":1700000000250}]\ndone"
+	rest, phases := extractPhases(output)
+	if rest != "[core] built\ndone" {
+		t.Errorf("expected the marker line to be removed, got %q", rest)
+	}
+	if len(phases) != 1 || phases[0].Name != "server" {
+		t.Fatalf("unexpected phases %+v", phases)
+	}
+	if got := phases[0].End.Sub(phases[0].Start); got != 249500*time.Microsecond {
+		t.Errorf("expected a 249.5ms phase, got %v", got)
+	}
+}
+
+func TestWorkerFromContext(t *testing.T) {
+	if got := workerFromContext(context.Background()); got != 1 {
+		t.Errorf("expected sequential builds on worker 1, got %d", got)
+	}
+	if got := workerFromContext(withWorker(context.Background(), 3)); got != 3 {
+		t.Errorf("expected worker 3, got %d", got)
+	}
+}
@@ -13,7 +13,8 @@ const (
 	TypeResource   ResourceType = "resource"
 	TypeStandalone ResourceType = "standalone"
 	TypeViews      ResourceType = "views"
-	TypeCopy       ResourceType = "copy" // standalone without compilation
+	TypeCopy       ResourceType = "copy"   // standalone without compilation
+	TypeShared     ResourceType = "shared" // generated shared dependency resource, only in results
 )
 
 // BuildTask represents a single build task
@@ -23,7 +24,8 @@ type BuildTask struct {
 	Type           ResourceType
 	OutDir         string
 	Options        BuildOptions
-	CustomCompiler string // Path to custom compiler, empty = use embedded
+	CustomCompiler string   // Path to custom compiler, empty = use embedded
+	Modules        []string // Shared module directories the sources import
 }
 
 // BuildSideOptions represents per-side build options that are forwarded to build.js.
@@ -93,6 +95,7 @@ type BuildOptions struct {
 	NUI                  bool                        `json:"nui"`
 	Minify               bool                        `json:"minify"`
 	SourceMaps           bool                        `json:"sourceMaps"`
+	DevSourceMaps        bool                        `json:"devSourceMaps,omitempty"` // external maps for dev stack traces
 	LogLevel             string                      `json:"logLevel"`
 	Target               string                      `json:"target"`
 	Runtime              string                      `json:"runtime,omitempty"`
@@ -113,7 +116,17 @@ type BuildOptions struct {
 	ServerBinaries       []string                    `json:"serverBinaries,omitempty"`
 	ServerBinaryPlatform string                      `json:"serverBinaryPlatform,omitempty"`
 	EnvironmentAliases   map[string]string           `json:"environmentAliases,omitempty"`
+	Modules              map[string]string           `json:"modules,omitempty"` // shared module name → directory
 	DependencyResolution *DependencyResolutionConfig `json:"dependencyResolution,omitempty"`
+	DependencyCacheDir   string                      `json:"dependencyCacheDir,omitempty"` // shared by the projects of a workspace
+	Metafile             *MetafileOptions            `json:"metafile,omitempty"`
+}
+
+// MetafileOptions tells build.js where to write the esbuild metafile of each
+// side. An empty path skips that side.
+type MetafileOptions struct {
+	Server string `json:"server,omitempty"`
+	Client string `json:"client,omitempty"`
 }
 
 // EntryPoints defines entry points for core builds
@@ -129,6 +142,12 @@ type BuildResult struct {
 	Duration time.Duration
 	Error    error
 	Output   string
+	Cached   bool // outputs were restored from the build cache
+	Skipped  bool // not built because a dependency failed
+
+	Diagnostics []Diagnostic // compiler errors and warnings, de-duplicated across sides
+	Phases      []TracePhase // timed parts of the build, for --trace
+	Inputs      []string     // absolute paths of the files the build read
 }
 
 // BuildProgress represents build progress for UI
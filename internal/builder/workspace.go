@@ -0,0 +1,40 @@
+package builder
+
+import (
+	"path/filepath"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+// ForProject returns a builder for cfg, another project of the same
+// workspace. It shares b's build daemon, tracer and output, so only b must
+// be closed. The build scripts are extracted into each project and its
+// daemon workers serve that project alone, so every project builds with the
+// toolchain in its own node_modules. The caller switches to the project root
+// before building, as for New.
+func (b *Builder) ForProject(cfg *config.Config) *Builder {
+	project := &Builder{
+		config:           cfg,
+		resourceBuilder:  b.resourceBuilder,
+		deployer:         NewDeployer(cfg),
+		out:              b.out,
+		tracer:           b.tracer,
+		devSourceMaps:    b.devSourceMaps,
+		validatedSources: make(map[string]bool),
+	}
+	if cfg.Build.CacheEnabled() {
+		project.cache = projectBuildCache(cfg)
+	}
+	return project
+}
+
+// dependencyCacheDir is where build.js caches installed dependencies. The
+// projects of a workspace share the workspace's cache; "" keeps the
+// project's own node_modules/.cache.
+func (b *Builder) dependencyCacheDir() string {
+	root := b.config.WorkspaceRoot()
+	if root == "" {
+		return ""
+	}
+	return filepath.Join(root, "node_modules", ".cache", "opencore", "dependencies")
+}
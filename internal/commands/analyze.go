@@ -0,0 +1,356 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/newcore-network/opencore-cli/internal/analyze"
+	"github.com/newcore-network/opencore-cli/internal/builder"
+	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+func NewAnalyzeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze [resource]",
+		Short: "Explore what makes up your bundles",
+		Long: `Inspect the esbuild metafiles written by 'opencore build --metafile': the heaviest
+modules, packages bundled by more than one resource, and why a package was included.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runAnalyze,
+	}
+
+	cmd.Flags().String("html", "", "Write a static HTML report to a file instead of opening the explorer")
+	cmd.Flags().String("why", "", "Show the import chains that pull a package into each bundle")
+	cmd.Flags().Int("top", 25, "Number of heaviest modules to list")
+	cmd.Flags().String("output", "auto", "Output mode (auto|tui|plain)")
+
+	return cmd
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	// Resolve the report path before switching to the project root.
+	htmlPath, _ := cmd.Flags().GetString("html")
+	htmlPath = callerPath(htmlPath)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	root, err := config.FindProjectRoot(wd)
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(root); err != nil {
+		return fmt.Errorf("failed to switch to project root: %w", err)
+	}
+
+	resource := ""
+	if len(args) > 0 {
+		resource = args[0]
+	}
+	bundles, err := analyze.LoadBundles(builder.MetafileDir, resource)
+	if err != nil {
+		return fmt.Errorf("failed to read metafiles: %w", err)
+	}
+	if len(bundles) == 0 {
+		if resource != "" {
+			return fmt.Errorf("no metafiles found for %q in %s; run 'opencore build --metafile' first", resource, builder.MetafileDir)
+		}
+		return fmt.Errorf("no metafiles found in %s; run 'opencore build --metafile' first", builder.MetafileDir)
+	}
+
+	top, _ := cmd.Flags().GetInt("top")
+	report := analyze.NewReport(bundles, top)
+
+	if why, _ := cmd.Flags().GetString("why"); why != "" {
+		return printWhy(cmd.OutOrStdout(), bundles, why)
+	}
+
+	if htmlPath != "" {
+		if err := writeHTMLReport(htmlPath, report); err != nil {
+			return err
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Report written to %s", htmlPath)))
+		return nil
+	}
+
+	outputModeValue, _ := cmd.Flags().GetString("output")
+	outputMode, err := builder.ParseOutputMode(outputModeValue)
+	if err != nil {
+		return err
+	}
+	if outputMode == builder.OutputModePlain || (outputMode == builder.OutputModeAuto && ui.IsNonInteractiveSession()) {
+		printAnalyzeReport(cmd.OutOrStdout(), report)
+		return nil
+	}
+
+	_, err = tea.NewProgram(newAnalyzeModel(report), tea.WithAltScreen()).Run()
+	return err
+}
+
+// writeHTMLReport writes the HTML report to path. A failure to flush the
+// file on close is an error too.
+func writeHTMLReport(path string, report analyze.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	if err := report.WriteHTML(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return nil
+}
+
+func printWhy(w io.Writer, bundles []analyze.Bundle, pkg string) error {
+	chains := analyze.WhyIncluded(bundles, pkg)
+	if len(chains) == 0 {
+		return fmt.Errorf("package %q is not included in any analyzed bundle", pkg)
+	}
+	for _, chain := range chains {
+		fmt.Fprintf(w, "%s\n", chain.Bundle)
+		for i, step := range chain.Path {
+			fmt.Fprintf(w, "  %s%s\n", strings.Repeat("  ", i), step)
+		}
+	}
+	return nil
+}
+
+func printAnalyzeReport(w io.Writer, report analyze.Report) {
+	fmt.Fprintln(w, "Bundles")
+	for _, bundle := range report.Bundles {
+		fmt.Fprintf(w, "- %s: %s\n", bundle.Name(), analyze.FormatSize(bundle.Bytes()))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Heaviest modules")
+	for _, module := range report.Modules {
+		fmt.Fprintf(w, "- %10s  %s  [%s]\n", analyze.FormatSize(module.Bytes), module.Path, module.Bundle)
+	}
+
+	fmt.Fprintln(w)
+	if len(report.Duplicates) == 0 {
+		fmt.Fprintln(w, "No package is bundled by more than one resource")
+		return
+	}
+	fmt.Fprintln(w, "Duplicate packages")
+	for _, dup := range report.Duplicates {
+		fmt.Fprintf(w, "- %s: %s across %s\n", dup.Name, analyze.FormatSize(dup.Bytes), strings.Join(dup.BundleNames(), ", "))
+		for _, chain := range report.Why[dup.Name] {
+			fmt.Fprintf(w, "    %s: %s\n", chain.Bundle, strings.Join(chain.Path, " -> "))
+		}
+	}
+}
+
+// ============================================================================
+// BubbleTea model for the bundle explorer
+// ============================================================================
+
+type analyzeTab int
+
+const (
+	tabModules analyzeTab = iota
+	tabPackages
+	tabDuplicates
+)
+
+var analyzeTabNames = []string{"Modules", "Packages", "Duplicates"}
+
+type analyzeModel struct {
+	report  analyze.Report
+	tab     analyzeTab
+	cursor  int
+	offset  int
+	height  int
+	width   int
+	details *analyze.PackageUsage
+}
+
+func newAnalyzeModel(report analyze.Report) analyzeModel {
+	return analyzeModel{report: report, height: 24, width: 100}
+}
+
+func (m analyzeModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m analyzeModel) rowCount() int {
+	switch m.tab {
+	case tabModules:
+		return len(m.report.Modules)
+	case tabPackages:
+		return len(m.report.Packages)
+	default:
+		return len(m.report.Duplicates)
+	}
+}
+
+// visibleRows is the number of list rows that fit below the header.
+func (m analyzeModel) visibleRows() int {
+	rows := m.height - 7
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+func (m analyzeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			m.details = nil
+		case "tab", "right", "l":
+			if m.details == nil {
+				m.tab = (m.tab + 1) % analyzeTab(len(analyzeTabNames))
+				m.cursor, m.offset = 0, 0
+			}
+		case "shift+tab", "left", "h":
+			if m.details == nil {
+				m.tab = (m.tab + analyzeTab(len(analyzeTabNames)) - 1) % analyzeTab(len(analyzeTabNames))
+				m.cursor, m.offset = 0, 0
+			}
+		case "up", "k":
+			if m.details == nil && m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.details == nil && m.cursor < m.rowCount()-1 {
+				m.cursor++
+			}
+		case "enter":
+			if m.details == nil {
+				switch m.tab {
+				case tabPackages:
+					if m.cursor < len(m.report.Packages) {
+						m.details = &m.report.Packages[m.cursor]
+					}
+				case tabDuplicates:
+					if m.cursor < len(m.report.Duplicates) {
+						m.details = &m.report.Duplicates[m.cursor]
+					}
+				}
+			}
+		}
+
+		if m.cursor < m.offset {
+			m.offset = m.cursor
+		}
+		if m.cursor >= m.offset+m.visibleRows() {
+			m.offset = m.cursor - m.visibleRows() + 1
+		}
+	}
+	return m, nil
+}
+
+func (m analyzeModel) View() string {
+	var b strings.Builder
+	b.WriteString(ui.TitleStyle.Render("OpenCore bundle analysis"))
+	b.WriteString("\n")
+
+	if m.details != nil {
+		b.WriteString(m.renderDetails())
+		b.WriteString("\n" + ui.Muted("esc back • q quit"))
+		return b.String()
+	}
+
+	tabs := make([]string, 0, len(analyzeTabNames))
+	for i, name := range analyzeTabNames {
+		if analyzeTab(i) == m.tab {
+			tabs = append(tabs, lipgloss.NewStyle().Bold(true).Foreground(ui.PrimaryColor).Render("["+name+"]"))
+		} else {
+			tabs = append(tabs, ui.Muted(" "+name+" "))
+		}
+	}
+	b.WriteString(strings.Join(tabs, " ") + "\n\n")
+
+	rows := m.rows()
+	if len(rows) == 0 {
+		b.WriteString(ui.Muted("  Nothing to show") + "\n")
+	}
+	end := m.offset + m.visibleRows()
+	if end > len(rows) {
+		end = len(rows)
+	}
+	for i := m.offset; i < end; i++ {
+		line := rows[i]
+		if runes := []rune(line); m.width > 4 && len(runes) > m.width-4 {
+			line = string(runes[:m.width-4])
+		}
+		if i == m.cursor {
+			b.WriteString(lipgloss.NewStyle().Foreground(ui.PrimaryColor).Render("› "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	help := "tab switch view • ↑/↓ move • q quit"
+	if m.tab != tabModules {
+		help = "tab switch view • ↑/↓ move • enter why • q quit"
+	}
+	b.WriteString("\n" + ui.Muted(help))
+	return b.String()
+}
+
+func (m analyzeModel) rows() []string {
+	var rows []string
+	switch m.tab {
+	case tabModules:
+		for _, module := range m.report.Modules {
+			rows = append(rows, fmt.Sprintf("%10s  %-22s %s", analyze.FormatSize(module.Bytes), module.Bundle, module.Path))
+		}
+	case tabPackages:
+		for _, pkg := range m.report.Packages {
+			marker := " "
+			if pkg.Duplicated() {
+				marker = "!"
+			}
+			rows = append(rows, fmt.Sprintf("%10s %s %-30s %s", analyze.FormatSize(pkg.Bytes), marker, pkg.Name, strings.Join(pkg.BundleNames(), ", ")))
+		}
+	case tabDuplicates:
+		for _, pkg := range m.report.Duplicates {
+			rows = append(rows, fmt.Sprintf("%10s  %-30s %s", analyze.FormatSize(pkg.Bytes), pkg.Name, strings.Join(pkg.Resources(), ", ")))
+		}
+	}
+	return rows
+}
+
+func (m analyzeModel) renderDetails() string {
+	pkg := m.details
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(pkg.Name))
+	b.WriteString(ui.Muted(fmt.Sprintf("  %s total", analyze.FormatSize(pkg.Bytes))) + "\n\n")
+
+	chains := m.report.Why[pkg.Name]
+	if chains == nil {
+		chains = analyze.WhyIncluded(m.report.Bundles, pkg.Name)
+	}
+	for _, name := range pkg.BundleNames() {
+		b.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Foreground(ui.InfoColor).Render(name), ui.Muted(analyze.FormatSize(pkg.Bundles[name]))))
+		for _, chain := range chains {
+			if chain.Bundle != name {
+				continue
+			}
+			for i, step := range chain.Path {
+				b.WriteString(fmt.Sprintf("  %s%s\n", strings.Repeat("  ", i), step))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -1,32 +1,165 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/newcore-network/opencore-cli/internal/builder"
 	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
 )
 
 func NewBuildCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "build",
+		Use:   "build [resource...]",
 		Short: "Build all resources for production",
-		Long:  "Compile TypeScript to JavaScript and prepare resources for deployment.",
-		RunE:  runBuild,
+		Long: `Compile TypeScript to JavaScript and prepare resources for deployment.
+
+Pass resource names or globs to build only those resources ("chat" also builds
+chat/ui, "chat/ui" builds only the views). --changed-since builds only the
+resources touched by files changed since a git ref.
+
+--env accepts a comma-separated list to build several environments in one run,
+each into <outDir>/<env>. An environment is only deployed when it sets its own
+destination.
+
+In a workspace (opencore.workspace.ts), build runs for every project, or for
+those selected with --project, sharing the build workers and the dependency
+cache. Inside a project directory it builds that project only.
+
+Examples:
+  opencore build
+  opencore build chat admin
+  opencore build 'core/ui' 'shop-*'
+  opencore build --changed-since origin/main
+  opencore build --env production
+  opencore build --env staging,production
+  opencore build --project fivem-server,redm-server
+  opencore build --trace build-trace.json`,
+		RunE: runBuild,
 	}
 
 	cmd.Flags().String("output", "auto", "Output mode (auto|tui|plain)")
-	cmd.Flags().StringP("environment", "e", "", "Environment to build for (e.g. development, production)")
+	addEnvironmentFlag(cmd)
+	addProjectFlag(cmd)
+	addFailurePolicyFlags(cmd)
+	cmd.Flags().String("changed-since", "", "Only build resources with files changed since this git ref")
+	cmd.Flags().Bool("no-cache", false, "Rebuild every resource, ignoring the incremental build cache")
+	cmd.Flags().Bool("no-daemon", false, "Start a node process per task instead of using the build daemon")
+	cmd.Flags().Bool("metafile", false, "Write esbuild metafiles to "+builder.MetafileDir+" for opencore analyze")
+	cmd.Flags().String("json", "", "Write a JSON build report to --json=<file>, or to stdout when no file is given")
+	cmd.Flags().Lookup("json").NoOptDefVal = "-"
+	cmd.Flags().String("junit", "", "Write a JUnit XML build report to --junit=<file>, or to stdout when no file is given")
+	cmd.Flags().Lookup("junit").NoOptDefVal = "-"
+	cmd.Flags().String("compare", "", "Compare bundle sizes against a snapshot saved with --save-snapshot")
+	cmd.Flags().String("save-snapshot", "", "Save bundle sizes to --save-snapshot=<file> (default "+builder.DefaultSnapshotPath+")")
+	cmd.Flags().Lookup("save-snapshot").NoOptDefVal = builder.DefaultSnapshotPath
+	cmd.Flags().String("trace", "", "Write a Chrome trace-event timeline of the build to a file")
 
 	return cmd
 }
 
+// callerPath resolves a file flag against the directory the command runs in,
+// before it switches to the project root. "" and "-" (stdout) stay as they are.
+func callerPath(path string) string {
+	if path == "" || path == "-" {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// optionalFileFlags take their file after "=", with the extension of the
+// files they write.
+var optionalFileFlags = []struct{ name, ext string }{
+	{"json", ".json"},
+	{"junit", ".xml"},
+	{"save-snapshot", ".json"},
+}
+
+// misplacedFlagFile rejects `--json report.json`. The file of a flag with an
+// optional value must follow "=", so report.json would be read as a resource
+// to build.
+func misplacedFlagFile(cmd *cobra.Command, args []string) error {
+	for _, flag := range optionalFileFlags {
+		f := cmd.Flags().Lookup(flag.name)
+		if f == nil || !f.Changed || f.Value.String() != f.NoOptDefVal {
+			continue
+		}
+		for _, arg := range args {
+			if strings.EqualFold(filepath.Ext(arg), flag.ext) {
+				return fmt.Errorf("%s was read as a resource to build; use --%s=%s to write it", arg, flag.name, arg)
+			}
+		}
+	}
+	return nil
+}
+
 func runBuild(cmd *cobra.Command, args []string) error {
+	if err := misplacedFlagFile(cmd, args); err != nil {
+		return err
+	}
+
+	var tracer *builder.Tracer
+	tracePath, _ := cmd.Flags().GetString("trace")
+	if tracePath != "" {
+		tracePath = callerPath(tracePath)
+		tracer = builder.NewTracer()
+	}
+
+	outputModeValue, _ := cmd.Flags().GetString("output")
+	outputMode, err := builder.ParseOutputMode(outputModeValue)
+	if err != nil {
+		return err
+	}
+
+	jsonPath, _ := cmd.Flags().GetString("json")
+	junitPath, _ := cmd.Flags().GetString("junit")
+	if jsonPath == "-" && junitPath == "-" {
+		return fmt.Errorf("--json and --junit cannot both write to stdout")
+	}
+	jsonPath, junitPath = callerPath(jsonPath), callerPath(junitPath)
+	snapshotPath, _ := cmd.Flags().GetString("save-snapshot")
+	if snapshotPath != builder.DefaultSnapshotPath {
+		// The default snapshot belongs to the project; an explicit file is
+		// relative to the current directory.
+		snapshotPath = callerPath(snapshotPath)
+	}
+	comparePath, _ := cmd.Flags().GetString("compare")
+	comparePath = callerPath(comparePath)
+	report := builder.ReportOptions{JSONPath: jsonPath, JUnitPath: junitPath, SnapshotPath: snapshotPath}
+
+	changedSince, _ := cmd.Flags().GetString("changed-since")
+	selection := builder.Selection{Resources: args, ChangedSince: changedSince}
+
+	workspace, projects, err := workspaceProjects(cmd)
+	if err != nil {
+		return err
+	}
+	if workspace != nil {
+		err = buildWorkspace(cmd, workspace, projects, outputMode, report, comparePath, selection, tracer)
+		if traceErr := tracer.WriteFile(tracePath); traceErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to write trace: %w", traceErr))
+		}
+		return err
+	}
+
 	// Load config
-	cfg, root, err := config.LoadWithProjectRoot()
+	var cfg *config.Config
+	var root string
+	err = tracer.Span("load config", func() (err error) {
+		cfg, root, err = config.LoadWithProjectRoot()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -34,17 +167,243 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to switch to project root: %w", err)
 	}
 
-	if env, _ := cmd.Flags().GetString("environment"); env != "" {
-		cfg.Build.Environment = env
+	configs := []*config.Config{cfg}
+	if envs := environmentNames(cmd); len(envs) > 1 {
+		configs = configs[:0]
+		for _, env := range envs {
+			envCfg, err := cfg.ForEnvironment(env)
+			if err != nil {
+				return err
+			}
+			configs = append(configs, envCfg)
+		}
+	} else if err := applyEnvironmentFlag(cmd, cfg); err != nil {
+		return err
+	}
+	for _, c := range configs {
+		applyBuildFlags(cmd, c)
 	}
 
-	outputModeValue, _ := cmd.Flags().GetString("output")
-	outputMode, err := builder.ParseOutputMode(outputModeValue)
-	if err != nil {
-		return err
+	if len(configs) > 1 {
+		err = buildEnvironments(cmd.Context(), configs, outputMode, report, comparePath, selection, tracer)
+	} else {
+		err = buildProject(cmd.Context(), cfg, outputMode, report, comparePath, selection, tracer)
+	}
+	if traceErr := tracer.WriteFile(tracePath); traceErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to write trace: %w", traceErr))
+	}
+	return err
+}
+
+// applyBuildFlags applies the flags that override build settings. They take
+// precedence over the environment, so this runs after it is applied.
+func applyBuildFlags(cmd *cobra.Command, cfg *config.Config) {
+	applyFailurePolicyFlags(cmd, cfg)
+
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		disabled := false
+		cfg.Build.Cache = &disabled
+	}
+
+	if noDaemon, _ := cmd.Flags().GetBool("no-daemon"); noDaemon {
+		disabled := false
+		cfg.Build.Daemon = &disabled
+	}
+
+	if metafile, _ := cmd.Flags().GetBool("metafile"); metafile {
+		cfg.Build.Metafile = true
+	}
+}
+
+func buildProject(ctx context.Context, cfg *config.Config, mode builder.OutputMode, report builder.ReportOptions, comparePath string, selection builder.Selection, tracer *builder.Tracer) error {
+	var baseline *builder.SizeSnapshot
+	if comparePath != "" {
+		var err error
+		baseline, err = builder.LoadSizeSnapshot(comparePath)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Create builder and build
 	b := builder.New(cfg)
-	return b.BuildWithOutputContext(cmd.Context(), outputMode)
+	defer b.Close()
+	b.SetReport(report)
+	b.SetBaseline(baseline)
+	b.SetSelection(selection)
+	if report.JSONPath == "-" || report.JUnitPath == "-" {
+		// Keep stdout clean for the report
+		b.SetOutput(os.Stderr)
+	}
+	b.SetTracer(tracer)
+	return b.BuildWithOutputContext(ctx, mode)
+}
+
+// environmentBuild is the outcome of one environment of a multi-environment
+// build.
+type environmentBuild struct {
+	config   *config.Config
+	duration time.Duration
+	err      error
+}
+
+// buildEnvironments builds each environment config in turn, each into its
+// own output directory. The builds share the build daemon, the source
+// validation and the views outputs in the build cache. Reports and size
+// snapshots are written per environment, with the environment name added
+// before the file extension.
+func buildEnvironments(ctx context.Context, configs []*config.Config, mode builder.OutputMode, report builder.ReportOptions, comparePath string, selection builder.Selection, tracer *builder.Tracer) error {
+	if report.JSONPath == "-" || report.JUnitPath == "-" {
+		return fmt.Errorf("--json and --junit need a file path when building several environments")
+	}
+	plain := mode == builder.OutputModePlain || (mode == builder.OutputModeAuto && ui.IsNonInteractiveSession())
+
+	base := builder.New(configs[0])
+	defer base.Close()
+	base.SetTracer(tracer)
+
+	builds := make([]environmentBuild, 0, len(configs))
+	var errs []error
+	for _, cfg := range configs {
+		env := cfg.Build.Environment
+		b := base.ForEnvironment(cfg)
+		b.SetReport(builder.ReportOptions{
+			JSONPath:     environmentPath(report.JSONPath, env),
+			JUnitPath:    environmentPath(report.JUnitPath, env),
+			SnapshotPath: environmentPath(report.SnapshotPath, env),
+		})
+		if comparePath != "" {
+			baseline, err := builder.LoadSizeSnapshot(environmentPath(comparePath, env))
+			if err != nil {
+				return err
+			}
+			b.SetBaseline(baseline)
+		}
+		b.SetSelection(selection)
+
+		header := fmt.Sprintf("Environment %s → %s", env, cfg.OutDir)
+		if plain {
+			fmt.Println("\n" + header)
+		} else {
+			fmt.Println("\n" + ui.TitleStyle.Render(header))
+		}
+
+		start := time.Now()
+		err := b.BuildWithOutputContext(ctx, mode)
+		builds = append(builds, environmentBuild{config: cfg, duration: time.Since(start), err: err})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", env, err))
+			if cfg.Build.FailFast || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	printEnvironmentSummary(builds, len(configs), plain)
+	return errors.Join(errs...)
+}
+
+func printEnvironmentSummary(builds []environmentBuild, total int, plain bool) {
+	fmt.Println()
+	if plain {
+		fmt.Println("Environments")
+	} else {
+		fmt.Println(ui.TitleStyle.Render("Environments"))
+	}
+	for _, build := range builds {
+		status := "ok"
+		if build.err != nil {
+			status = "failed"
+		}
+		target := build.config.OutDir
+		if build.config.Destination != "" {
+			target += " → " + build.config.Destination
+		}
+		line := fmt.Sprintf("  %-14s %-7s %-8s %s", build.config.Build.Environment, status, build.duration.Round(time.Millisecond), target)
+		if build.err != nil && !plain {
+			line = ui.ErrorStyle.Render(line)
+		}
+		fmt.Println(line)
+	}
+	if skipped := total - len(builds); skipped > 0 {
+		message := fmt.Sprintf("  %d environment(s) skipped after a failure", skipped)
+		if plain {
+			fmt.Println(message)
+		} else {
+			fmt.Println(ui.Muted(message))
+		}
+	}
+}
+
+// environmentPath adds env before the extension of path, e.g.
+// report.json → report.staging.json. An empty path stays empty.
+func environmentPath(path, env string) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
+
+// environmentNames returns the environments listed in --env, which build
+// accepts as a comma-separated list.
+func environmentNames(cmd *cobra.Command) []string {
+	value, _ := cmd.Flags().GetString("env")
+	if value == "" {
+		value, _ = cmd.Flags().GetString("environment")
+	}
+	return splitList(value)
+}
+
+// splitList splits a comma-separated flag value, dropping blanks and
+// duplicates.
+func splitList(value string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// addEnvironmentFlag registers --env (-e). The older --environment spelling
+// is still accepted.
+func addEnvironmentFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("env", "e", "", "Environment whose overrides to apply (e.g. development, production)")
+	cmd.Flags().String("environment", "", "Alias for --env")
+	_ = cmd.Flags().MarkHidden("environment")
+}
+
+// applyEnvironmentFlag selects the environment given with --env and applies
+// its overrides. It must run before the other flags are applied, since flags
+// take precedence over environments.
+func applyEnvironmentFlag(cmd *cobra.Command, cfg *config.Config) error {
+	envs := environmentNames(cmd)
+	if len(envs) > 1 {
+		return fmt.Errorf("only one environment can be used here, got %s", strings.Join(envs, ", "))
+	}
+	if len(envs) == 1 {
+		cfg.UseEnvironment(envs[0], "--env")
+	}
+	return cfg.ApplyEnvironment()
+}
+
+// addFailurePolicyFlags registers --keep-going and --fail-fast, which override
+// build.failFast.
+func addFailurePolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("keep-going", false, "Build every resource that does not depend on a failed one (default)")
+	cmd.Flags().Bool("fail-fast", false, "Stop starting new builds after the first failure")
+	cmd.MarkFlagsMutuallyExclusive("keep-going", "fail-fast")
+}
+
+func applyFailurePolicyFlags(cmd *cobra.Command, cfg *config.Config) {
+	if keepGoing, _ := cmd.Flags().GetBool("keep-going"); keepGoing {
+		cfg.Build.FailFast = false
+	}
+	if failFast, _ := cmd.Flags().GetBool("fail-fast"); failFast {
+		cfg.Build.FailFast = true
+	}
 }
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentNames(t *testing.T) {
+	cmd := NewBuildCommand()
+	if err := cmd.Flags().Set("env", " staging, production,,staging"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(environmentNames(cmd), ","); got != "staging,production" {
+		t.Errorf("expected staging,production, got %q", got)
+	}
+
+	cmd = NewBuildCommand()
+	if err := cmd.Flags().Set("environment", "production"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(environmentNames(cmd), ","); got != "production" {
+		t.Errorf("expected the --environment alias to be read, got %q", got)
+	}
+}
+
+func TestEnvironmentPath(t *testing.T) {
+	cases := map[string]string{
+		"build-report.json":            "build-report.staging.json",
+		".opencore/size-snapshot.json": ".opencore/size-snapshot.staging.json",
+		"report":                       "report.staging",
+		"":                             "",
+	}
+	for path, want := range cases {
+		if got := environmentPath(path, "staging"); got != want {
+			t.Errorf("%q: expected %q, got %q", path, want, got)
+		}
+	}
+}
+
+func TestCallerPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"report.json":       filepath.Join(wd, "report.json"),
+		"../out/report.xml": filepath.Join(filepath.Dir(wd), "out", "report.xml"),
+		"-":                 "-",
+		"":                  "",
+	}
+	for path, want := range cases {
+		if got := callerPath(path); got != want {
+			t.Errorf("%q: expected %q, got %q", path, want, got)
+		}
+	}
+}
+
+func TestMisplacedFlagFile(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--json", "report.json"}, "report.json was read as a resource to build; use --json=report.json to write it"},
+		{[]string{"--junit", "chat", "results.xml"}, "results.xml was read as a resource to build; use --junit=results.xml to write it"},
+		{[]string{"--save-snapshot", "sizes.json"}, "sizes.json was read as a resource to build; use --save-snapshot=sizes.json to write it"},
+		{[]string{"--json=report.json", "chat"}, ""},
+		{[]string{"--json", "chat"}, ""},
+		{[]string{"--junit", "report.json"}, ""},
+	}
+	for _, tc := range cases {
+		cmd := NewBuildCommand()
+		if err := cmd.ParseFlags(tc.args); err != nil {
+			t.Fatal(err)
+		}
+		err := misplacedFlagFile(cmd, cmd.Flags().Args())
+		if tc.want == "" && err != nil {
+			t.Errorf("%v: expected no error, got %v", tc.args, err)
+		}
+		if tc.want != "" && (err == nil || err.Error() != tc.want) {
+			t.Errorf("%v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newcore-network/opencore-cli/internal/builder"
+	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the resolved project configuration",
+		Long: `The CLI resolves opencore.config.ts before using it: it is merged over the
+presets it extends and with the resource configs colocated in resource
+directories, outDir and destination are placed in the [category] folder,
+targets default per runtime or come from the adapter, the active environment
+and OPENCORE_* variables override values, and legacy dev fields are merged
+into their new names.
+
+Examples:
+  opencore config print
+  opencore config print --format yaml --env production
+  opencore config explain build.server.target
+  opencore config explain dev
+  opencore config schema > opencore.config.schema.json
+  opencore config migrate --dry-run`,
+	}
+
+	cmd.AddCommand(newConfigPrintCommand())
+	cmd.AddCommand(newConfigExplainCommand())
+	cmd.AddCommand(newConfigSchemaCommand())
+	cmd.AddCommand(newConfigMigrateCommand())
+
+	return cmd
+}
+
+func newConfigPrintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the resolved config, where its values came from and the build options of every task",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigPrint,
+	}
+
+	cmd.Flags().String("format", "json", "Output format (json|yaml)")
+	addEnvironmentFlag(cmd)
+
+	return cmd
+}
+
+func newConfigExplainCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <path>",
+		Short: "Show where resolved config values came from",
+		Long: `Show every resolved value at or below a config path, such as
+build.server.target or dev, and the steps that set it: default, preset,
+config file, resource file, adapter hint, legacy field, environment override,
+env var or flag. The last step is the one in effect.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runConfigExplain,
+	}
+
+	cmd.Flags().String("format", "text", "Output format (text|json|yaml)")
+	addEnvironmentFlag(cmd)
+
+	return cmd
+}
+
+func newConfigSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of opencore.config.ts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema, err := config.Schema()
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(schema)
+			return err
+		},
+	}
+}
+
+func newConfigMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite deprecated fields of opencore.config.ts",
+		Long: `Rewrite deprecated fields of opencore.config.ts to their current names:
+dev.port becomes dev.bridge.port, dev.txAdminUrl/User/Password move into
+dev.txAdmin and build.target moves to build.server.target and
+build.client.target. Only those properties are rewritten; comments and
+formatting are kept. The changes are printed as a diff.`,
+		Args: cobra.NoArgs,
+		RunE: runConfigMigrate,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Print the diff without writing opencore.config.ts")
+
+	return cmd
+}
+
+// loadResolvedConfig loads the config of the current project with the
+// environment given with --env applied, as the build would see it.
+func loadResolvedConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, root, err := config.LoadWithProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		return nil, fmt.Errorf("failed to switch to project root: %w", err)
+	}
+	if err := applyEnvironmentFlag(cmd, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// resolvedTask is a build task as printed by `opencore config print`.
+type resolvedTask struct {
+	ResourceName   string               `json:"resourceName"`
+	Type           builder.ResourceType `json:"type"`
+	Path           string               `json:"path"`
+	OutDir         string               `json:"outDir"`
+	CustomCompiler string               `json:"customCompiler,omitempty"`
+	Options        builder.BuildOptions `json:"options"`
+}
+
+func runConfigPrint(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("unknown format %q (expected json or yaml)", format)
+	}
+
+	cfg, err := loadResolvedConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	b := builder.New(cfg)
+	defer b.Close()
+	tasks := []resolvedTask{}
+	for _, task := range b.CollectTasks() {
+		tasks = append(tasks, resolvedTask{
+			ResourceName:   task.ResourceName,
+			Type:           task.Type,
+			Path:           task.Path,
+			OutDir:         task.OutDir,
+			CustomCompiler: task.CustomCompiler,
+			Options:        task.Options,
+		})
+	}
+
+	// The origin in effect for each value; config explain shows the rest.
+	explanations, err := cfg.Explain("")
+	if err != nil {
+		return err
+	}
+	origins := make(map[string]config.Origin, len(explanations))
+	for _, explanation := range explanations {
+		origins[explanation.Path] = explanation.Origins[len(explanation.Origins)-1]
+	}
+
+	return writeFormatted(cmd.OutOrStdout(), format, struct {
+		Config  *config.Config           `json:"config"`
+		Presets []config.Preset          `json:"presets,omitempty"`
+		Origins map[string]config.Origin `json:"origins"`
+		Tasks   []resolvedTask           `json:"tasks"`
+	}{cfg.Redacted(), cfg.Presets(), origins, tasks})
+}
+
+func runConfigExplain(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "text" && format != "json" && format != "yaml" {
+		return fmt.Errorf("unknown format %q (expected text, json or yaml)", format)
+	}
+
+	cfg, err := loadResolvedConfig(cmd)
+	if err != nil {
+		return err
+	}
+	explanations, err := cfg.Explain(args[0])
+	if err != nil {
+		return err
+	}
+
+	if format != "text" {
+		return writeFormatted(cmd.OutOrStdout(), format, explanations)
+	}
+	printExplanations(cmd.OutOrStdout(), explanations)
+	return nil
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cfg, root, err := config.LoadWithProjectRoot()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	configPath := filepath.Join(root, "opencore.config.ts")
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+	source, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	migration, err := cfg.Migrate(source)
+	if err != nil {
+		return fmt.Errorf("failed to migrate opencore.config.ts: %w", err)
+	}
+	for _, skipped := range migration.Skipped {
+		fmt.Println(ui.Warning(fmt.Sprintf("%s; move it by hand", skipped)))
+	}
+	if !migration.Changed() {
+		if len(migration.Skipped) == 0 {
+			fmt.Println(ui.Success("opencore.config.ts has no deprecated fields"))
+		}
+		return nil
+	}
+
+	printDiff(unifiedDiff("opencore.config.ts", string(source), string(migration.Source)))
+	fmt.Println()
+	for _, change := range migration.Changes {
+		fmt.Println(ui.Muted("  " + change))
+	}
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println(ui.Info("Dry run: opencore.config.ts was not changed"))
+		return nil
+	}
+	if err := os.WriteFile(configPath, migration.Source, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write opencore.config.ts: %w", err)
+	}
+	fmt.Println(ui.Success(fmt.Sprintf("Migrated %d deprecated field(s) in opencore.config.ts", len(migration.Changes))))
+	return nil
+}
+
+// printDiff prints a unified diff with removed lines in red and added
+// lines in green.
+func printDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "@@"):
+			fmt.Println(ui.MutedStyle.Render(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(ui.ErrorStyle.Render(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(ui.SuccessStyle.Render(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func writeFormatted(w io.Writer, format string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format == "yaml" {
+		data, err = jsonToYAML(data)
+		if err != nil {
+			return err
+		}
+	} else {
+		data = append(data, '\n')
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func printExplanations(w io.Writer, explanations []config.Explanation) {
+	for i, explanation := range explanations {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s = %s\n", explanation.Path, formatConfigValue(explanation.Value))
+		last := len(explanation.Origins) - 1
+		for j, origin := range explanation.Origins {
+			line := fmt.Sprintf("%-20s %s", origin.Kind, formatConfigValue(origin.Value))
+			if origin.Source != "" {
+				line += "  (" + origin.Source + ")"
+			}
+			if j == last {
+				fmt.Fprintf(w, "  * %s\n", line)
+			} else {
+				fmt.Fprintf(w, "    %s\n", ui.Muted(line))
+			}
+		}
+	}
+}
+
+func formatConfigValue(value any) string {
+	if value == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newcore-network/opencore-cli/internal/builder"
+	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+func NewDeployCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Inspect and roll back deployed resources",
+		Long: `Every deployment keeps the versions it replaces in the destination
+(deploy.keep per resource, default 3). Use these commands to list them and to
+restore one without rebuilding.
+
+Examples:
+  opencore deploy list
+  opencore deploy rollback chat
+  opencore deploy rollback --to 20261016-153045.123`,
+	}
+
+	cmd.AddCommand(newDeployListCommand())
+	cmd.AddCommand(newDeployRollbackCommand())
+
+	return cmd
+}
+
+func newDeployListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [resource]",
+		Short: "List the retained versions of deployed resources",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runDeployList,
+	}
+}
+
+func newDeployRollbackCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [resource]",
+		Short: "Restore a previously deployed version",
+		Long: `Restore the previous version of a resource, or of every resource when none is
+given. With --to, restore the deployment with that id (a unique prefix is enough).`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runDeployRollback,
+	}
+
+	cmd.Flags().String("to", "", "Deployment id to restore (see 'opencore deploy list')")
+
+	return cmd
+}
+
+func loadDeployer() (*builder.Deployer, error) {
+	cfg, root, err := config.LoadWithProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(root); err != nil {
+		return nil, fmt.Errorf("failed to switch to project root: %w", err)
+	}
+	if cfg.Destination == "" {
+		return nil, fmt.Errorf("no destination configured; deployments are only kept in the destination")
+	}
+	return builder.NewDeployer(cfg), nil
+}
+
+func runDeployList(cmd *cobra.Command, args []string) error {
+	deployer, err := loadDeployer()
+	if err != nil {
+		return err
+	}
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	histories, err := deployer.History(name)
+	if err != nil {
+		return err
+	}
+	if len(histories) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), ui.Muted("No deployments recorded yet"))
+		return nil
+	}
+	printDeployHistory(cmd.OutOrStdout(), histories)
+	return nil
+}
+
+func printDeployHistory(w io.Writer, histories []builder.ResourceHistory) {
+	for i, history := range histories {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, history.Resource)
+		if history.Current != nil {
+			fmt.Fprintf(w, "  * %s  (current)\n", formatDeployment(*history.Current))
+		}
+		for _, version := range history.Versions {
+			if history.Current != nil && version.ID == history.Current.ID {
+				continue
+			}
+			fmt.Fprintf(w, "    %s\n", formatDeployment(version))
+		}
+	}
+}
+
+func formatDeployment(d builder.Deployment) string {
+	line := fmt.Sprintf("%s  %s", d.ID, d.DeployedAt.Local().Format("2006-01-02 15:04:05"))
+	if d.Environment != "" {
+		line += "  " + d.Environment
+	}
+	if d.Commit != "" {
+		line += "  " + d.Commit
+		if d.Dirty {
+			line += " (dirty)"
+		}
+	}
+	return line
+}
+
+func runDeployRollback(cmd *cobra.Command, args []string) error {
+	deployer, err := loadDeployer()
+	if err != nil {
+		return err
+	}
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	to, _ := cmd.Flags().GetString("to")
+
+	restored, err := deployer.Rollback(name, to)
+	if err != nil {
+		return err
+	}
+	for _, deployment := range restored {
+		fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("%s restored to %s", deployment.Resource, formatDeployment(deployment))))
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newcore-network/opencore-cli/internal/builder"
+)
+
+func TestPrintDeployHistory(t *testing.T) {
+	at := time.Date(2026, 10, 16, 15, 30, 45, 0, time.UTC)
+	current := builder.Deployment{ID: "20261016-153045.000", DeployedAt: at, Environment: "production", Commit: "abc1234"}
+	previous := builder.Deployment{ID: "20261015-090000.000", DeployedAt: at.Add(-30 * time.Hour), Commit: "def5678", Dirty: true}
+
+	var buf bytes.Buffer
+	printDeployHistory(&buf, []builder.ResourceHistory{{
+		Resource: "chat",
+		Current:  &current,
+		Versions: []builder.Deployment{current, previous},
+	}})
+
+	out := buf.String()
+	if strings.Count(out, "20261016-153045.000") != 1 {
+		t.Fatalf("expected the current version to be listed once:\n%s", out)
+	}
+	for _, want := range []string{"chat\n", "* 20261016-153045.000", "production  abc1234  (current)", "    20261015-090000.000", "def5678 (dirty)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
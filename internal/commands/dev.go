@@ -15,11 +15,16 @@ func NewDevCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dev",
 		Short: "Start development mode with hot-reload",
-		Long:  "Watch for file changes and automatically rebuild resources.",
-		RunE:  runDev,
+		Long: `Watch for file changes and automatically rebuild resources.
+
+In a workspace, every project (or those selected with --project) runs its own
+dev session, with output prefixed by the project name.`,
+		RunE: runDev,
 	}
 
-	cmd.Flags().StringP("environment", "e", "", "Environment to use during development (e.g. development, production)")
+	addEnvironmentFlag(cmd)
+	addProjectFlag(cmd)
+	addFailurePolicyFlags(cmd)
 
 	return cmd
 }
@@ -28,18 +33,34 @@ func runDev(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.TitleStyle.Render("Development Mode"))
 	fmt.Println()
 
-	// Load config
-	cfg, root, err := config.LoadWithProjectRoot()
+	workspace, projects, err := workspaceProjects(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
-	if err := os.Chdir(root); err != nil {
-		return fmt.Errorf("failed to switch to project root: %w", err)
+	if len(projects) > 1 {
+		return runWorkspaceDev(cmd, projects)
 	}
 
-	if env, _ := cmd.Flags().GetString("environment"); env != "" {
-		cfg.Build.Environment = env
+	// Load config
+	var cfg *config.Config
+	if workspace != nil {
+		if cfg, err = loadWorkspaceProject(cmd, workspace, projects[0]); err != nil {
+			return err
+		}
+	} else {
+		var root string
+		cfg, root, err = config.LoadWithProjectRoot()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := os.Chdir(root); err != nil {
+			return fmt.Errorf("failed to switch to project root: %w", err)
+		}
+		if err := applyEnvironmentFlag(cmd, cfg); err != nil {
+			return err
+		}
 	}
+	applyFailurePolicyFlags(cmd, cfg)
 
 	// Create watcher
 	w, err := watcher.New(cfg)
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +12,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/newcore-network/opencore-cli/internal/builder"
 	"github.com/newcore-network/opencore-cli/internal/config"
 	"github.com/newcore-network/opencore-cli/internal/pkgmgr"
 	"github.com/newcore-network/opencore-cli/internal/ui"
@@ -19,10 +22,14 @@ func NewDoctorCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Check project health and dependencies",
-		Long:  "Validate that all required dependencies and configuration are correct.",
-		RunE:  runDoctor,
+		Long: `Validate that all required dependencies and configuration are correct.
+
+In a workspace, every project (or those selected with --project) is checked.`,
+		RunE: runDoctor,
 	}
 
+	addProjectFlag(cmd)
+
 	return cmd
 }
 
@@ -63,6 +70,58 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		checks = append(checks, CheckResult{Name: "Package Manager", Passed: true, Message: fmt.Sprintf("%s (%s)", resolved.Choice, resolved.Version), Required: true})
 	}
 
+	workspace, projects, err := workspaceProjects(cmd)
+	if err != nil {
+		checks = append(checks, CheckResult{Name: "Workspace", Passed: false, Message: err.Error(), Required: true})
+	} else if workspace == nil {
+		checks = append(checks, projectChecks(resolved)...)
+	} else {
+		checks = append(checks, CheckResult{Name: "Workspace", Passed: true, Message: fmt.Sprintf("%d project(s) in %s", len(projects), workspace.Root()), Required: true})
+	}
+
+	// Render results table
+	renderCheckResults(checks)
+
+	// Each workspace project gets its own table.
+	for _, project := range projects {
+		fmt.Println()
+		fmt.Println(ui.TitleStyle.Render(fmt.Sprintf("Project %s (%s)", project.Name, project.Path)))
+		fmt.Println()
+		var results []CheckResult
+		if err := os.Chdir(project.Root); err != nil {
+			results = []CheckResult{{Name: "OpenCore Project", Passed: false, Message: err.Error(), Required: true}}
+		} else {
+			results = projectChecks(resolved)
+		}
+		renderCheckResults(results)
+		checks = append(checks, results...)
+	}
+
+	// Determine overall status
+	allPassed := true
+	for _, check := range checks {
+		if check.Required && !check.Passed {
+			allPassed = false
+			break
+		}
+	}
+
+	fmt.Println()
+	if allPassed {
+		fmt.Println(ui.SuccessBoxStyle.Render("✓ All checks passed! Your project is healthy."))
+	} else {
+		fmt.Println(ui.ErrorBoxStyle.Render("✗ Some checks failed. Please fix the issues above."))
+		return fmt.Errorf("health check failed")
+	}
+
+	return nil
+}
+
+// projectChecks checks the project in the current directory: its config,
+// adapter, manifests and installed dependencies.
+func projectChecks(resolved pkgmgr.Resolved) []CheckResult {
+	checks := []CheckResult{}
+
 	// Check if in OpenCore project
 	projectCheck := checkOpenCoreProject()
 	projectCheck.Required = true
@@ -77,9 +136,16 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		checks = append(checks, configCheck)
 		if configCheck.Passed {
 			checks = append(checks, CheckResult{Name: "Runtime", Passed: true, Message: cfg.RuntimeKind(), Required: true})
+			checks = append(checks, checkLegacyConfig("opencore.config.ts"))
+			if len(cfg.Modules) > 0 {
+				checks = append(checks, checkModules(cfg, "tsconfig.json"))
+			}
 			adapterCheck := checkAdapter(cfg)
 			adapterCheck.Required = true
 			checks = append(checks, adapterCheck)
+			manifestCheck := checkManifests(cfg)
+			manifestCheck.Required = true
+			checks = append(checks, manifestCheck)
 		}
 	}
 
@@ -90,27 +156,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		checks = append(checks, depsCheck)
 	}
 
-	// Render results table
-	renderCheckResults(checks)
-
-	// Determine overall status
-	allPassed := true
-	for _, check := range checks {
-		if check.Required && !check.Passed {
-			allPassed = false
-			break
-		}
-	}
-
-	fmt.Println()
-	if allPassed {
-		fmt.Println(ui.SuccessBoxStyle.Render("✓ All checks passed! Your project is healthy."))
-	} else {
-		fmt.Println(ui.ErrorBoxStyle.Render("✗ Some checks failed. Please fix the issues above."))
-		return fmt.Errorf("health check failed")
-	}
-
-	return nil
+	return checks
 }
 
 func checkCommand(command string, args string, name string) CheckResult {
@@ -199,6 +245,68 @@ func checkConfig() (*config.Config, CheckResult) {
 	}
 }
 
+// checkLegacyConfig warns about deprecated fields that opencore config
+// migrate rewrites.
+func checkLegacyConfig(path string) CheckResult {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return CheckResult{Name: "Deprecated Fields", Passed: false, Message: err.Error()}
+	}
+	fields, err := config.LegacyFields(source)
+	if err != nil {
+		return CheckResult{Name: "Deprecated Fields", Passed: true, Message: fmt.Sprintf("Not checked: %v", err)}
+	}
+	if len(fields) == 0 {
+		return CheckResult{Name: "Deprecated Fields", Passed: true, Message: "No deprecated config fields"}
+	}
+	return CheckResult{
+		Name:    "Deprecated Fields",
+		Passed:  false,
+		Message: fmt.Sprintf("%s (run opencore config migrate)", strings.Join(fields, ", ")),
+	}
+}
+
+// checkModules checks that the shared module directories exist, that
+// tsconfig.json maps their @modules/ imports, which editors and tsc need to
+// type-check them, and that they type-check as opencore build requires.
+func checkModules(cfg *config.Config, tsconfigPath string) CheckResult {
+	modules, err := cfg.SharedModules()
+	if err != nil {
+		return CheckResult{Name: "Modules", Passed: false, Required: true, Message: err.Error()}
+	}
+	for _, module := range modules {
+		if info, err := os.Stat(module.Path); err != nil || !info.IsDir() {
+			return CheckResult{Name: "Modules", Passed: false, Required: true, Message: fmt.Sprintf("%s not found", module.Path)}
+		}
+	}
+
+	tsconfig, _ := os.ReadFile(tsconfigPath)
+	var unmapped []string
+	for _, module := range modules {
+		alias := config.ModuleImportPrefix + module.Name
+		if !strings.Contains(string(tsconfig), `"`+config.ModuleImportPrefix+`*"`) && !strings.Contains(string(tsconfig), `"`+alias+`"`) {
+			unmapped = append(unmapped, alias)
+		}
+	}
+	if len(unmapped) > 0 {
+		return CheckResult{
+			Name:    "Modules",
+			Passed:  false,
+			Message: fmt.Sprintf("%s not in compilerOptions.paths of %s", strings.Join(unmapped, ", "), tsconfigPath),
+		}
+	}
+
+	err = builder.TypeCheckModules(context.Background(), modules)
+	var typeErrors builder.ModuleTypeErrors
+	switch {
+	case errors.As(err, &typeErrors):
+		return CheckResult{Name: "Modules", Passed: false, Required: true, Message: fmt.Sprintf("%s, first: %s", typeErrors.Error(), typeErrors[0])}
+	case err != nil:
+		return CheckResult{Name: "Modules", Passed: false, Required: true, Message: err.Error()}
+	}
+	return CheckResult{Name: "Modules", Passed: true, Message: fmt.Sprintf("%d shared module(s), type-checked", len(modules))}
+}
+
 func checkAdapter(cfg *config.Config) CheckResult {
 	if cfg == nil || cfg.Adapter == nil || (cfg.Adapter.Server == nil && cfg.Adapter.Client == nil) {
 		return CheckResult{
@@ -232,6 +340,43 @@ func checkAdapter(cfg *config.Config) CheckResult {
 	}
 }
 
+func checkManifests(cfg *config.Config) CheckResult {
+	issues, checked := builder.New(cfg).LintManifests()
+	if checked == 0 && len(issues) == 0 {
+		return CheckResult{
+			Name:    "Manifests",
+			Passed:  true,
+			Message: "No built manifests to check (run opencore build)",
+		}
+	}
+
+	var problems, warnings []string
+	for _, issue := range issues {
+		if issue.Warning {
+			warnings = append(warnings, issue.String())
+		} else {
+			problems = append(problems, issue.String())
+		}
+	}
+	if len(problems) > 0 {
+		return CheckResult{
+			Name:    "Manifests",
+			Passed:  false,
+			Message: strings.Join(problems, "; "),
+		}
+	}
+
+	message := fmt.Sprintf("%d manifest(s) match the build output", checked)
+	if len(warnings) > 0 {
+		message += "; " + strings.Join(warnings, "; ")
+	}
+	return CheckResult{
+		Name:    "Manifests",
+		Passed:  true,
+		Message: message,
+	}
+}
+
 func formatAdapterBinding(side string, binding *config.AdapterBinding) string {
 	if binding == nil {
 		return fmt.Sprintf("%s: not configured", side)
@@ -263,6 +408,25 @@ func adapterRuntimeLabel(binding *config.AdapterBinding) string {
 }
 
 func checkDependencies(pm pkgmgr.Resolved) CheckResult {
+	// Node also resolves packages from parent directories, where workspaces
+	// usually install them.
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "node_modules", "@open-core", "framework")); err == nil {
+				return CheckResult{
+					Name:    "Dependencies",
+					Passed:  true,
+					Message: "All dependencies installed",
+				}
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
 	// Check if node_modules exists
 	if _, err := os.Stat("node_modules"); os.IsNotExist(err) {
 		install := "pnpm install"
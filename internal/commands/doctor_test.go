@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -51,3 +54,100 @@ func TestCheckAdapterInvalid(t *testing.T) {
 		t.Fatalf("unexpected message: %s", result.Message)
 	}
 }
+
+func TestCheckManifestsWithoutBuildOutput(t *testing.T) {
+	root := t.TempDir()
+	result := checkManifests(&config.Config{
+		OutDir: filepath.Join(root, "build"),
+		Core:   config.CoreConfig{Path: filepath.Join(root, "core"), ResourceName: "core"},
+	})
+
+	if !result.Passed {
+		t.Fatalf("expected a project without build output to pass, got %s", result.Message)
+	}
+	if !strings.Contains(result.Message, "No built manifests") {
+		t.Fatalf("unexpected message: %s", result.Message)
+	}
+}
+
+func TestCheckManifestsMissingScript(t *testing.T) {
+	root := t.TempDir()
+	manifest := filepath.Join(root, "build", "core", "fxmanifest.lua")
+	if err := os.MkdirAll(filepath.Dir(manifest), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifest, []byte("server_script 'server.js'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := checkManifests(&config.Config{
+		OutDir: filepath.Join(root, "build"),
+		Core:   config.CoreConfig{Path: filepath.Join(root, "core"), ResourceName: "core"},
+	})
+	if result.Passed || !strings.Contains(result.Message, "[core] server_script 'server.js'") {
+		t.Fatalf("expected missing script to fail, got %+v", result)
+	}
+}
+
+func TestCheckLegacyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "opencore.config.ts")
+	source := "export default defineConfig({\n  build: { target: 'es2021' },\n  dev: { port: 4000 },\n})\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := checkLegacyConfig(path)
+	if result.Passed || result.Required {
+		t.Fatalf("expected an optional warning, got %+v", result)
+	}
+	if !strings.Contains(result.Message, "dev.port, build.target") || !strings.Contains(result.Message, "opencore config migrate") {
+		t.Fatalf("unexpected message: %s", result.Message)
+	}
+
+	if err := os.WriteFile(path, []byte("export default defineConfig({ dev: { bridge: { port: 4000 } } })\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if result := checkLegacyConfig(path); !result.Passed {
+		t.Fatalf("expected a current config to pass, got %+v", result)
+	}
+}
+
+func TestCheckModules(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "modules", "inventory-types"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+	cfg := &config.Config{Modules: []string{"./modules/inventory-types"}}
+
+	result := checkModules(cfg, "tsconfig.json")
+	if result.Passed || result.Required || !strings.Contains(result.Message, "@modules/inventory-types not in compilerOptions.paths") {
+		t.Fatalf("expected a warning about the missing path alias, got %+v", result)
+	}
+
+	tsconfig := `{ "compilerOptions": { "paths": { "@modules/*": ["./modules/*"] } } }`
+	if err := os.WriteFile("tsconfig.json", []byte(tsconfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if result := checkModules(cfg, "tsconfig.json"); result.Passed || !strings.Contains(result.Message, "typescript is not installed") {
+		t.Fatalf("expected modules to need typescript, got %+v", result)
+	}
+
+	if _, err := exec.LookPath("node"); err == nil {
+		tsc := filepath.Join(root, "node_modules", "typescript", "bin", "tsc")
+		if err := os.MkdirAll(filepath.Dir(tsc), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(tsc, []byte("process.exit(0)\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if result := checkModules(cfg, "tsconfig.json"); !result.Passed {
+			t.Fatalf("expected mapped modules that type-check to pass, got %+v", result)
+		}
+	}
+
+	cfg.Modules = append(cfg.Modules, "./modules/missing")
+	if result := checkModules(cfg, "tsconfig.json"); result.Passed || !result.Required {
+		t.Fatalf("expected a missing module to fail, got %+v", result)
+	}
+}
@@ -0,0 +1,292 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newcore-network/opencore-cli/internal/builder"
+	"github.com/newcore-network/opencore-cli/internal/config"
+	"github.com/newcore-network/opencore-cli/internal/ui"
+)
+
+// addProjectFlag registers --project, which selects projects of a workspace.
+func addProjectFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("project", "p", "", "Workspace projects to use, comma-separated (default: every project, or the current one)")
+}
+
+// projectNames returns the projects listed in --project.
+func projectNames(cmd *cobra.Command) []string {
+	value, _ := cmd.Flags().GetString("project")
+	return splitList(value)
+}
+
+// workspaceProjects returns the workspace projects a command operates on.
+// Without --project that is every project, unless the command runs inside
+// one of them: then it operates on that project alone, as outside a
+// workspace, and the workspace is nil.
+func workspaceProjects(cmd *cobra.Command) (*config.Workspace, []config.WorkspaceProject, error) {
+	names := projectNames(cmd)
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	root, err := config.FindWorkspaceRoot(wd)
+	if err != nil {
+		if len(names) > 0 {
+			return nil, nil, fmt.Errorf("--project needs an %s in the current directory or a parent directory", config.WorkspaceFile)
+		}
+		return nil, nil, nil
+	}
+	if len(names) == 0 {
+		if projectRoot, err := config.FindProjectRoot(wd); err == nil && strings.HasPrefix(projectRoot, root+string(os.PathSeparator)) {
+			return nil, nil, nil
+		}
+	}
+
+	workspace, err := config.LoadWorkspace(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load workspace: %w", err)
+	}
+	projects, err := workspace.SelectProjects(names)
+	if err != nil {
+		return nil, nil, err
+	}
+	return workspace, projects, nil
+}
+
+// loadWorkspaceProject switches to a workspace project and loads its config
+// with the --env overrides applied.
+func loadWorkspaceProject(cmd *cobra.Command, workspace *config.Workspace, project config.WorkspaceProject) (*config.Config, error) {
+	if err := os.Chdir(project.Root); err != nil {
+		return nil, fmt.Errorf("failed to switch to project root: %w", err)
+	}
+	cfg, err := config.LoadProject(project.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if workspace.MaxWorkers > 0 {
+		cfg.Build.MaxWorkers = workspace.MaxWorkers
+	}
+	if err := applyEnvironmentFlag(cmd, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// projectPath places a report of a workspace build. Relative paths are
+// written into each project, like a single project build does; absolute
+// paths get the project name before the extension.
+func projectPath(path, project string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	return environmentPath(path, project)
+}
+
+// projectBuild is the outcome of one project of a workspace build.
+type projectBuild struct {
+	project  config.WorkspaceProject
+	config   *config.Config
+	duration time.Duration
+	err      error
+}
+
+// buildWorkspace builds the workspace projects in turn. They share the build
+// daemon, whose workers switch between projects, and the workspace's
+// dependency cache.
+func buildWorkspace(cmd *cobra.Command, workspace *config.Workspace, projects []config.WorkspaceProject, mode builder.OutputMode, report builder.ReportOptions, comparePath string, selection builder.Selection, tracer *builder.Tracer) error {
+	if len(environmentNames(cmd)) > 1 {
+		return fmt.Errorf("only one environment can be built for several projects, got %s", strings.Join(environmentNames(cmd), ", "))
+	}
+	if len(projects) > 1 {
+		if len(selection.Resources) > 0 {
+			return fmt.Errorf("resource arguments need a single --project")
+		}
+		if report.JSONPath == "-" || report.JUnitPath == "-" {
+			return fmt.Errorf("--json and --junit need a file path when building several projects")
+		}
+	}
+	plain := mode == builder.OutputModePlain || (mode == builder.OutputModeAuto && ui.IsNonInteractiveSession())
+	ctx := cmd.Context()
+
+	var base *builder.Builder
+	defer func() {
+		if base != nil {
+			base.Close()
+		}
+	}()
+
+	builds := make([]projectBuild, 0, len(projects))
+	var errs []error
+	for _, project := range projects {
+		header := fmt.Sprintf("Project %s (%s)", project.Name, project.Path)
+		if plain {
+			fmt.Println("\n" + header)
+		} else {
+			fmt.Println("\n" + ui.TitleStyle.Render(header))
+		}
+
+		start := time.Now()
+		cfg, err := loadWorkspaceProject(cmd, workspace, project)
+		if err == nil {
+			applyBuildFlags(cmd, cfg)
+			var b *builder.Builder
+			if base == nil {
+				base = builder.New(cfg)
+				base.SetTracer(tracer)
+				b = base
+			} else {
+				b = base.ForProject(cfg)
+			}
+			b.SetReport(builder.ReportOptions{
+				JSONPath:     projectPath(report.JSONPath, project.Name),
+				JUnitPath:    projectPath(report.JUnitPath, project.Name),
+				SnapshotPath: projectPath(report.SnapshotPath, project.Name),
+			})
+			if report.JSONPath == "-" || report.JUnitPath == "-" {
+				b.SetOutput(os.Stderr)
+			}
+			b.SetSelection(selection)
+			if comparePath != "" {
+				var baseline *builder.SizeSnapshot
+				if baseline, err = builder.LoadSizeSnapshot(projectPath(comparePath, project.Name)); err == nil {
+					b.SetBaseline(baseline)
+				}
+			}
+			if err == nil {
+				err = b.BuildWithOutputContext(ctx, mode)
+			}
+		}
+		builds = append(builds, projectBuild{project: project, config: cfg, duration: time.Since(start), err: err})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", project.Name, err))
+			if (cfg != nil && cfg.Build.FailFast) || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	printProjectSummary(builds, len(projects), plain)
+	return errors.Join(errs...)
+}
+
+func printProjectSummary(builds []projectBuild, total int, plain bool) {
+	fmt.Println()
+	if plain {
+		fmt.Println("Projects")
+	} else {
+		fmt.Println(ui.TitleStyle.Render("Projects"))
+	}
+	for _, build := range builds {
+		status := "ok"
+		if build.err != nil {
+			status = "failed"
+		}
+		target := "-"
+		if build.config != nil {
+			target = filepath.Join(build.project.Path, build.config.OutDir)
+			if build.config.Destination != "" {
+				target += " → " + build.config.Destination
+			}
+		}
+		line := fmt.Sprintf("  %-14s %-7s %-8s %s", build.project.Name, status, build.duration.Round(time.Millisecond), target)
+		if build.err != nil && !plain {
+			line = ui.ErrorStyle.Render(line)
+		}
+		fmt.Println(line)
+	}
+	if skipped := total - len(builds); skipped > 0 {
+		message := fmt.Sprintf("  %d project(s) skipped after a failure", skipped)
+		if plain {
+			fmt.Println(message)
+		} else {
+			fmt.Println(ui.Muted(message))
+		}
+	}
+}
+
+// runWorkspaceDev runs `opencore dev` for each project in its own process,
+// since a dev session watches, serves and restarts one project. Their output
+// is prefixed with the project name. It returns when every session ended.
+func runWorkspaceDev(cmd *cobra.Command, projects []config.WorkspaceProject) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the opencore executable: %w", err)
+	}
+
+	width := 0
+	for _, project := range projects {
+		width = max(width, len(project.Name))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(projects))
+	for i, project := range projects {
+		fmt.Println(ui.Info(fmt.Sprintf("Starting %s (%s)", project.Name, project.Path)))
+		prefix := ui.MutedStyle.Render(fmt.Sprintf("%-*s │ ", width, project.Name))
+
+		child := exec.CommandContext(cmd.Context(), executable, workspaceDevArgs(cmd, project)...)
+		child.Dir = project.Root
+		child.Cancel = func() error {
+			if err := child.Process.Signal(os.Interrupt); err != nil {
+				return child.Process.Kill()
+			}
+			return nil
+		}
+		child.WaitDelay = 15 * time.Second
+		stdout, err := child.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		child.Stderr = child.Stdout
+		if err := child.Start(); err != nil {
+			return fmt.Errorf("%s: %w", project.Name, err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prefixLines(os.Stdout, stdout, prefix)
+			if err := child.Wait(); err != nil && cmd.Context().Err() == nil {
+				errs[i] = fmt.Errorf("%s: %w", project.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// workspaceDevArgs returns the arguments of the dev session of a project.
+// --project keeps the session on that project, also when the project is the
+// workspace root itself.
+func workspaceDevArgs(cmd *cobra.Command, project config.WorkspaceProject) []string {
+	args := []string{"dev", "--project", project.Name}
+	if envs := environmentNames(cmd); len(envs) > 0 {
+		args = append(args, "--env", strings.Join(envs, ","))
+	}
+	for _, flag := range []string{"keep-going", "fail-fast"} {
+		if set, _ := cmd.Flags().GetBool(flag); set {
+			args = append(args, "--"+flag)
+		}
+	}
+	return args
+}
+
+// prefixLines copies r to w line by line, starting each line with prefix.
+func prefixLines(w io.Writer, r io.Reader, prefix string) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fmt.Fprintln(w, prefix+scanner.Text())
+	}
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newcore-network/opencore-cli/internal/config"
+)
+
+func TestWorkspaceProjectsInsideProject(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "servers", "fivem")
+	if err := os.MkdirAll(filepath.Join(project, "core"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{filepath.Join(root, config.WorkspaceFile), filepath.Join(project, "opencore.config.ts")} {
+		if err := os.WriteFile(file, []byte("export default {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(filepath.Join(project, "core"))
+
+	workspace, projects, err := workspaceProjects(NewBuildCommand())
+	if err != nil || workspace != nil || projects != nil {
+		t.Errorf("expected a command inside a project to use that project alone, got %v %v %v", workspace, projects, err)
+	}
+
+	t.Chdir(t.TempDir())
+	cmd := NewBuildCommand()
+	if err := cmd.Flags().Set("project", "fivem"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := workspaceProjects(cmd); err == nil || !strings.Contains(err.Error(), config.WorkspaceFile) {
+		t.Errorf("expected --project to need a workspace, got %v", err)
+	}
+}
+
+func TestWorkspaceDevArgs(t *testing.T) {
+	cmd := NewDevCommand()
+	if err := cmd.Flags().Set("env", "staging"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Flags().Set("fail-fast", "true"); err != nil {
+		t.Fatal(err)
+	}
+	args := workspaceDevArgs(cmd, config.WorkspaceProject{Name: "servers", Path: "."})
+	if got, want := strings.Join(args, " "), "dev --project servers --env staging --fail-fast"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestProjectPath(t *testing.T) {
+	abs := filepath.Join(t.TempDir(), "report.json")
+	if got, want := projectPath(abs, "redm"), strings.TrimSuffix(abs, ".json")+".redm.json"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := projectPath("report.json", "redm"); got != "report.json" {
+		t.Errorf("expected relative paths to stay per project, got %q", got)
+	}
+}
+
+func TestPrefixLines(t *testing.T) {
+	var out bytes.Buffer
+	prefixLines(&out, strings.NewReader("Watching...\nBuilt core\n"), "redm │ ")
+	if got, want := out.String(), "redm │ Watching...\nredm │ Built core\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
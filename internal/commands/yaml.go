@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlNode is a decoded JSON value that keeps the order of object keys, so
+// YAML output lists fields in the same order as the JSON encoding.
+type yamlNode struct {
+	keys   []string    // object keys, nil for arrays and scalars
+	values []*yamlNode // object values or array items
+	array  bool
+	object bool
+	scalar any
+}
+
+// plainYAMLString matches strings that need no quotes in YAML.
+var plainYAMLString = regexp.MustCompile(`^[A-Za-z0-9_./$][A-Za-z0-9_./@$ +-]*$`)
+
+// jsonToYAML converts a JSON document to block-style YAML.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if root.inline() {
+		out.WriteString(root.inlineValue() + "\n")
+		return out.Bytes(), nil
+	}
+	for _, line := range root.lines() {
+		out.WriteString(line + "\n")
+	}
+	return out.Bytes(), nil
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return &yamlNode{scalar: token}, nil
+	}
+
+	node := &yamlNode{object: delim == '{', array: delim == '['}
+	for dec.More() {
+		if node.object {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid object key %v", key)
+			}
+			node.keys = append(node.keys, name)
+		}
+		value, err := decodeYAMLNode(dec)
+		if err != nil {
+			return nil, err
+		}
+		node.values = append(node.values, value)
+	}
+	// Consume the closing delimiter.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// inline reports whether the node fits on its key's line.
+func (n *yamlNode) inline() bool {
+	return (!n.object && !n.array) || len(n.values) == 0
+}
+
+func (n *yamlNode) inlineValue() string {
+	switch {
+	case n.object:
+		return "{}"
+	case n.array:
+		return "[]"
+	}
+	switch value := n.scalar.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(value)
+	case json.Number:
+		return value.String()
+	case string:
+		return yamlString(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// lines renders an object or array, indented relative to its parent.
+func (n *yamlNode) lines() []string {
+	var lines []string
+	for i, value := range n.values {
+		prefix := "- "
+		if n.object {
+			prefix = yamlString(n.keys[i]) + ":"
+			if value.inline() {
+				prefix += " "
+			}
+		}
+		if value.inline() {
+			lines = append(lines, prefix+value.inlineValue())
+			continue
+		}
+
+		children := value.lines()
+		if n.object {
+			lines = append(lines, prefix)
+			for _, child := range children {
+				lines = append(lines, "  "+child)
+			}
+			continue
+		}
+		// The first field of an object in a list shares the dash's line.
+		lines = append(lines, prefix+children[0])
+		for _, child := range children[1:] {
+			lines = append(lines, "  "+child)
+		}
+	}
+	return lines
+}
+
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "", "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	if !plainYAMLString.MatchString(s) || strings.HasSuffix(s, " ") {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package commands
+
+import "testing"
+
+func TestJSONToYAML(t *testing.T) {
+	data := []byte(`{
+		"name": "demo",
+		"outDir": "build/[demo]",
+		"minify": false,
+		"port": 3847,
+		"target": "",
+		"version": "1.0",
+		"external": ["pg", "@opencore/framework"],
+		"explicit": [{"path": "./chat", "compile": true}],
+		"server": {"platform": "node", "define": {}},
+		"modules": []
+	}`)
+	got, err := jsonToYAML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `name: demo
+outDir: "build/[demo]"
+minify: false
+port: 3847
+target: ""
+version: "1.0"
+external:
+  - pg
+  - "@opencore/framework"
+explicit:
+  - path: ./chat
+    compile: true
+server:
+  platform: node
+  define: {}
+modules: []
+`
+	if string(got) != want {
+		t.Errorf("unexpected YAML:\n%s\nwant:\n%s", got, want)
+	}
+}
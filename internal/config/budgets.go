@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BudgetsConfig caps the size of built outputs: server.js, client.js and the
+// ui/ folder. Per-resource budgets override the global ones field by field.
+type BudgetsConfig struct {
+	Server *SizeBudget `json:"server,omitempty"`
+	Client *SizeBudget `json:"client,omitempty"`
+	UI     *SizeBudget `json:"ui,omitempty"`
+}
+
+// SizeBudget is a maximum size in bytes. In JSON it is either a byte count,
+// a size string such as "250 KB", or an object { max, soft }. A soft budget
+// only warns when exceeded.
+type SizeBudget struct {
+	Max  int64
+	Soft bool
+}
+
+func (s *SizeBudget) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Max  json.RawMessage `json:"max"`
+		Soft bool            `json:"soft"`
+	}
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if len(raw.Max) == 0 {
+			return fmt.Errorf("size budget requires a max value")
+		}
+		data = raw.Max
+	}
+
+	max, err := parseSizeJSON(data)
+	if err != nil {
+		return err
+	}
+	s.Max = max
+	s.Soft = raw.Soft
+	return nil
+}
+
+func (s SizeBudget) MarshalJSON() ([]byte, error) {
+	if !s.Soft {
+		return json.Marshal(s.Max)
+	}
+	return json.Marshal(struct {
+		Max  int64 `json:"max"`
+		Soft bool  `json:"soft"`
+	}{s.Max, s.Soft})
+}
+
+func parseSizeJSON(data []byte) (int64, error) {
+	var bytes int64
+	if err := json.Unmarshal(data, &bytes); err == nil {
+		if bytes <= 0 {
+			return 0, fmt.Errorf("size budget must be positive, got %d", bytes)
+		}
+		return bytes, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return 0, fmt.Errorf("size budget must be a number of bytes or a string like \"250 KB\"")
+	}
+	return ParseSize(value)
+}
+
+// ParseSize parses sizes such as "512", "250 KB", "1.5MB" or "2 mb". Units
+// are binary (1 KB = 1024 bytes), matching the sizes printed by the build
+// summary.
+func ParseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := float64(1)
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{
+		{"KIB", 1024}, {"MIB", 1024 * 1024},
+		{"KB", 1024}, {"MB", 1024 * 1024},
+		{"K", 1024}, {"M", 1024 * 1024},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. \"250 KB\" or \"1.5 MB\")", value)
+	}
+	return int64(n * multiplier), nil
+}
+
+// MergeBudgets returns base with every budget set in override replacing the
+// corresponding base budget.
+func MergeBudgets(base *BudgetsConfig, override *BudgetsConfig) *BudgetsConfig {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	merged := BudgetsConfig{}
+	if base != nil {
+		merged = *base
+	}
+	if override != nil {
+		if override.Server != nil {
+			merged.Server = override.Server
+		}
+		if override.Client != nil {
+			merged.Client = override.Client
+		}
+		if override.UI != nil {
+			merged.UI = override.UI
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"512":    512,
+		"100 B":  100,
+		"250 KB": 250 * 1024,
+		"250kb":  250 * 1024,
+		"1.5MB":  1536 * 1024,
+		"2 MiB":  2 * 1024 * 1024,
+	}
+	for input, expected := range cases {
+		got, err := ParseSize(input)
+		if err != nil {
+			t.Errorf("ParseSize(%q) failed: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseSize(%q) = %d, expected %d", input, got, expected)
+		}
+	}
+
+	for _, input := range []string{"", "abc", "-5 KB", "10 GB"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Errorf("expected ParseSize(%q) to fail", input)
+		}
+	}
+}
+
+func TestBudgetsConfigUnmarshal(t *testing.T) {
+	var budgets BudgetsConfig
+	data := `{"server": 2048, "client": "500 KB", "ui": {"max": "1 MB", "soft": true}}`
+	if err := json.Unmarshal([]byte(data), &budgets); err != nil {
+		t.Fatalf("failed to parse budgets: %v", err)
+	}
+
+	if budgets.Server == nil || budgets.Server.Max != 2048 || budgets.Server.Soft {
+		t.Errorf("unexpected server budget: %+v", budgets.Server)
+	}
+	if budgets.Client == nil || budgets.Client.Max != 500*1024 {
+		t.Errorf("unexpected client budget: %+v", budgets.Client)
+	}
+	if budgets.UI == nil || budgets.UI.Max != 1024*1024 || !budgets.UI.Soft {
+		t.Errorf("unexpected ui budget: %+v", budgets.UI)
+	}
+
+	if err := json.Unmarshal([]byte(`{"client": {"soft": true}}`), &budgets); err == nil {
+		t.Error("expected budget without max to fail")
+	}
+}
+
+func TestMergeBudgets(t *testing.T) {
+	base := &BudgetsConfig{
+		Server: &SizeBudget{Max: 100},
+		Client: &SizeBudget{Max: 200},
+	}
+	merged := MergeBudgets(base, &BudgetsConfig{Client: &SizeBudget{Max: 50, Soft: true}})
+
+	if merged.Server.Max != 100 || merged.Client.Max != 50 || !merged.Client.Soft {
+		t.Fatalf("unexpected merged budgets: server=%+v client=%+v", merged.Server, merged.Client)
+	}
+	if base.Client.Max != 200 {
+		t.Fatal("expected base budgets to be left untouched")
+	}
+	if MergeBudgets(nil, nil) != nil {
+		t.Fatal("expected nil budgets when neither is set")
+	}
+}
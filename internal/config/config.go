@@ -10,6 +10,7 @@ import (
 )
 
 type Config struct {
+	Extends     []string          `json:"extends,omitempty"`
 	Name        string            `json:"name"`
 	OutDir      string            `json:"outDir"`
 	Destination string            `json:"destination,omitempty"`
@@ -19,7 +20,35 @@ type Config struct {
 	Standalones *StandaloneConfig `json:"standalones,omitempty"`
 	Modules     []string          `json:"modules"`
 	Build       BuildConfig       `json:"build"`
+	Deploy      DeployConfig      `json:"deploy,omitempty"`
 	Dev         DevConfig         `json:"dev"`
+
+	environmentApplied bool
+	// presets are the configs named in extends, in merge order.
+	presets []Preset
+	// workspaceRoot is the root of the workspace the project belongs to.
+	workspaceRoot string
+	// origins records where resolved values came from; see Explain.
+	origins map[string][]Origin
+}
+
+// DefaultDeployKeep is how many previous versions of each resource are kept
+// in the destination when deploy.keep is not set.
+const DefaultDeployKeep = 3
+
+type DeployConfig struct {
+	// Keep is the number of previous versions retained per resource for
+	// `opencore deploy rollback`, including .<name>.prev.
+	Keep int `json:"keep,omitempty"`
+}
+
+// KeepVersions returns the retention count, never less than one so the
+// previous version is always available.
+func (d DeployConfig) KeepVersions() int {
+	if d.Keep <= 0 {
+		return DefaultDeployKeep
+	}
+	return d.Keep
 }
 
 func normalizedConfigPath(p string) string {
@@ -79,7 +108,7 @@ type DevBridgeConfig struct {
 }
 
 type DevRestartConfig struct {
-	Mode string `json:"mode,omitempty"`
+	Mode string `json:"mode,omitempty" enum:"auto,process,txadmin,none"`
 }
 
 type DevTxAdminConfig struct {
@@ -298,10 +327,11 @@ type ResourceBuildConfig struct {
 	ServerBinaryPlatform string                      `json:"serverBinaryPlatform,omitempty"`
 	LogLevel             string                      `json:"logLevel,omitempty"`
 	DependencyResolution *DependencyResolutionConfig `json:"dependencyResolution,omitempty"`
+	Budgets              *BudgetsConfig              `json:"budgets,omitempty"`
 }
 
 type DependencyResolutionConfig struct {
-	Mode                string `json:"mode,omitempty"`
+	Mode                string `json:"mode,omitempty" enum:"auto,isolated,shared-resource,bundle,symlink"`
 	PackageManager      string `json:"packageManager,omitempty"`
 	SharedResourceName  string `json:"sharedResourceName,omitempty"`
 	VerifySandboxPaths  *bool  `json:"verifySandboxPaths,omitempty"`
@@ -333,6 +363,13 @@ func (s *ResourceBuildSideConfig) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (s ResourceBuildSideConfig) MarshalJSON() ([]byte, error) {
+	if s.Options != nil {
+		return json.Marshal(s.Options)
+	}
+	return json.Marshal(s.Enabled)
+}
+
 type StandaloneConfig struct {
 	Include  []string           `json:"include"`
 	Views    *ViewsConfig       `json:"views,omitempty"`
@@ -341,7 +378,7 @@ type StandaloneConfig struct {
 
 type ViewsConfig struct {
 	Path         string   `json:"path,omitempty"`
-	Framework    string   `json:"framework,omitempty"`
+	Framework    string   `json:"framework,omitempty" enum:"vanilla,vite"`
 	EntryPoint   string   `json:"entryPoint,omitempty"`   // Optional: explicit entry point (e.g., "main.ng.ts")
 	Ignore       []string `json:"ignore,omitempty"`       // Optional: ignore patterns (e.g., ["*.config.ts", "test/**"])
 	ForceInclude []string `json:"forceInclude,omitempty"` // Optional: force include static files by name
@@ -350,8 +387,8 @@ type ViewsConfig struct {
 }
 
 type BuildSideConfig struct {
-	Platform   string   `json:"platform,omitempty"`
-	Format     string   `json:"format,omitempty"`
+	Platform   string   `json:"platform,omitempty" enum:"node,browser,neutral"`
+	Format     string   `json:"format,omitempty" enum:"iife,cjs,esm"`
 	Target     string   `json:"target,omitempty"`
 	External   []string `json:"external,omitempty"`
 	Minify     *bool    `json:"minify,omitempty"`
@@ -364,22 +401,54 @@ type BuildConfig struct {
 	LogLevel             string                         `json:"logLevel,omitempty"`
 	Target               string                         `json:"target,omitempty"`
 	Parallel             bool                           `json:"parallel"`
+	FailFast             bool                           `json:"failFast,omitempty"`
 	MaxWorkers           int                            `json:"maxWorkers,omitempty"`
+	Cache                *bool                          `json:"cache,omitempty"`
+	Daemon               *bool                          `json:"daemon,omitempty"`
+	Metafile             bool                           `json:"metafile,omitempty"`
 	ServerBinaries       []string                       `json:"serverBinaries,omitempty"`
 	ServerBinaryPlatform string                         `json:"serverBinaryPlatform,omitempty"`
 	DependencyResolution *DependencyResolutionConfig    `json:"dependencyResolution,omitempty"`
 	Server               *BuildSideConfig               `json:"server,omitempty"`
 	Client               *BuildSideConfig               `json:"client,omitempty"`
+	Budgets              *BudgetsConfig                 `json:"budgets,omitempty"`
 	Environment          string                         `json:"environment,omitempty"`
 	Environments         map[string]EnvironmentOverride `json:"environments,omitempty"`
 }
 
-// EnvironmentOverride holds per-environment build overrides
+// CacheEnabled reports whether the incremental build cache is used (default true).
+func (b *BuildConfig) CacheEnabled() bool {
+	if b == nil || b.Cache == nil {
+		return true
+	}
+	return *b.Cache
+}
+
+// DaemonEnabled reports whether builds run on the persistent Node build
+// daemon (default true).
+func (b *BuildConfig) DaemonEnabled() bool {
+	if b == nil || b.Daemon == nil {
+		return true
+	}
+	return *b.Daemon
+}
+
+// EnvironmentOverride holds the settings an environment changes. Unset
+// fields keep the base configuration; see ApplyEnvironment.
 type EnvironmentOverride struct {
-	Minify           *bool             `json:"minify,omitempty"`
-	SourceMaps       *bool             `json:"sourceMaps,omitempty"`
-	LogLevel         string            `json:"logLevel,omitempty"`
-	FileReplacements []FileReplacement `json:"fileReplacements,omitempty"`
+	Minify               *bool                       `json:"minify,omitempty"`
+	SourceMaps           *bool                       `json:"sourceMaps,omitempty"`
+	LogLevel             string                      `json:"logLevel,omitempty"`
+	FileReplacements     []FileReplacement           `json:"fileReplacements,omitempty"`
+	OutDir               string                      `json:"outDir,omitempty"`
+	Destination          *string                     `json:"destination,omitempty"` // "" turns deployment off
+	Server               *BuildSideConfig            `json:"server,omitempty"`
+	Client               *BuildSideConfig            `json:"client,omitempty"`
+	DependencyResolution *DependencyResolutionConfig `json:"dependencyResolution,omitempty"`
+	// Resources enables (true) or disables (false) resources by name or
+	// glob. An exact name wins over globs; among globs, disabling wins.
+	Resources map[string]bool `json:"resources,omitempty"`
+	Dev       *DevConfig      `json:"dev,omitempty"`
 }
 
 // FileReplacement instructs esbuild to substitute one module/file for another
@@ -425,11 +494,46 @@ func LoadWithProjectRoot() (*Config, string, error) {
 		return nil, "", err
 	}
 
-	configPathAbs := filepath.Join(root, "opencore.config.ts")
+	config, err := LoadProject(root)
+	if err != nil {
+		return nil, "", err
+	}
+	return config, root, nil
+}
 
+// LoadProject reads and transpiles the opencore.config.ts of the project at
+// root, along with the resource configs colocated in its resource
+// directories. A project inside a workspace records the workspace root.
+func LoadProject(root string) (*Config, error) {
+	output, err := transpileConfig(filepath.Join(root, "opencore.config.ts"), root)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := parseConfig(output)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.loadResourceFiles(root); err != nil {
+		return nil, err
+	}
+	if workspaceRoot, err := FindWorkspaceRoot(root); err == nil {
+		config.workspaceRoot = workspaceRoot
+	}
+	return config, nil
+}
+
+// transpileConfig bundles a TypeScript config file with the esbuild of the
+// project in dir and returns its default export as JSON.
+func transpileConfig(configPathAbs, dir string) ([]byte, error) {
+	return runConfigLoader(dir, configPathAbs)
+}
+
+// runConfigLoader runs the config loader script in dir with args.
+func runConfigLoader(dir string, args ...string) ([]byte, error) {
 	// Check if Node.js is installed
 	if _, err := exec.LookPath("node"); err != nil {
-		return nil, "", fmt.Errorf("Node.js is not installed. Please install Node.js 18+ and try again")
+		return nil, fmt.Errorf("Node.js is not installed. Please install Node.js 18+ and try again")
 	}
 
 	// Create temporary transpiler script
@@ -530,20 +634,86 @@ async function loadConfig(configPath) {
   }
 }
 
+function serializeConfig(config) {
+  const serialized = {
+    ...config,
+    adapter: {
+      server: inspectAdapterBinding(config?.adapter?.server, '@open-core/fivem-adapter', '@open-core/fivem-adapter/server'),
+      client: inspectAdapterBinding(config?.adapter?.client, '@open-core/fivem-adapter', '@open-core/fivem-adapter/client'),
+    },
+  };
+
+  if (!serialized.adapter.server && !serialized.adapter.client) {
+    delete serialized.adapter;
+  }
+  return serialized;
+}
+
+const presetExtensions = ['.ts', '.mts', '.cts', '.js', '.mjs', '.cjs'];
+
+// resolvePreset resolves an extends entry: paths are relative to the config
+// that names them, anything else is a package resolved from that config.
+function resolvePreset(entry, fromPath) {
+  if (typeof entry !== 'string' || entry.trim() === '') {
+    throw new Error(fromPath + ': extends entries must be config file paths or package names');
+  }
+
+  if (!entry.startsWith('./') && !entry.startsWith('../') && !path.isAbsolute(entry)) {
+    return { source: entry, file: createRequire(fromPath).resolve(entry) };
+  }
+
+  const file = path.resolve(path.dirname(fromPath), entry);
+  const candidates = [file, ...presetExtensions.map((ext) => file + ext)];
+  const found = candidates.find((candidate) => fs.existsSync(candidate) && fs.statSync(candidate).isFile());
+  if (!found) {
+    throw new Error(fromPath + ': preset ' + entry + ' not found');
+  }
+  const relative = path.relative(process.cwd(), found).split(path.sep).join('/');
+  return { source: relative.startsWith('.') ? relative : './' + relative, file: found };
+}
+
+// loadPresets loads the configs named in extends depth first, so every
+// preset comes after the presets it extends itself.
+async function loadPresets(config, configPath, chain, presets) {
+  const entries = config?.extends;
+  if (entries === undefined) {
+    return presets;
+  }
+  if (!Array.isArray(entries)) {
+    throw new Error(configPath + ': extends must be an array');
+  }
+
+  for (const entry of entries) {
+    const preset = resolvePreset(entry, configPath);
+    if (chain.includes(preset.file)) {
+      throw new Error('extends cycle: ' + [...chain, preset.file].join(' -> '));
+    }
+    const presetConfig = await loadConfig(preset.file);
+    await loadPresets(presetConfig, preset.file, [...chain, preset.file], presets);
+    presets.push({ source: preset.source, file: preset.file, config: serializeConfig(presetConfig) });
+  }
+  return presets;
+}
+
 (async () => {
   try {
+    // --resources loads the colocated resource configs, keyed by file. They
+    // have no adapter or extends to resolve.
+    if (process.argv[2] === '--resources') {
+      const configs = {};
+      for (const file of process.argv.slice(3)) {
+        configs[file] = await loadConfig(path.resolve(file));
+      }
+      console.log(JSON.stringify(configs, null, 2));
+      return;
+    }
+
     const configPath = path.resolve(process.argv[2]);
     const config = await loadConfig(configPath);
-    const serialized = {
-      ...config,
-      adapter: {
-        server: inspectAdapterBinding(config?.adapter?.server, '@open-core/fivem-adapter', '@open-core/fivem-adapter/server'),
-        client: inspectAdapterBinding(config?.adapter?.client, '@open-core/fivem-adapter', '@open-core/fivem-adapter/client'),
-      },
-    };
-
-    if (!serialized.adapter.server && !serialized.adapter.client) {
-      delete serialized.adapter;
+    const serialized = serializeConfig(config);
+    const presets = await loadPresets(config, configPath, [configPath], []);
+    if (presets.length > 0) {
+      serialized['$presets'] = presets;
     }
 
     console.log(JSON.stringify(serialized, null, 2));
@@ -554,56 +724,86 @@ async function loadConfig(configPath) {
 })();
 `
 
-	// Write transpiler script to temp file
-	tmpFile := filepath.Join(os.TempDir(), "opencore-config-loader.js")
-	if err := os.WriteFile(tmpFile, []byte(transpilerScript), 0644); err != nil {
-		return nil, "", fmt.Errorf("failed to create transpiler script: %w", err)
+	// Write transpiler script to temp file. Every load gets its own file, as
+	// the projects of a workspace may be loaded at the same time.
+	tmp, err := os.CreateTemp("", "opencore-config-loader-*.js")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transpiler script: %w", err)
 	}
+	tmpFile := tmp.Name()
 	defer os.Remove(tmpFile)
+	_, err = tmp.WriteString(transpilerScript)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transpiler script: %w", err)
+	}
 
 	// Execute transpiler script
-	cmd := exec.Command("node", tmpFile, configPathAbs)
-	cmd.Dir = root
+	cmd := exec.Command("node", append([]string{tmpFile}, args...)...)
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to transpile config: %w\nOutput: %s", err, string(output))
+		return nil, fmt.Errorf("failed to transpile config: %w\nOutput: %s", err, string(output))
+	}
+	return output, nil
+}
+
+// parseConfig decodes the transpiled config, merges it over the presets it
+// extends and resolves its implicit values: the [category] output folders, runtime and adapter targets,
+// OPENCORE_* variables and legacy dev fields. Where each value came from is
+// recorded for Explain.
+func parseConfig(output []byte) (*Config, error) {
+	layers, err := configLayers(output)
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range layers {
+		if err := validateConfigTree(layer.tree, layer.name()); err != nil {
+			return nil, err
+		}
+	}
+	merged, err := json.Marshal(mergeLayers(layers))
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse JSON output
 	var config Config
-	if err := json.Unmarshal(output, &config); err != nil {
-		return nil, "", fmt.Errorf("failed to parse config JSON: %w\nOutput: %s", err, string(output))
+	if err := json.Unmarshal(merged, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config JSON: %w\nOutput: %s", err, string(output))
 	}
 
 	if strings.TrimSpace(config.Name) == "" {
-		return nil, "", fmt.Errorf("config.name is required")
+		return nil, fmt.Errorf("config.name is required")
+	}
+	if _, err := config.SharedModules(); err != nil {
+		return nil, err
+	}
+	for _, layer := range layers {
+		if layer.preset != nil {
+			config.presets = append(config.presets, *layer.preset)
+		}
+		config.recordLayer(layer)
 	}
 
 	runtimeKind := config.RuntimeKind()
-	category := config.Name
-	if !isBracketFolderName(category) {
-		category = fmt.Sprintf("[%s]", config.Name)
-	}
 
-	if strings.TrimSpace(config.Destination) != "" {
-		if runtimeKind == "ragemp" {
-			config.Destination = strings.TrimSpace(config.Destination)
-			config.OutDir = config.Destination
-		} else {
-			config.Destination = filepath.Join(strings.TrimSpace(config.Destination), category)
-			config.OutDir = config.Destination
-		}
-	} else {
-		outBase := strings.TrimSpace(config.OutDir)
-		if outBase == "" {
-			outBase = "build"
-		}
-		if runtimeKind == "ragemp" {
-			config.OutDir = outBase
-		} else {
-			config.OutDir = filepath.Join(outBase, category)
-		}
-		config.Destination = ""
+	// Resources are always built into outDir; with a destination they are then
+	// deployed there through staging directories.
+	outBase := strings.TrimSpace(config.OutDir)
+	if outBase == "" {
+		outBase = "build"
+		config.record("outDir", OriginDefault, "", outBase)
+	}
+	destinationBase := config.Destination
+	config.OutDir = config.categoryDir(outBase)
+	config.Destination = config.categoryDir(destinationBase)
+	if config.OutDir != outBase {
+		config.record("outDir", OriginDefault, "category folder", config.OutDir)
+	}
+	if config.Destination != destinationBase {
+		config.record("destination", OriginDefault, "category folder", config.Destination)
 	}
 
 	config.ensureBuildSideConfigs()
@@ -611,46 +811,91 @@ async function loadConfig(configPath) {
 	if strings.TrimSpace(config.Build.Server.Target) == "" {
 		if adapterTarget := config.adapterSideTarget("server"); adapterTarget != "" {
 			config.Build.Server.Target = adapterTarget
+			config.record("build.server.target", OriginAdapter, "adapter.server.runtime.server.target", adapterTarget)
 		} else if legacyTarget != "" {
 			config.Build.Server.Target = legacyTarget
+			config.record("build.server.target", OriginLegacy, "build.target", legacyTarget)
 		} else if runtimeKind == "ragemp" {
 			config.Build.Server.Target = "node14"
+			config.record("build.server.target", OriginDefault, "ragemp runtime", "node14")
 		} else {
 			config.Build.Server.Target = "ES2020"
+			config.record("build.server.target", OriginDefault, "", "ES2020")
 		}
 	}
 	if strings.TrimSpace(config.Build.Client.Target) == "" {
 		if adapterTarget := config.adapterSideTarget("client"); adapterTarget != "" {
 			config.Build.Client.Target = adapterTarget
+			config.record("build.client.target", OriginAdapter, "adapter.client.runtime.client.target", adapterTarget)
 		} else if legacyTarget != "" {
 			config.Build.Client.Target = legacyTarget
+			config.record("build.client.target", OriginLegacy, "build.target", legacyTarget)
 		} else {
 			config.Build.Client.Target = "ES2020"
+			config.record("build.client.target", OriginDefault, "", "ES2020")
 		}
 	}
 	if config.Build.LogLevel == "" {
 		config.Build.LogLevel = "INFO"
+		config.record("build.logLevel", OriginDefault, "", "INFO")
 	}
 
 	// Environment variables override config file (higher priority)
-	if envURL := os.Getenv("OPENCORE_TXADMIN_URL"); envURL != "" {
-		config.Dev.TxAdmin.URL = envURL
-	}
-	if envUser := os.Getenv("OPENCORE_TXADMIN_USER"); envUser != "" {
-		config.Dev.TxAdmin.User = envUser
-	}
-	if envPass := os.Getenv("OPENCORE_TXADMIN_PASSWORD"); envPass != "" {
-		config.Dev.TxAdmin.Password = envPass
-	}
+	config.applyEnvironmentVariables()
 	if envName := os.Getenv("OPENCORE_ENVIRONMENT"); envName != "" {
 		config.Build.Environment = envName
+		config.record("build.environment", OriginEnvVar, "OPENCORE_ENVIRONMENT", envName)
 	}
 	if config.Build.Environment == "" {
 		config.Build.Environment = "development"
+		config.record("build.environment", OriginDefault, "", "development")
 	}
-	config.Dev.Normalize()
+	config.normalizeDev()
+
+	return &config, nil
+}
 
-	return &config, root, nil
+// categoryDir places a resources directory in the project's [category]
+// folder, except for RageMP, which has no categories. An empty dir stays empty.
+func (c *Config) categoryDir(dir string) string {
+	dir = strings.TrimSpace(dir)
+	if dir == "" || c.RuntimeKind() == "ragemp" {
+		return dir
+	}
+	return filepath.Join(dir, c.categoryFolder())
+}
+
+// categoryParent is the inverse of categoryDir.
+func (c *Config) categoryParent(dir string) string {
+	if dir == "" || c.RuntimeKind() == "ragemp" || filepath.Base(dir) != c.categoryFolder() {
+		return dir
+	}
+	return filepath.Dir(dir)
+}
+
+func (c *Config) categoryFolder() string {
+	if isBracketFolderName(c.Name) {
+		return c.Name
+	}
+	return fmt.Sprintf("[%s]", c.Name)
+}
+
+// applyEnvironmentVariables applies the OPENCORE_TXADMIN_* variables, which
+// take precedence over the config file and its environments. Only changes
+// are recorded, since environments apply them again.
+func (c *Config) applyEnvironmentVariables() {
+	if envURL := os.Getenv("OPENCORE_TXADMIN_URL"); envURL != "" && envURL != c.Dev.TxAdmin.URL {
+		c.Dev.TxAdmin.URL = envURL
+		c.record("dev.txAdmin.url", OriginEnvVar, "OPENCORE_TXADMIN_URL", envURL)
+	}
+	if envUser := os.Getenv("OPENCORE_TXADMIN_USER"); envUser != "" && envUser != c.Dev.TxAdmin.User {
+		c.Dev.TxAdmin.User = envUser
+		c.record("dev.txAdmin.user", OriginEnvVar, "OPENCORE_TXADMIN_USER", envUser)
+	}
+	if envPass := os.Getenv("OPENCORE_TXADMIN_PASSWORD"); envPass != "" && envPass != c.Dev.TxAdmin.Password {
+		c.Dev.TxAdmin.Password = envPass
+		c.record("dev.txAdmin.password", OriginEnvVar, "OPENCORE_TXADMIN_PASSWORD", envPass)
+	}
 }
 
 // Load reads and transpiles opencore.config.ts to Config.
@@ -682,7 +927,7 @@ func (c *Config) GetResourcePaths() []string {
 				// Avoid duplicates
 				isDuplicate := false
 				for _, existing := range paths {
-					if existing == match {
+					if normalizedConfigPath(existing) == match {
 						isDuplicate = true
 						break
 					}
@@ -721,7 +966,7 @@ func (c *Config) GetStandalonePaths() []string {
 			if err == nil && info.IsDir() {
 				isDuplicate := false
 				for _, existing := range paths {
-					if existing == match {
+					if normalizedConfigPath(existing) == match {
 						isDuplicate = true
 						break
 					}
@@ -743,7 +988,7 @@ func (c *Config) ShouldCompile(path string) bool {
 	}
 
 	for _, res := range c.Standalones.Explicit {
-		if res.Path == path {
+		if normalizedConfigPath(res.Path) == normalizedConfigPath(path) {
 			if res.Compile != nil {
 				return *res.Compile
 			}
@@ -756,20 +1001,22 @@ func (c *Config) ShouldCompile(path string) bool {
 
 // GetResourceViews returns views config for a specific resource path
 func (c *Config) GetResourceViews(path string) *ViewsConfig {
+	path = normalizedConfigPath(path)
+
 	// Check core
-	if path == c.Core.Path && c.Core.Views != nil {
+	if path == normalizedConfigPath(c.Core.Path) && c.Core.Views != nil {
 		return c.Core.Views
 	}
 
 	// Check explicit resources
 	for _, res := range c.Resources.Explicit {
-		if res.Path == path && res.Views != nil {
+		if normalizedConfigPath(res.Path) == path && res.Views != nil {
 			return res.Views
 		}
 	}
 	if c.Resources.Views != nil {
 		for _, res := range c.Resources.Explicit {
-			if res.Path == path {
+			if normalizedConfigPath(res.Path) == path {
 				return c.Resources.Views
 			}
 		}
@@ -778,13 +1025,13 @@ func (c *Config) GetResourceViews(path string) *ViewsConfig {
 	// Check standalone
 	if c.Standalones != nil {
 		for _, res := range c.Standalones.Explicit {
-			if res.Path == path && res.Views != nil {
+			if normalizedConfigPath(res.Path) == path && res.Views != nil {
 				return res.Views
 			}
 		}
 		if c.Standalones.Views != nil {
 			for _, res := range c.Standalones.Explicit {
-				if res.Path == path {
+				if normalizedConfigPath(res.Path) == path {
 					return c.Standalones.Views
 				}
 			}
@@ -821,14 +1068,16 @@ func (c *Config) GetExplicitStandalone(path string) *ExplicitResource {
 
 // GetCustomCompiler returns the custom compiler path for a specific resource, or empty if using default
 func (c *Config) GetCustomCompiler(resourcePath string) string {
+	resourcePath = normalizedConfigPath(resourcePath)
+
 	// Check core
-	if resourcePath == c.Core.Path {
+	if resourcePath == normalizedConfigPath(c.Core.Path) {
 		return c.Core.CustomCompiler
 	}
 
 	// Check explicit resources
 	for _, res := range c.Resources.Explicit {
-		if res.Path == resourcePath {
+		if normalizedConfigPath(res.Path) == resourcePath {
 			return res.CustomCompiler
 		}
 	}
@@ -836,7 +1085,7 @@ func (c *Config) GetCustomCompiler(resourcePath string) string {
 	// Check standalone
 	if c.Standalones != nil {
 		for _, res := range c.Standalones.Explicit {
-			if res.Path == resourcePath {
+			if normalizedConfigPath(res.Path) == resourcePath {
 				return res.CustomCompiler
 			}
 		}
@@ -112,9 +112,8 @@ func TestConfigParsing(t *testing.T) {
 		t.Errorf("Expected name 'test-project', got '%s'", cfg.Name)
 	}
 
-	// OutDir should match Destination as we forced it in Load()
-	// But in this raw Unmarshal test it stays as is.
-	// The logic for forcing OutDir = Destination is in Load() which we aren't testing here directly with json.Unmarshal.
+	// Load() appends the project category to OutDir and Destination; a raw
+	// Unmarshal keeps them as written.
 	if cfg.Destination != "C:/FXServer/resources" {
 		t.Errorf("Expected destination 'C:/FXServer/resources', got '%s'", cfg.Destination)
 	}
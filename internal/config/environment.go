@@ -0,0 +1,258 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ActiveEnvironment returns the overrides of the active environment, if it
+// defines any.
+func (c *Config) ActiveEnvironment() (EnvironmentOverride, bool) {
+	override, ok := c.Build.Environments[c.Build.Environment]
+	return override, ok
+}
+
+// ApplyEnvironment merges the overrides of the active environment
+// (build.environment) onto the configuration. Settings are resolved in this
+// order, each step overriding the previous one:
+//
+//  1. opencore.config.ts
+//  2. build.environments[<environment>]
+//  3. OPENCORE_TXADMIN_* environment variables
+//  4. command-line flags, which commands apply after this call
+//
+// Only the first call has an effect, so the environment must be chosen
+// before it.
+func (c *Config) ApplyEnvironment() error {
+	if c.environmentApplied {
+		return nil
+	}
+	c.environmentApplied = true
+
+	override, ok := c.ActiveEnvironment()
+	if !ok {
+		return nil
+	}
+	if err := c.validateOverride(override); err != nil {
+		return fmt.Errorf("build.environments.%s: %w", c.Build.Environment, err)
+	}
+
+	if override.Minify != nil {
+		c.Build.Minify = *override.Minify
+	}
+	if override.SourceMaps != nil {
+		c.Build.SourceMaps = *override.SourceMaps
+	}
+	if override.LogLevel != "" {
+		c.Build.LogLevel = override.LogLevel
+	}
+	if outDir := strings.TrimSpace(override.OutDir); outDir != "" {
+		c.OutDir = c.categoryDir(outDir)
+	}
+	if override.Destination != nil {
+		c.Destination = c.categoryDir(*override.Destination)
+	}
+	c.Build.Server = mergeSideOverride(c.Build.Server, override.Server)
+	c.Build.Client = mergeSideOverride(c.Build.Client, override.Client)
+	c.Build.DependencyResolution = mergeDependencyResolution(c.Build.DependencyResolution, override.DependencyResolution)
+	if override.Dev != nil {
+		c.Dev.merge(*override.Dev)
+	}
+	c.recordOverride(override)
+
+	c.applyEnvironmentVariables()
+	c.normalizeDev()
+	return nil
+}
+
+// UseEnvironment selects the environment to apply, e.g. from a command-line
+// flag; source names where the choice came from.
+func (c *Config) UseEnvironment(name, source string) {
+	c.Build.Environment = name
+	c.record("build.environment", OriginFlag, source, name)
+}
+
+// ForEnvironment returns a copy of the configuration with the overrides of
+// the named environment applied, for building several environments in one
+// run. Unless the environment sets its own outDir, it is built into
+// <outDir>/<name>, and it is only deployed when it sets its own destination,
+// so the environments never overwrite each other.
+func (c *Config) ForEnvironment(name string) (*Config, error) {
+	if c.environmentApplied {
+		return nil, fmt.Errorf("environment %q is already applied", c.Build.Environment)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+	var env Config
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+
+	env.origins = make(map[string][]Origin, len(c.origins))
+	for path, origins := range c.origins {
+		env.origins[path] = append([]Origin(nil), origins...)
+	}
+	env.presets = c.presets
+	env.workspaceRoot = c.workspaceRoot
+	env.UseEnvironment(name, "--env")
+	override := env.Build.Environments[name]
+	if strings.TrimSpace(override.OutDir) == "" {
+		env.OutDir = env.categoryDir(filepath.Join(env.categoryParent(env.OutDir), name))
+	}
+	if override.Destination == nil {
+		env.Destination = ""
+	}
+	if err := env.ApplyEnvironment(); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+func (c *Config) validateOverride(override EnvironmentOverride) error {
+	for pattern, enabled := range override.Resources {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid resource pattern %q: %w", pattern, err)
+		}
+		if !enabled && pattern == c.Core.ResourceName {
+			return fmt.Errorf("the core resource %q cannot be disabled", pattern)
+		}
+	}
+	return nil
+}
+
+// ResourceEnabled reports whether the active environment builds the named
+// resource. The core resource is always built.
+func (c *Config) ResourceEnabled(name string) bool {
+	override, ok := c.ActiveEnvironment()
+	if !ok || len(override.Resources) == 0 || name == c.Core.ResourceName {
+		return true
+	}
+	if enabled, ok := override.Resources[name]; ok {
+		return enabled
+	}
+	for pattern, enabled := range override.Resources {
+		if matched, _ := path.Match(pattern, name); matched && !enabled {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeSideOverride returns base with the fields set in override replaced.
+func mergeSideOverride(base, override *BuildSideConfig) *BuildSideConfig {
+	if override == nil {
+		return base
+	}
+	merged := BuildSideConfig{}
+	if base != nil {
+		merged = *base
+	}
+	if override.Platform != "" {
+		merged.Platform = override.Platform
+	}
+	if override.Format != "" {
+		merged.Format = override.Format
+	}
+	if override.Target != "" {
+		merged.Target = override.Target
+	}
+	if override.External != nil {
+		merged.External = override.External
+	}
+	if override.Minify != nil {
+		merged.Minify = override.Minify
+	}
+	if override.SourceMaps != nil {
+		merged.SourceMaps = override.SourceMaps
+	}
+	return &merged
+}
+
+// mergeDependencyResolution returns base with the fields set in override
+// replaced.
+func mergeDependencyResolution(base, override *DependencyResolutionConfig) *DependencyResolutionConfig {
+	if override == nil {
+		return base
+	}
+	merged := DependencyResolutionConfig{}
+	if base != nil {
+		merged = *base
+	}
+	if override.Mode != "" {
+		merged.Mode = override.Mode
+	}
+	if override.PackageManager != "" {
+		merged.PackageManager = override.PackageManager
+	}
+	if override.SharedResourceName != "" {
+		merged.SharedResourceName = override.SharedResourceName
+	}
+	if override.VerifySandboxPaths != nil {
+		merged.VerifySandboxPaths = override.VerifySandboxPaths
+	}
+	if override.AllowInstallScripts != nil {
+		merged.AllowInstallScripts = override.AllowInstallScripts
+	}
+	if override.Cache != nil {
+		merged.Cache = override.Cache
+	}
+	return &merged
+}
+
+// merge replaces the dev settings set in override. The legacy top-level
+// port and txAdmin fields are accepted as in the base config.
+func (d *DevConfig) merge(override DevConfig) {
+	port := override.Bridge.Port
+	if port == 0 {
+		port = override.Port
+	}
+	if port > 0 {
+		d.Bridge.Port, d.Port = port, port
+	}
+	if override.Restart.Mode != "" {
+		d.Restart.Mode = override.Restart.Mode
+	}
+	if url := firstNonEmpty(override.TxAdmin.URL, override.TxAdminURL); url != "" {
+		d.TxAdmin.URL, d.TxAdminURL = url, url
+	}
+	if user := firstNonEmpty(override.TxAdmin.User, override.TxAdminUser); user != "" {
+		d.TxAdmin.User, d.TxAdminUser = user, user
+	}
+	if password := firstNonEmpty(override.TxAdmin.Password, override.TxAdminPassword); password != "" {
+		d.TxAdmin.Password, d.TxAdminPassword = password, password
+	}
+
+	process := override.Process
+	if process.Command != "" {
+		d.Process.Command = process.Command
+	}
+	if process.Args != nil {
+		d.Process.Args = process.Args
+	}
+	if process.Cwd != "" {
+		d.Process.Cwd = process.Cwd
+	}
+	if process.Env != nil {
+		d.Process.Env = process.Env
+	}
+	if process.StopSignal != "" {
+		d.Process.StopSignal = process.StopSignal
+	}
+	if process.StopTimeoutMs > 0 {
+		d.Process.StopTimeoutMs = process.StopTimeoutMs
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func environmentConfig(t *testing.T) *Config {
+	t.Helper()
+	var cfg Config
+	err := json.Unmarshal([]byte(`{
+		"name": "server",
+		"core": {"path": "./core", "resourceName": "core"},
+		"build": {
+			"minify": false,
+			"server": {"target": "ES2020", "external": ["pg"]},
+			"dependencyResolution": {"mode": "bundle", "packageManager": "pnpm"},
+			"environments": {
+				"production": {
+					"minify": true,
+					"outDir": "dist",
+					"destination": "/srv/resources",
+					"server": {"target": "node22"},
+					"dependencyResolution": {"mode": "shared"},
+					"resources": {"devtools": false, "debug-*": false, "debug-hud": true},
+					"dev": {"restart": {"mode": "txadmin"}, "txAdmin": {"url": "http://prod:40120", "user": "ops"}}
+				},
+				"local": {"destination": ""}
+			}
+		},
+		"dev": {"restart": {"mode": "auto"}, "txAdmin": {"url": "http://localhost:40120", "user": "admin", "password": "secret"}}
+	}`), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.OutDir = cfg.categoryDir("build")
+	cfg.Destination = cfg.categoryDir("/fx/resources")
+	cfg.Dev.Normalize()
+	return &cfg
+}
+
+func TestApplyEnvironmentOverrides(t *testing.T) {
+	cfg := environmentConfig(t)
+	cfg.Build.Environment = "production"
+	if err := cfg.ApplyEnvironment(); err != nil {
+		t.Fatalf("ApplyEnvironment failed: %v", err)
+	}
+
+	if !cfg.Build.Minify {
+		t.Error("expected minify to be overridden")
+	}
+	if cfg.OutDir != filepath.Join("dist", "[server]") || cfg.Destination != filepath.Join("/srv/resources", "[server]") {
+		t.Errorf("expected output paths in the category folder, got %s and %s", cfg.OutDir, cfg.Destination)
+	}
+	if cfg.Build.Server.Target != "node22" || strings.Join(cfg.Build.Server.External, ",") != "pg" {
+		t.Errorf("expected the server target to be overridden and externals kept, got %+v", cfg.Build.Server)
+	}
+	if dr := cfg.Build.DependencyResolution; dr.Mode != "shared" || dr.PackageManager != "pnpm" {
+		t.Errorf("expected dependency resolution to be merged, got %+v", dr)
+	}
+	if cfg.Dev.RestartMode() != "txadmin" || cfg.Dev.TxAdmin.URL != "http://prod:40120" || cfg.Dev.TxAdmin.User != "ops" || cfg.Dev.TxAdmin.Password != "secret" {
+		t.Errorf("expected dev settings to be merged, got %+v", cfg.Dev)
+	}
+
+	// Only the first call applies.
+	cfg.Build.Minify = false
+	if err := cfg.ApplyEnvironment(); err != nil || cfg.Build.Minify {
+		t.Errorf("expected ApplyEnvironment to apply once, got minify=%v err=%v", cfg.Build.Minify, err)
+	}
+}
+
+func TestApplyEnvironmentPrecedence(t *testing.T) {
+	t.Setenv("OPENCORE_TXADMIN_URL", "http://from-env:40120")
+	cfg := environmentConfig(t)
+	cfg.Build.Environment = "production"
+	if err := cfg.ApplyEnvironment(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Dev.TxAdmin.URL != "http://from-env:40120" {
+		t.Errorf("expected OPENCORE_TXADMIN_URL to win over the environment, got %s", cfg.Dev.TxAdmin.URL)
+	}
+}
+
+func TestApplyEnvironmentCanDisableDeployment(t *testing.T) {
+	cfg := environmentConfig(t)
+	cfg.Build.Environment = "local"
+	if err := cfg.ApplyEnvironment(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Destination != "" {
+		t.Errorf("expected an empty destination override to turn deployment off, got %q", cfg.Destination)
+	}
+	if cfg.Build.Minify || cfg.OutDir != filepath.Join("build", "[server]") {
+		t.Error("expected settings the environment does not set to be kept")
+	}
+}
+
+func TestResourceEnabled(t *testing.T) {
+	cfg := environmentConfig(t)
+	cfg.Build.Environment = "production"
+	cases := map[string]bool{
+		"chat":      true,
+		"devtools":  false,
+		"debug-map": false,
+		"debug-hud": true,
+		"core":      true,
+	}
+	for name, want := range cases {
+		if got := cfg.ResourceEnabled(name); got != want {
+			t.Errorf("%s: expected enabled=%v, got %v", name, want, got)
+		}
+	}
+
+	cfg.Build.Environment = "development"
+	if !cfg.ResourceEnabled("devtools") {
+		t.Error("expected environments without overrides to build every resource")
+	}
+}
+
+func TestApplyEnvironmentRejectsInvalidResources(t *testing.T) {
+	cfg := environmentConfig(t)
+	cfg.Build.Environment = "production"
+	cfg.Build.Environments["production"] = EnvironmentOverride{Resources: map[string]bool{"core": false}}
+	if err := cfg.ApplyEnvironment(); err == nil || !strings.Contains(err.Error(), "cannot be disabled") {
+		t.Errorf("expected disabling core to fail, got %v", err)
+	}
+
+	cfg = environmentConfig(t)
+	cfg.Build.Environment = "production"
+	cfg.Build.Environments["production"] = EnvironmentOverride{Resources: map[string]bool{"[chat": false}}
+	if err := cfg.ApplyEnvironment(); err == nil || !strings.Contains(err.Error(), "build.environments.production") {
+		t.Errorf("expected an invalid pattern to fail, got %v", err)
+	}
+}
+
+func TestForEnvironment(t *testing.T) {
+	cfg := environmentConfig(t)
+	cfg.Resources.Explicit = []ExplicitResource{{
+		Path: "./resources/chat",
+		Build: &ResourceBuildConfig{
+			Server: &ResourceBuildSideConfig{Enabled: true, Options: &BuildSideConfig{Target: "node22"}},
+			Client: &ResourceBuildSideConfig{Enabled: false},
+		},
+	}}
+
+	staging, err := cfg.ForEnvironment("staging")
+	if err != nil {
+		t.Fatalf("ForEnvironment failed: %v", err)
+	}
+	if staging.Build.Environment != "staging" || staging.OutDir != filepath.Join("build", "staging", "[server]") {
+		t.Errorf("expected staging in build/staging/[server], got %s in %s", staging.Build.Environment, staging.OutDir)
+	}
+	if staging.Destination != "" {
+		t.Errorf("expected environments without a destination not to deploy, got %q", staging.Destination)
+	}
+	explicit := staging.Resources.Explicit[0].Build
+	if explicit.Server.Options == nil || explicit.Server.Options.Target != "node22" || explicit.Client.Enabled {
+		t.Errorf("expected per-resource side settings to be copied, got %+v %+v", explicit.Server, explicit.Client)
+	}
+
+	production, err := cfg.ForEnvironment("production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !production.Build.Minify || production.OutDir != filepath.Join("dist", "[server]") || production.Destination != filepath.Join("/srv/resources", "[server]") {
+		t.Errorf("expected the production overrides, got minify=%v outDir=%s destination=%s", production.Build.Minify, production.OutDir, production.Destination)
+	}
+
+	if cfg.Build.Minify || cfg.OutDir != filepath.Join("build", "[server]") || cfg.Build.Server.Target != "ES2020" {
+		t.Error("expected the base config to be left untouched")
+	}
+	staging.Build.Server.External[0] = "changed"
+	if cfg.Build.Server.External[0] != "pg" {
+		t.Error("expected environment configs not to share slices with the base config")
+	}
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// presetsKey is the key under which the loader passes the configs named in
+// extends, resolved and in merge order. It is not a config option.
+const presetsKey = "$presets"
+
+// Preset is a config named in extends.
+type Preset struct {
+	// Source is the package name, or the file path relative to the project.
+	Source string `json:"source"`
+	// File is the resolved config file.
+	File string `json:"file"`
+}
+
+// configLayer is one config file in merge order: the presets first, the
+// project's opencore.config.ts last.
+type configLayer struct {
+	preset *Preset
+	tree   map[string]any
+}
+
+// name is how errors and origins refer to the layer.
+func (l configLayer) name() string {
+	if l.preset != nil {
+		return l.preset.Source
+	}
+	return "opencore.config.ts"
+}
+
+// unionPaths are the lists a config adds to what it extends.
+var unionPaths = map[string]bool{
+	"resources.include":   true,
+	"standalones.include": true,
+}
+
+// keyedPaths are the lists whose entries are merged by their path.
+var keyedPaths = map[string]bool{
+	"resources.explicit":   true,
+	"standalones.explicit": true,
+}
+
+// replacedPaths are objects a config replaces as a whole.
+var replacedPaths = map[string]bool{
+	"adapter.server": true,
+	"adapter.client": true,
+}
+
+// configLayers splits the transpiled config into the presets it extends and
+// the project's own config.
+func configLayers(output []byte) ([]configLayer, error) {
+	var tree map[string]any
+	if err := json.Unmarshal(output, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse config JSON: %w\nOutput: %s", err, string(output))
+	}
+
+	var layers []configLayer
+	if raw, ok := tree[presetsKey]; ok {
+		delete(tree, presetsKey)
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		var presets []struct {
+			Preset
+			Config map[string]any `json:"config"`
+		}
+		if err := json.Unmarshal(data, &presets); err != nil {
+			return nil, fmt.Errorf("failed to parse presets: %w", err)
+		}
+		for _, preset := range presets {
+			// Presets are flattened by the loader; their own extends is resolved.
+			delete(preset.Config, "extends")
+			p := preset.Preset
+			layers = append(layers, configLayer{preset: &p, tree: preset.Config})
+		}
+	}
+	return append(layers, configLayer{tree: tree}), nil
+}
+
+// mergeLayers deep-merges the layers in order, later layers winning.
+func mergeLayers(layers []configLayer) map[string]any {
+	merged := map[string]any{}
+	for _, layer := range layers {
+		merged = mergeValue("", merged, layer.tree).(map[string]any)
+	}
+	return merged
+}
+
+// mergeValue merges override into base. Objects merge key by key and a null
+// removes the inherited value. Lists replace the inherited list, except
+// include patterns, which are combined, and explicit resources, which merge
+// by path.
+func mergeValue(path string, base, override any) any {
+	switch {
+	case unionPaths[path]:
+		baseList, ok1 := base.([]any)
+		overrideList, ok2 := override.([]any)
+		if ok1 && ok2 {
+			return unionList(baseList, overrideList)
+		}
+	case keyedPaths[path]:
+		baseList, ok1 := base.([]any)
+		overrideList, ok2 := override.([]any)
+		if ok1 && ok2 {
+			return mergeByPath(path, baseList, overrideList)
+		}
+	case replacedPaths[path]:
+		return override
+	}
+
+	baseObject, ok1 := base.(map[string]any)
+	overrideObject, ok2 := override.(map[string]any)
+	if !ok1 || !ok2 {
+		return override
+	}
+	merged := make(map[string]any, len(baseObject)+len(overrideObject))
+	for key, value := range baseObject {
+		merged[key] = value
+	}
+	for key, value := range overrideObject {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		if inherited, ok := merged[key]; ok {
+			value = mergeValue(joinPath(path, key), inherited, value)
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+func unionList(base, override []any) []any {
+	merged := append([]any{}, base...)
+	for _, value := range override {
+		if !containsValue(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+func mergeByPath(path string, base, override []any) []any {
+	merged := append([]any{}, base...)
+	for _, value := range override {
+		key := entryPath(value)
+		index := -1
+		for i, existing := range merged {
+			if key != "" && entryPath(existing) == key {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			merged = append(merged, value)
+		} else {
+			merged[index] = mergeValue(path+"[]", merged[index], value)
+		}
+	}
+	return merged
+}
+
+// entryPath is the normalized path of an explicit resource entry.
+func entryPath(value any) string {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return ""
+	}
+	p, _ := object["path"].(string)
+	return normalizedConfigPath(p)
+}
+
+func containsValue(values []any, value any) bool {
+	for _, existing := range values {
+		if reflect.DeepEqual(existing, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Presets returns the configs the project extends, in merge order.
+func (c *Config) Presets() []Preset {
+	return c.presets
+}
+
+// recordLayer records the values a config file or preset sets. The adapter
+// section is not written by hand; the loader fills it in from the adapter
+// packages.
+func (c *Config) recordLayer(layer configLayer) {
+	kind := OriginConfigFile
+	if layer.preset != nil {
+		kind = OriginPreset
+	}
+	for path, value := range flatten("", layer.tree) {
+		if strings.HasPrefix(path, "adapter.") {
+			c.record(path, OriginAdapter, "adapter package", value)
+		} else {
+			c.record(path, kind, layer.name(), value)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const presetConfigOutput = `{
+  "extends": ["@team/preset", "./base.config.ts"],
+  "name": "demo",
+  "core": {"path": "./core", "resourceName": "core"},
+  "resources": {
+    "include": ["./resources/*", "./extra/*"],
+    "explicit": [{"path": "./resources/chat", "build": {"client": {"format": "esm"}}}]
+  },
+  "build": {"minify": null, "environments": {"production": {"dev": {"bridge": {"port": 4100}}}}},
+  "dev": {"txAdmin": {"user": "me"}},
+  "$presets": [
+    {
+      "source": "@team/preset",
+      "file": "/project/node_modules/@team/preset/index.js",
+      "config": {
+        "outDir": "./dist",
+        "resources": {
+          "include": ["./resources/*"],
+          "explicit": [{"path": "resources/chat", "compile": true, "build": {"client": {"target": "es2020"}}}]
+        },
+        "build": {"minify": true, "sourceMaps": true, "server": {"external": ["pg"], "target": "node18"}},
+        "dev": {"bridge": {"port": 4000}, "txAdmin": {"url": "http://localhost:40120", "user": "admin"}}
+      }
+    },
+    {
+      "source": "./base.config.ts",
+      "file": "/project/base.config.ts",
+      "config": {
+        "extends": ["@team/preset"],
+        "build": {
+          "sourceMaps": false,
+          "server": {"external": ["mysql2"]},
+          "environments": {"production": {"minify": true}}
+        }
+      }
+    }
+  ]
+}`
+
+func TestParseConfigMergesPresets(t *testing.T) {
+	cfg, err := parseConfig([]byte(presetConfigOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.OutDir != "dist/[demo]" {
+		t.Errorf("expected outDir from the preset, got %q", cfg.OutDir)
+	}
+	if cfg.Build.Minify {
+		t.Error("expected null to remove the inherited build.minify")
+	}
+	if cfg.Build.SourceMaps {
+		t.Error("expected the later preset to win for build.sourceMaps")
+	}
+	if cfg.Build.Server.Target != "node18" || !reflect.DeepEqual(cfg.Build.Server.External, []string{"mysql2"}) {
+		t.Errorf("expected objects to merge and lists to replace, got %+v", cfg.Build.Server)
+	}
+	if want := []string{"./resources/*", "./extra/*"}; !reflect.DeepEqual(cfg.Resources.Include, want) {
+		t.Errorf("expected include patterns %v, got %v", want, cfg.Resources.Include)
+	}
+	if len(cfg.Resources.Explicit) != 1 {
+		t.Fatalf("expected explicit resources to merge by path, got %+v", cfg.Resources.Explicit)
+	}
+	chat := cfg.Resources.Explicit[0]
+	if chat.Compile == nil || !*chat.Compile || chat.Build == nil || chat.Build.Client == nil ||
+		chat.Build.Client.Options == nil || chat.Build.Client.Options.Target != "es2020" || chat.Build.Client.Options.Format != "esm" {
+		t.Errorf("expected the chat entry to combine both configs, got %+v", chat)
+	}
+	production := cfg.Build.Environments["production"]
+	if production.Minify == nil || !*production.Minify || production.Dev == nil || production.Dev.Bridge.Port != 4100 {
+		t.Errorf("expected environments to merge, got %+v", production)
+	}
+	if cfg.Dev.TxAdmin.URL != "http://localhost:40120" || cfg.Dev.TxAdmin.User != "me" {
+		t.Errorf("expected dev settings to merge, got %+v", cfg.Dev.TxAdmin)
+	}
+	if want := []string{"@team/preset", "./base.config.ts"}; !reflect.DeepEqual(cfg.Extends, want) {
+		t.Errorf("expected extends %v, got %v", want, cfg.Extends)
+	}
+	if presets := cfg.Presets(); len(presets) != 2 || presets[1].File != "/project/base.config.ts" {
+		t.Errorf("unexpected presets %+v", presets)
+	}
+}
+
+func TestParseConfigRecordsPresetOrigins(t *testing.T) {
+	cfg, err := parseConfig([]byte(presetConfigOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sourceMaps := explainOne(t, cfg, "build.sourceMaps")
+	want := []Origin{
+		{Kind: OriginPreset, Source: "@team/preset", Value: true},
+		{Kind: OriginPreset, Source: "./base.config.ts", Value: false},
+	}
+	if !reflect.DeepEqual(sourceMaps.Origins, want) {
+		t.Errorf("expected origins %+v, got %+v", want, sourceMaps.Origins)
+	}
+
+	user := explainOne(t, cfg, "dev.txAdmin.user")
+	if last := user.Origins[len(user.Origins)-1]; last.Kind != OriginConfigFile || last.Source != "opencore.config.ts" {
+		t.Errorf("expected the project config to set dev.txAdmin.user last, got %+v", user.Origins)
+	}
+	port := explainOne(t, cfg, "dev.bridge.port")
+	if len(port.Origins) != 1 || port.Origins[0].Kind != OriginPreset {
+		t.Errorf("expected dev.bridge.port from the preset, got %+v", port.Origins)
+	}
+}
+
+func TestParseConfigValidatesPresets(t *testing.T) {
+	output := strings.Replace(presetConfigOutput, `"sourceMaps": false`, `"sourcemaps": false`, 1)
+	_, err := parseConfig([]byte(output))
+	if err == nil || !strings.Contains(err.Error(), "invalid ./base.config.ts") ||
+		!strings.Contains(err.Error(), `build.sourcemaps: unknown option, did you mean "sourceMaps"?`) {
+		t.Errorf("expected the preset to be named in the validation error, got %v", err)
+	}
+}
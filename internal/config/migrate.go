@@ -0,0 +1,428 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// legacyTxAdminFields maps the flat txAdmin fields of dev to their names in
+// dev.txAdmin.
+var legacyTxAdminFields = map[string]string{
+	"txAdminUrl":      "url",
+	"txAdminUser":     "user",
+	"txAdminPassword": "password",
+}
+
+// Migration is the result of rewriting the legacy fields of
+// opencore.config.ts.
+type Migration struct {
+	Source  []byte   // the rewritten config
+	Changes []string // one line per rewritten field
+	Skipped []string // legacy fields that have to be moved by hand
+}
+
+// Changed reports whether the migration rewrote the config.
+func (m *Migration) Changed() bool {
+	return len(m.Changes) > 0
+}
+
+// pathObject is an object literal and its config path.
+type pathObject struct {
+	path   string
+	object *tsObject
+}
+
+// devObjects returns the dev sections of the config: the root one and the
+// one of every environment override.
+func devObjects(root *tsObject) []pathObject {
+	var objects []pathObject
+	if dev := root.object("dev"); dev != nil {
+		objects = append(objects, pathObject{"dev", dev})
+	}
+	if environments := root.object("build").object("environments"); environments != nil {
+		for _, environment := range environments.props {
+			if dev := environment.value.object("dev"); dev != nil {
+				objects = append(objects, pathObject{"build.environments." + environment.key + ".dev", dev})
+			}
+		}
+	}
+	return objects
+}
+
+// LegacyFields lists the deprecated fields set in the config source by
+// path, such as dev.port or build.target.
+func LegacyFields(source []byte) ([]string, error) {
+	root, err := parseConfigObject(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for _, dev := range devObjects(root) {
+		for _, prop := range dev.object.props {
+			if _, ok := legacyTxAdminFields[prop.key]; ok || prop.key == "port" {
+				fields = append(fields, dev.path+"."+prop.key)
+			}
+		}
+	}
+	if root.object("build").prop("target") != nil {
+		fields = append(fields, "build.target")
+	}
+	return fields, nil
+}
+
+// Migrate rewrites the legacy fields of the config source to their current
+// equivalents: dev.port to dev.bridge.port, dev.txAdminUrl/User/Password to
+// dev.txAdmin and build.target to build.server.target and
+// build.client.target. A legacy field is dropped when its replacement is
+// already set, or, for build.target, when the adapter sets the side's
+// target, so the resolved config does not change. Only the rewritten
+// properties are touched; comments and formatting are kept.
+func (c *Config) Migrate(source []byte) (*Migration, error) {
+	root, err := parseConfigObject(source)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newConfigRewriter(source, root)
+	for _, dev := range devObjects(root) {
+		r.migrateBridgePort(dev)
+		r.migrateTxAdmin(dev)
+	}
+	if build := root.object("build"); build != nil {
+		r.migrateBuildTarget(build, c)
+	}
+
+	r.migration.Source, err = r.apply()
+	if err != nil {
+		return nil, err
+	}
+	return r.migration, nil
+}
+
+// tsEntry is a property written by the migration.
+type tsEntry struct {
+	key, value string
+}
+
+type sourceEdit struct {
+	start, end int
+	text       string
+}
+
+type configRewriter struct {
+	tsScanner
+	edits         []sourceEdit
+	unit          string // one level of indentation
+	newline       string
+	trailingComma bool
+	migration     *Migration
+}
+
+func newConfigRewriter(src []byte, root *tsObject) *configRewriter {
+	r := &configRewriter{tsScanner: tsScanner{src: src}, unit: "  ", newline: "\n", migration: &Migration{}}
+	if bytes.Contains(src, []byte("\r\n")) {
+		r.newline = "\r\n"
+	}
+	if len(root.props) > 0 {
+		first := root.props[0]
+		if inner, outer := r.indentAt(first.start), r.indentAt(root.open); r.multiline(root) && len(inner) > len(outer) {
+			r.unit = inner[len(outer):]
+		}
+		last := root.props[len(root.props)-1]
+		if next := r.skipTrivia(last.end); next < len(src) && src[next] == ',' {
+			r.trailingComma = true
+		}
+	}
+	return r
+}
+
+func (r *configRewriter) migrateBridgePort(dev pathObject) {
+	legacy := dev.object.prop("port")
+	if legacy == nil {
+		return
+	}
+
+	from, to := dev.path+".port", dev.path+".bridge.port"
+	bridge := dev.object.prop("bridge")
+	switch {
+	case bridge == nil:
+		r.replace(legacy, dev.object, []tsEntry{{"bridge", r.block(dev.object, legacy.start, []tsEntry{{"port", r.value(legacy)}})}})
+		r.moved(from, to)
+	case bridge.value == nil:
+		r.skipped(from, dev.path+".bridge is not an object literal")
+	case bridge.value.prop("port") != nil:
+		r.remove(legacy, dev.object)
+		r.dropped(from, to+" is already set")
+	default:
+		r.insert(bridge.value, []tsEntry{{"port", r.value(legacy)}})
+		r.remove(legacy, dev.object)
+		r.moved(from, to)
+	}
+}
+
+func (r *configRewriter) migrateTxAdmin(dev pathObject) {
+	var legacy []*tsProperty
+	for _, prop := range dev.object.props {
+		if _, ok := legacyTxAdminFields[prop.key]; ok && dev.object.prop(prop.key) == prop {
+			legacy = append(legacy, prop)
+		}
+	}
+	if len(legacy) == 0 {
+		return
+	}
+
+	txAdmin := dev.object.prop("txAdmin")
+	if txAdmin != nil && txAdmin.value == nil {
+		for _, prop := range legacy {
+			r.skipped(dev.path+"."+prop.key, dev.path+".txAdmin is not an object literal")
+		}
+		return
+	}
+
+	var entries []tsEntry
+	for _, prop := range legacy {
+		field := legacyTxAdminFields[prop.key]
+		from, to := dev.path+"."+prop.key, dev.path+".txAdmin."+field
+		if txAdmin != nil && txAdmin.value.prop(field) != nil {
+			r.dropped(from, to+" is already set")
+			continue
+		}
+		entries = append(entries, tsEntry{field, r.value(prop)})
+		r.moved(from, to)
+	}
+
+	if txAdmin == nil {
+		// The new section takes the place of the first legacy field.
+		r.replace(legacy[0], dev.object, []tsEntry{{"txAdmin", r.block(dev.object, legacy[0].start, entries)}})
+		legacy = legacy[1:]
+	} else if len(entries) > 0 {
+		r.insert(txAdmin.value, entries)
+	}
+	for _, prop := range legacy {
+		r.remove(prop, dev.object)
+	}
+}
+
+func (r *configRewriter) migrateBuildTarget(build *tsObject, c *Config) {
+	legacy := build.prop("target")
+	if legacy == nil {
+		return
+	}
+
+	var replacement []tsEntry
+	var inserts []*tsObject
+	var changes []func()
+	for _, side := range []string{"server", "client"} {
+		to := "build." + side + ".target"
+		prop := build.prop(side)
+		switch {
+		case c.adapterSideTarget(side) != "":
+			changes = append(changes, func() { r.dropped("build.target", "the adapter sets "+to) })
+		case prop == nil:
+			replacement = append(replacement, tsEntry{side, r.block(build, legacy.start, []tsEntry{{"target", r.value(legacy)}})})
+			changes = append(changes, func() { r.moved("build.target", to) })
+		case prop.value == nil:
+			r.skipped("build.target", "build."+side+" is not an object literal")
+			return
+		case prop.value.prop("target") != nil:
+			changes = append(changes, func() { r.dropped("build.target", to+" is already set") })
+		default:
+			inserts = append(inserts, prop.value)
+			changes = append(changes, func() { r.moved("build.target", to) })
+		}
+	}
+
+	for _, object := range inserts {
+		r.insert(object, []tsEntry{{"target", r.value(legacy)}})
+	}
+	if len(replacement) > 0 {
+		r.replace(legacy, build, replacement)
+	} else {
+		r.remove(legacy, build)
+	}
+	for _, change := range changes {
+		change()
+	}
+}
+
+func (r *configRewriter) moved(from, to string) {
+	r.migration.Changes = append(r.migration.Changes, fmt.Sprintf("%s → %s", from, to))
+}
+
+func (r *configRewriter) dropped(from, reason string) {
+	r.migration.Changes = append(r.migration.Changes, fmt.Sprintf("%s removed, %s", from, reason))
+}
+
+func (r *configRewriter) skipped(from, reason string) {
+	r.migration.Skipped = append(r.migration.Skipped, fmt.Sprintf("%s: %s", from, reason))
+}
+
+// value returns the source of a property value as written.
+func (r *configRewriter) value(prop *tsProperty) string {
+	return string(r.src[prop.valueStart:prop.end])
+}
+
+// block renders an object literal for a property of container that starts
+// at offset at, on several lines when container spans several lines.
+func (r *configRewriter) block(container *tsObject, at int, entries []tsEntry) string {
+	if !r.multiline(container) {
+		return "{ " + joinEntries(entries, ", ") + " }"
+	}
+	indent := r.indentAt(at)
+	var b strings.Builder
+	b.WriteString("{")
+	for i, entry := range entries {
+		b.WriteString(r.newline + indent + r.unit + entry.key + ": " + entry.value)
+		if i < len(entries)-1 || r.trailingComma {
+			b.WriteString(",")
+		}
+	}
+	b.WriteString(r.newline + indent + "}")
+	return b.String()
+}
+
+// replace writes entries in place of prop.
+func (r *configRewriter) replace(prop *tsProperty, container *tsObject, entries []tsEntry) {
+	separator := ", "
+	if r.multiline(container) {
+		separator = "," + r.newline + r.indentAt(prop.start)
+	}
+	r.edit(prop.start, prop.end, joinEntries(entries, separator))
+}
+
+// insert adds entries after the last property of object.
+func (r *configRewriter) insert(object *tsObject, entries []tsEntry) {
+	if len(object.props) == 0 {
+		r.edit(object.open, object.close+1, r.block(object, object.open, entries))
+		return
+	}
+
+	last := object.props[len(object.props)-1]
+	lineEnd, ok := r.lineEnd(last.end)
+	if !r.multiline(object) || !ok {
+		r.edit(last.end, last.end, ", "+joinEntries(entries, ", "))
+		return
+	}
+
+	next := r.skipTrivia(last.end)
+	comma := next < len(r.src) && r.src[next] == ','
+	if !comma {
+		r.edit(last.end, last.end, ",")
+	}
+	indent := r.indentAt(last.start)
+	var b strings.Builder
+	for i, entry := range entries {
+		b.WriteString(r.newline + indent + entry.key + ": " + entry.value)
+		if i < len(entries)-1 || comma {
+			b.WriteString(",")
+		}
+	}
+	r.edit(lineEnd, lineEnd, b.String())
+}
+
+// remove deletes prop, with its line when it has a line of its own.
+func (r *configRewriter) remove(prop *tsProperty, container *tsObject) {
+	start := r.lineStart(prop.start)
+	if r.multiline(container) && strings.TrimSpace(string(r.src[start:prop.start])) == "" {
+		if end, ok := r.lineEnd(prop.end); ok {
+			if end < len(r.src) {
+				end += len(r.newline)
+			}
+			r.edit(start, end, "")
+			return
+		}
+	}
+
+	if next := r.skipTrivia(prop.end); next < len(r.src) && r.src[next] == ',' {
+		r.edit(prop.start, r.skipTrivia(next+1), "")
+		return
+	}
+	previous := prop.start
+	for previous > 0 && strings.ContainsRune(" \t\r\n", rune(r.src[previous-1])) {
+		previous--
+	}
+	if previous > 0 && r.src[previous-1] == ',' {
+		r.edit(previous-1, prop.end, "")
+		return
+	}
+	r.edit(prop.start, prop.end, "")
+}
+
+func (r *configRewriter) edit(start, end int, text string) {
+	r.edits = append(r.edits, sourceEdit{start, end, text})
+}
+
+// apply returns the source with the edits made. Removals that overlap,
+// such as two neighbouring properties on one line, are merged.
+func (r *configRewriter) apply() ([]byte, error) {
+	sort.SliceStable(r.edits, func(i, j int) bool { return r.edits[i].start < r.edits[j].start })
+	var out bytes.Buffer
+	offset := 0
+	for _, edit := range r.edits {
+		if edit.start < offset {
+			if edit.text != "" {
+				return nil, fmt.Errorf("overlapping rewrites at %s", r.position(edit.start))
+			}
+			offset = max(offset, edit.end)
+			continue
+		}
+		out.Write(r.src[offset:edit.start])
+		out.WriteString(edit.text)
+		offset = edit.end
+	}
+	out.Write(r.src[offset:])
+	return out.Bytes(), nil
+}
+
+func (r *configRewriter) multiline(object *tsObject) bool {
+	return bytes.IndexByte(r.src[object.open:object.close], '\n') >= 0
+}
+
+func (r *configRewriter) lineStart(i int) int {
+	return bytes.LastIndexByte(r.src[:i], '\n') + 1
+}
+
+// indentAt returns the indentation of the line containing offset i.
+func (r *configRewriter) indentAt(i int) string {
+	start := r.lineStart(i)
+	end := start
+	for end < len(r.src) && (r.src[end] == ' ' || r.src[end] == '\t') {
+		end++
+	}
+	return string(r.src[start:end])
+}
+
+// lineEnd returns the offset of the line break after i when only a comma
+// and a line comment follow i on its line.
+func (r *configRewriter) lineEnd(i int) (int, bool) {
+	skipSpaces := func() {
+		for i < len(r.src) && (r.src[i] == ' ' || r.src[i] == '\t') {
+			i++
+		}
+	}
+	skipSpaces()
+	if i < len(r.src) && r.src[i] == ',' {
+		i++
+		skipSpaces()
+	}
+	if bytes.HasPrefix(r.src[i:], []byte("//")) {
+		for i < len(r.src) && r.src[i] != '\n' && r.src[i] != '\r' {
+			i++
+		}
+	}
+	if i == len(r.src) || r.src[i] == '\n' || r.src[i] == '\r' {
+		return i, true
+	}
+	return 0, false
+}
+
+func joinEntries(entries []tsEntry, separator string) string {
+	parts := make([]string, len(entries))
+	for i, entry := range entries {
+		parts[i] = entry.key + ": " + entry.value
+	}
+	return strings.Join(parts, separator)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const legacyConfigSource = `import { defineConfig } from '@open-core/cli'
+
+export default defineConfig({
+  name: 'demo',
+  // Shared by both sides.
+  build: {
+    minify: true,
+    target: 'es2021', // keep in sync with the server
+    client: {
+      format: 'iife',
+    },
+  },
+  dev: {
+    port: 4000,
+    /* txAdmin on the test box */
+    txAdminUrl: process.env.TXADMIN_URL ?? 'http://localhost:40120',
+    txAdminUser: 'admin',
+  },
+})
+`
+
+func TestMigrateRewritesLegacyFields(t *testing.T) {
+	migration, err := (&Config{}).Migrate([]byte(legacyConfigSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `import { defineConfig } from '@open-core/cli'
+
+export default defineConfig({
+  name: 'demo',
+  // Shared by both sides.
+  build: {
+    minify: true,
+    server: {
+      target: 'es2021',
+    }, // keep in sync with the server
+    client: {
+      format: 'iife',
+      target: 'es2021',
+    },
+  },
+  dev: {
+    bridge: {
+      port: 4000,
+    },
+    /* txAdmin on the test box */
+    txAdmin: {
+      url: process.env.TXADMIN_URL ?? 'http://localhost:40120',
+      user: 'admin',
+    },
+  },
+})
+`
+	if got := string(migration.Source); got != want {
+		t.Errorf("unexpected migration:\n%s", got)
+	}
+
+	wantChanges := []string{
+		"dev.port → dev.bridge.port",
+		"dev.txAdminUrl → dev.txAdmin.url",
+		"dev.txAdminUser → dev.txAdmin.user",
+		"build.target → build.server.target",
+		"build.target → build.client.target",
+	}
+	if !reflect.DeepEqual(migration.Changes, wantChanges) {
+		t.Errorf("expected changes %v, got %v", wantChanges, migration.Changes)
+	}
+
+	fields, err := LegacyFields(migration.Source)
+	if err != nil || len(fields) != 0 {
+		t.Errorf("expected no legacy fields after migrating, got %v %v", fields, err)
+	}
+}
+
+func TestMigrateKeepsCurrentFields(t *testing.T) {
+	source := `export default {
+  build: { target: 'es2021', server: { target: 'node22' } },
+  dev: { port: 4000, bridge: { port: 5000 }, txAdminPassword: 'secret', txAdmin: { url: 'http://tx' } },
+}
+`
+	cfg := &Config{Adapter: &AdapterConfig{Client: &AdapterBinding{Runtime: &AdapterRuntimeBinding{
+		Client: &AdapterRuntimeSideHints{Target: "es2020"},
+	}}}}
+	migration, err := cfg.Migrate([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `export default {
+  build: { server: { target: 'node22' } },
+  dev: { bridge: { port: 5000 }, txAdmin: { url: 'http://tx', password: 'secret' } },
+}
+`
+	if got := string(migration.Source); got != want {
+		t.Errorf("unexpected migration:\n%s", got)
+	}
+	for _, change := range []string{
+		"dev.port removed, dev.bridge.port is already set",
+		"build.target removed, build.server.target is already set",
+		"build.target removed, the adapter sets build.client.target",
+	} {
+		if !containsString(migration.Changes, change) {
+			t.Errorf("expected change %q in %v", change, migration.Changes)
+		}
+	}
+}
+
+func TestMigrateEnvironmentDevAndSkips(t *testing.T) {
+	source := `const shared = { target: 'node16' }
+
+export default defineConfig({
+  build: {
+    target: 'es2021',
+    server: shared,
+    environments: {
+      staging: { dev: { port: 4100 } },
+    },
+  },
+})
+`
+	migration, err := (&Config{}).Migrate([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(migration.Source), "staging: { dev: { bridge: { port: 4100 } } }") {
+		t.Errorf("expected the environment dev port to move:\n%s", migration.Source)
+	}
+	if !strings.Contains(string(migration.Source), "target: 'es2021',") {
+		t.Errorf("expected build.target to stay when a side is not a literal:\n%s", migration.Source)
+	}
+	if len(migration.Skipped) != 1 || !strings.Contains(migration.Skipped[0], "build.server is not an object literal") {
+		t.Errorf("expected build.target to be skipped, got %v", migration.Skipped)
+	}
+}
+
+func TestLegacyFields(t *testing.T) {
+	fields, err := LegacyFields([]byte(legacyConfigSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"dev.port", "dev.txAdminUrl", "dev.txAdminUser", "build.target"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected %v, got %v", want, fields)
+	}
+
+	if _, err := LegacyFields([]byte("module.exports = config")); err == nil {
+		t.Error("expected an error without a config object")
+	}
+}
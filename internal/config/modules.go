@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ModuleImportPrefix is how resources import the shared modules listed in
+// modules: a module in modules/inventory-types is @modules/inventory-types.
+const ModuleImportPrefix = "@modules/"
+
+// SharedModule is a directory of TypeScript code shared by resources. It is
+// bundled into every resource that imports it.
+type SharedModule struct {
+	Name string // import name after ModuleImportPrefix
+	Path string // directory relative to the project root
+}
+
+// SharedModules returns the modules listed in the config, named after their
+// directories.
+func (c *Config) SharedModules() ([]SharedModule, error) {
+	modules := make([]SharedModule, 0, len(c.Modules))
+	seen := make(map[string]string, len(c.Modules))
+	for _, entry := range c.Modules {
+		path := normalizedConfigPath(entry)
+		if path == "" || path == "." || filepath.IsAbs(path) {
+			return nil, fmt.Errorf("modules: %q must be a directory relative to the project root", entry)
+		}
+		name := filepath.Base(path)
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("modules: %q and %q would both be imported as %s%s", other, entry, ModuleImportPrefix, name)
+		}
+		seen[name] = entry
+		modules = append(modules, SharedModule{Name: name, Path: path})
+	}
+	return modules, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSharedModules(t *testing.T) {
+	cfg := &Config{Modules: []string{"./modules/inventory-types", "shared/items/"}}
+	modules, err := cfg.SharedModules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SharedModule{
+		{Name: "inventory-types", Path: filepath.Join("modules", "inventory-types")},
+		{Name: "items", Path: filepath.Join("shared", "items")},
+	}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("expected %v, got %v", want, modules)
+	}
+
+	for _, entries := range [][]string{
+		{"./modules/types", "./shared/types"},
+		{"."},
+		{""},
+	} {
+		cfg := &Config{Modules: entries}
+		if _, err := cfg.SharedModules(); err == nil {
+			t.Errorf("expected %q to be rejected", entries)
+		}
+	}
+}
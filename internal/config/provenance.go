@@ -0,0 +1,267 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of origin a resolved config value can have.
+const (
+	OriginDefault      = "default"
+	OriginPreset       = "preset"
+	OriginConfigFile   = "config file"
+	OriginResourceFile = "resource file"
+	OriginAdapter      = "adapter hint"
+	OriginLegacy       = "legacy field"
+	OriginEnvironment  = "environment override"
+	OriginEnvVar       = "env var"
+	OriginFlag         = "flag"
+)
+
+// redactedValue replaces secrets in printed and explained configs.
+const redactedValue = "********"
+
+// secretPaths are the config paths whose values are never printed. The same
+// dev fields are secret in every environment override.
+var secretPaths = map[string]bool{
+	"dev.txAdmin.password": true,
+	"dev.txAdminPassword":  true,
+}
+
+// isSecretPath reports whether the value at path is never printed.
+func isSecretPath(path string) bool {
+	if secretPaths[path] {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(path, "build.environments."); ok {
+		if _, field, ok := strings.Cut(rest, "."); ok {
+			return secretPaths[field]
+		}
+	}
+	return false
+}
+
+// Origin is one step that set a resolved config value.
+type Origin struct {
+	Kind string `json:"kind"`
+	// Source names what set the value, e.g. the env var, the adapter or the
+	// environment override.
+	Source string `json:"source,omitempty"`
+	Value  any    `json:"value"`
+}
+
+// Explanation is a resolved config value and the steps that set it, in the
+// order they were applied. The last origin is the one in effect.
+type Explanation struct {
+	Path    string   `json:"path"`
+	Value   any      `json:"value"`
+	Origins []Origin `json:"origins"`
+}
+
+// record notes that a step set the value at path.
+func (c *Config) record(path, kind, source string, value any) {
+	if c.origins == nil {
+		c.origins = make(map[string][]Origin)
+	}
+	if isSecretPath(path) {
+		value = redact(value)
+	}
+	c.origins[path] = append(c.origins[path], Origin{Kind: kind, Source: source, Value: value})
+}
+
+// recordOverride records the values an environment override sets.
+func (c *Config) recordOverride(override EnvironmentOverride) {
+	data, err := json.Marshal(override)
+	if err != nil {
+		return
+	}
+	env := c.Build.Environment
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return
+	}
+	for path, value := range flatten("", tree) {
+		source := "build.environments." + env + "." + path
+		head, _, _ := strings.Cut(path, ".")
+		switch head {
+		case "minify", "sourceMaps", "logLevel", "server", "client", "dependencyResolution":
+			c.record("build."+path, OriginEnvironment, source, value)
+		case "dev":
+			c.record(path, OriginEnvironment, source, value)
+		case "outDir":
+			c.record("outDir", OriginEnvironment, source, c.OutDir)
+		case "destination":
+			c.record("destination", OriginEnvironment, source, c.Destination)
+		}
+	}
+}
+
+// normalizeDev normalizes the dev settings and records the values filled
+// in from defaults and legacy fields.
+func (c *Config) normalizeDev() {
+	before := c.Dev
+	c.Dev.Normalize()
+	after := c.Dev
+
+	if before.Bridge.Port != after.Bridge.Port {
+		if before.Port != 0 {
+			c.record("dev.bridge.port", OriginLegacy, "dev.port", after.Bridge.Port)
+		} else {
+			c.record("dev.bridge.port", OriginDefault, "", after.Bridge.Port)
+		}
+	}
+	if before.TxAdmin.URL != after.TxAdmin.URL {
+		c.record("dev.txAdmin.url", OriginLegacy, "dev.txAdminUrl", after.TxAdmin.URL)
+	}
+	if before.TxAdmin.User != after.TxAdmin.User {
+		c.record("dev.txAdmin.user", OriginLegacy, "dev.txAdminUser", after.TxAdmin.User)
+	}
+	if before.TxAdmin.Password != after.TxAdmin.Password {
+		c.record("dev.txAdmin.password", OriginLegacy, "dev.txAdminPassword", after.TxAdmin.Password)
+	}
+	if before.Process.StopTimeoutMs != after.Process.StopTimeoutMs {
+		c.record("dev.process.stopTimeoutMs", OriginDefault, "", after.Process.StopTimeoutMs)
+	}
+	if before.Process.StopSignal != after.Process.StopSignal {
+		c.record("dev.process.stopSignal", OriginDefault, "", after.Process.StopSignal)
+	}
+}
+
+// Redacted returns a copy of the configuration safe to print: secrets such
+// as the txAdmin password are masked.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redactDev(&redacted.Dev)
+	if len(c.Build.Environments) > 0 {
+		redacted.Build.Environments = make(map[string]EnvironmentOverride, len(c.Build.Environments))
+		for name, override := range c.Build.Environments {
+			if override.Dev != nil {
+				dev := *override.Dev
+				redactDev(&dev)
+				override.Dev = &dev
+			}
+			redacted.Build.Environments[name] = override
+		}
+	}
+	return &redacted
+}
+
+func redactDev(dev *DevConfig) {
+	if dev.TxAdmin.Password != "" {
+		dev.TxAdmin.Password = redactedValue
+	}
+	if dev.TxAdminPassword != "" {
+		dev.TxAdminPassword = redactedValue
+	}
+}
+
+// Explain returns every resolved value at or below path, a dotted list of
+// JSON field names such as "build.server.target", with where it came from.
+// Values no step set keep their zero value and are reported as defaults.
+func (c *Config) Explain(path string) ([]Explanation, error) {
+	data, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	path = strings.Trim(strings.TrimSpace(path), ".")
+	node, ok := lookupPath(tree, path)
+	if !ok {
+		return nil, fmt.Errorf("unknown config path %q", path)
+	}
+
+	values := flatten(path, node)
+	if len(values) == 0 {
+		values = map[string]any{path: node}
+	}
+	paths := make([]string, 0, len(values))
+	for p := range values {
+		paths = append(paths, p)
+	}
+	// Values that are unset in the resolved config may still have a history,
+	// e.g. a destination an environment turned off.
+	for p := range c.origins {
+		if _, ok := values[p]; !ok && (path == "" || p == path || strings.HasPrefix(p, path+".")) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	explanations := make([]Explanation, 0, len(paths))
+	for _, p := range paths {
+		origins := c.origins[p]
+		if len(origins) == 0 {
+			origins = []Origin{{Kind: OriginDefault, Value: values[p]}}
+		}
+		explanations = append(explanations, Explanation{Path: p, Value: values[p], Origins: origins})
+	}
+	return explanations, nil
+}
+
+// lookupPath returns the node of a decoded JSON document at a dotted path.
+// Array elements are addressed by index.
+func lookupPath(tree any, path string) (any, bool) {
+	if path == "" {
+		return tree, true
+	}
+	node := tree
+	for _, key := range strings.Split(path, ".") {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[key]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// flatten returns the leaves of a decoded JSON document by dotted path.
+// Arrays are leaves, so lists such as build.server.external are explained
+// as a whole.
+func flatten(prefix string, node any) map[string]any {
+	leaves := make(map[string]any)
+	var walk func(path string, node any)
+	walk = func(path string, node any) {
+		object, ok := node.(map[string]any)
+		if !ok {
+			if path != "" {
+				leaves[path] = node
+			}
+			return
+		}
+		for key, child := range object {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			walk(childPath, child)
+		}
+	}
+	walk(prefix, node)
+	return leaves
+}
+
+func redact(value any) any {
+	if s, ok := value.(string); ok && s == "" {
+		return value
+	}
+	return redactedValue
+}
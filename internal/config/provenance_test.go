@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const provenanceConfig = `{
+	"name": "demo",
+	"core": {"path": "./core", "resourceName": "core"},
+	"adapter": {"server": {"name": "fivem", "valid": true, "runtime": {"runtime": "fivem", "server": {"target": "node16"}}}},
+	"build": {
+		"target": "es2021",
+		"environments": {"production": {"minify": true, "server": {"target": "node22"}}}
+	},
+	"dev": {"port": 4000, "txAdminUrl": "http://legacy:40120", "txAdminPassword": "hunter2"}
+}`
+
+func explainOne(t *testing.T, cfg *Config, path string) Explanation {
+	t.Helper()
+	explanations, err := cfg.Explain(path)
+	if err != nil {
+		t.Fatalf("Explain(%s) failed: %v", path, err)
+	}
+	for _, explanation := range explanations {
+		if explanation.Path == path {
+			return explanation
+		}
+	}
+	t.Fatalf("no explanation for %s in %+v", path, explanations)
+	return Explanation{}
+}
+
+func kinds(origins []Origin) string {
+	names := make([]string, len(origins))
+	for i, origin := range origins {
+		names[i] = origin.Kind
+	}
+	return strings.Join(names, " > ")
+}
+
+func TestExplainRecordsOrigins(t *testing.T) {
+	t.Setenv("OPENCORE_ENVIRONMENT", "")
+	t.Setenv("OPENCORE_TXADMIN_USER", "ops")
+	cfg, err := parseConfig([]byte(provenanceConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.UseEnvironment("production", "--env")
+	if err := cfg.ApplyEnvironment(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"name":                 OriginConfigFile,
+		"outDir":               OriginDefault + " > " + OriginDefault,
+		"build.server.target":  OriginAdapter + " > " + OriginEnvironment,
+		"build.client.target":  OriginLegacy,
+		"build.minify":         OriginEnvironment,
+		"build.logLevel":       OriginDefault,
+		"build.environment":    OriginDefault + " > " + OriginFlag,
+		"dev.bridge.port":      OriginLegacy,
+		"dev.txAdmin.url":      OriginLegacy,
+		"dev.txAdmin.user":     OriginEnvVar,
+		"build.sourceMaps":     OriginDefault,
+		"adapter.server.name":  OriginAdapter,
+		"dev.txAdmin.password": OriginLegacy,
+	}
+	for path, want := range cases {
+		if got := kinds(explainOne(t, cfg, path).Origins); got != want {
+			t.Errorf("%s: expected origins %q, got %q", path, want, got)
+		}
+	}
+
+	target := explainOne(t, cfg, "build.server.target")
+	if target.Value != "node22" || target.Origins[1].Source != "build.environments.production.server.target" {
+		t.Errorf("expected the environment override to be in effect, got %+v", target)
+	}
+	if outDir := explainOne(t, cfg, "outDir"); outDir.Value != filepath.Join("build", "[demo]") {
+		t.Errorf("expected the category folder, got %v", outDir.Value)
+	}
+}
+
+func TestExplainRedactsSecrets(t *testing.T) {
+	cfg, err := parseConfig([]byte(provenanceConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	explanations, err := cfg.Explain("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(explanations)
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("expected the txAdmin password to be redacted:\n%s", data)
+	}
+	if cfg.Dev.TxAdmin.Password != "hunter2" || cfg.Redacted().Dev.TxAdmin.Password != redactedValue {
+		t.Error("expected Redacted to mask a copy and keep the config intact")
+	}
+}
+
+func TestRedactedMasksEnvironmentSecrets(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{
+  "name": "demo",
+  "core": {"path": "./core"},
+  "resources": {"include": []},
+  "build": {"environments": {"production": {"dev": {"txAdmin": {"password": "hunter2"}, "txAdminPassword": "swordfish"}}}}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(cfg.Redacted())
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "swordfish") {
+		t.Errorf("expected the environment txAdmin passwords to be redacted:\n%s", data)
+	}
+	explanations, err := cfg.Explain("build.environments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = json.Marshal(explanations)
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "swordfish") {
+		t.Errorf("expected explain to redact the environment txAdmin passwords:\n%s", data)
+	}
+	if dev := cfg.Build.Environments["production"].Dev; dev.TxAdmin.Password != "hunter2" || dev.TxAdminPassword != "swordfish" {
+		t.Error("expected Redacted to mask a copy and keep the environments intact")
+	}
+}
+
+func TestExplainUnknownPath(t *testing.T) {
+	cfg, err := parseConfig([]byte(provenanceConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Explain("build.nope"); err == nil || !strings.Contains(err.Error(), "unknown config path") {
+		t.Errorf("expected an unknown path error, got %v", err)
+	}
+	explanations, err := cfg.Explain("build.server")
+	if err != nil || len(explanations) == 0 {
+		t.Fatalf("expected the values below build.server, got %v %v", explanations, err)
+	}
+	for _, explanation := range explanations {
+		if !strings.HasPrefix(explanation.Path, "build.server.") {
+			t.Errorf("unexpected path %s", explanation.Path)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Resource config files colocated in a resource directory. They hold the
+// options of a resources.explicit or standalones.explicit entry for that
+// directory, so a resource found through include globs can carry its own
+// overrides.
+const (
+	ResourceFileTS   = "opencore.resource.ts"
+	ResourceFileJSON = "opencore.resource.json"
+)
+
+// resourceFile is a colocated resource config.
+type resourceFile struct {
+	// section is resources or standalones.
+	section string
+	// dir is the resource directory relative to the project, e.g.
+	// ./resources/chat.
+	dir string
+	// file is the absolute path of the config file.
+	file string
+}
+
+// name is how errors and origins refer to the file.
+func (f resourceFile) name() string {
+	return f.dir + "/" + filepath.Base(f.file)
+}
+
+// IsResourceFile reports whether path is a colocated resource config.
+func IsResourceFile(path string) bool {
+	base := filepath.Base(path)
+	return base == ResourceFileTS || base == ResourceFileJSON
+}
+
+// loadResourceFiles merges the resource configs colocated in the resource
+// directories of the project at root into the explicit resources. An entry
+// for the same directory in opencore.config.ts is merged over the file.
+func (c *Config) loadResourceFiles(root string) error {
+	files, err := c.findResourceFiles(root)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	var scripts []string
+	for _, file := range files {
+		if filepath.Base(file.file) == ResourceFileTS {
+			scripts = append(scripts, file.file)
+		}
+	}
+	transpiled := make(map[string]map[string]any)
+	if len(scripts) > 0 {
+		output, err := runConfigLoader(root, append([]string{"--resources"}, scripts...)...)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(output, &transpiled); err != nil {
+			return fmt.Errorf("failed to parse resource configs: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	for _, file := range files {
+		tree, ok := transpiled[file.file]
+		if !ok {
+			data, err := os.ReadFile(file.file)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file.name(), err)
+			}
+			if err := json.Unmarshal(data, &tree); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", file.name(), err)
+			}
+		}
+		if err := c.applyResourceFile(file, tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findResourceFiles returns the resource configs in the directories matched
+// by resources.include and standalones.include and in the explicit
+// resources, in that order.
+func (c *Config) findResourceFiles(root string) ([]resourceFile, error) {
+	type section struct {
+		name     string
+		include  []string
+		explicit []ExplicitResource
+	}
+	sections := []section{{"resources", c.Resources.Include, c.Resources.Explicit}}
+	if c.Standalones != nil {
+		sections = append(sections, section{"standalones", c.Standalones.Include, c.Standalones.Explicit})
+	}
+
+	var files []resourceFile
+	seen := make(map[string]bool)
+	for _, s := range sections {
+		var dirs []string
+		for _, pattern := range s.include {
+			matches, err := filepath.Glob(filepath.Join(root, normalizedConfigPath(pattern)))
+			if err != nil {
+				continue
+			}
+			dirs = append(dirs, matches...)
+		}
+		for _, res := range s.explicit {
+			dirs = append(dirs, filepath.Join(root, normalizedConfigPath(res.Path)))
+		}
+
+		for _, dir := range dirs {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil || seen[rel] || rel == normalizedConfigPath(c.Core.Path) {
+				continue
+			}
+			seen[rel] = true
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+
+			var found []string
+			for _, name := range []string{ResourceFileTS, ResourceFileJSON} {
+				if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+					found = append(found, filepath.Join(dir, name))
+				}
+			}
+			if len(found) == 0 {
+				continue
+			}
+			file := resourceFile{section: s.name, dir: "./" + filepath.ToSlash(rel), file: found[0]}
+			if len(found) > 1 {
+				return nil, fmt.Errorf("%s has both %s and %s; keep one", file.dir, ResourceFileTS, ResourceFileJSON)
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// applyResourceFile merges a resource config into the explicit resource for
+// its directory, adding one when opencore.config.ts has none.
+func (c *Config) applyResourceFile(file resourceFile, tree map[string]any) error {
+	if _, ok := tree["path"]; ok {
+		return fmt.Errorf("invalid %s:\n  path: is the directory of the file and cannot be set", file.name())
+	}
+	var issues []string
+	validateValue("", tree, reflect.TypeOf(ExplicitResource{}), &issues)
+	if len(issues) > 0 {
+		return fmt.Errorf("invalid %s:\n  %s", file.name(), strings.Join(issues, "\n  "))
+	}
+	rebaseResourcePaths(tree, file.dir)
+	tree["path"] = file.dir
+
+	entries := &c.Resources.Explicit
+	if file.section == "standalones" {
+		entries = &c.Standalones.Explicit
+	}
+	index := -1
+	for i, res := range *entries {
+		if normalizedConfigPath(res.Path) == normalizedConfigPath(file.dir) {
+			index = i
+			break
+		}
+	}
+
+	merged := any(tree)
+	if index >= 0 {
+		data, err := json.Marshal((*entries)[index])
+		if err != nil {
+			return err
+		}
+		var explicit map[string]any
+		if err := json.Unmarshal(data, &explicit); err != nil {
+			return err
+		}
+		merged = mergeValue(file.section+".explicit[]", tree, explicit)
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	var entry ExplicitResource
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file.name(), err)
+	}
+	if index >= 0 {
+		(*entries)[index] = entry
+	} else {
+		*entries = append(*entries, entry)
+	}
+
+	if data, err := json.Marshal(*entries); err == nil {
+		var list any
+		if err := json.Unmarshal(data, &list); err == nil {
+			c.record(file.section+".explicit", OriginResourceFile, file.name(), list)
+		}
+	}
+	return nil
+}
+
+// rebaseResourcePaths makes the paths of a resource config, which are
+// relative to its directory, relative to the project like those of
+// opencore.config.ts.
+func rebaseResourcePaths(tree map[string]any, dir string) {
+	rebase := func(object map[string]any, key string) {
+		p, ok := object[key].(string)
+		if !ok || strings.TrimSpace(p) == "" || filepath.IsAbs(p) {
+			return
+		}
+		object[key] = "./" + filepath.ToSlash(filepath.Join(normalizedConfigPath(dir), normalizedConfigPath(p)))
+	}
+
+	rebase(tree, "customCompiler")
+	if views, ok := tree["views"].(map[string]any); ok {
+		rebase(views, "path")
+	}
+	if entryPoints, ok := tree["entryPoints"].(map[string]any); ok {
+		rebase(entryPoints, "server")
+		rebase(entryPoints, "client")
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeResourceFile(t *testing.T, root, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if name == "" {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(root, dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadResourceFiles(t *testing.T) {
+	root := t.TempDir()
+	writeResourceFile(t, root, "resources/chat", ResourceFileJSON, `{
+  "customCompiler": "./compile.js",
+  "views": {"path": "./ui", "framework": "vite"},
+  "build": {"client": false, "server": {"external": ["pg"]}, "logLevel": "DEBUG"}
+}`)
+	writeResourceFile(t, root, "resources/shop", ResourceFileJSON, `{"resourceName": "shop-core", "build": {"minify": false}}`)
+	writeResourceFile(t, root, "resources/plain", "", "")
+	writeResourceFile(t, root, "standalone/radio", ResourceFileJSON, `{"compile": false}`)
+
+	cfg, err := parseConfig([]byte(`{
+  "name": "demo",
+  "core": {"path": "./core"},
+  "resources": {
+    "include": ["./resources/*"],
+    "explicit": [{"path": "resources/chat", "build": {"logLevel": "WARN"}}]
+  },
+  "standalones": {"include": ["./standalone/*"]}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.loadResourceFiles(root); err != nil {
+		t.Fatal(err)
+	}
+
+	chat := cfg.GetExplicitResource("./resources/chat")
+	if chat == nil || chat.Path != "resources/chat" {
+		t.Fatalf("expected the chat entry to keep its path, got %+v", chat)
+	}
+	if chat.Build.LogLevel != "WARN" {
+		t.Errorf("expected opencore.config.ts to win, got log level %q", chat.Build.LogLevel)
+	}
+	if chat.Build.Client == nil || chat.Build.Client.Enabled || chat.Build.Server.Options.External[0] != "pg" {
+		t.Errorf("expected the build sides from the resource file, got %+v", chat.Build)
+	}
+	if got := cfg.GetCustomCompiler("resources/chat"); got != "./resources/chat/compile.js" {
+		t.Errorf("expected the compiler relative to the resource, got %q", got)
+	}
+	if views := cfg.GetResourceViews("resources/chat"); views == nil || views.Path != "./resources/chat/ui" {
+		t.Errorf("expected the views relative to the resource, got %+v", views)
+	}
+
+	shop := cfg.GetExplicitResource("resources/shop")
+	if shop == nil || shop.ResourceName != "shop-core" || shop.Path != "./resources/shop" {
+		t.Errorf("expected a new entry for shop, got %+v", shop)
+	}
+	if cfg.GetExplicitResource("resources/plain") != nil {
+		t.Error("expected no entry for a resource without a resource file")
+	}
+	if cfg.ShouldCompile("standalone/radio") {
+		t.Error("expected the standalone resource file to turn off compilation")
+	}
+	t.Chdir(root)
+	if paths := cfg.GetResourcePaths(); len(paths) != 4 {
+		t.Errorf("expected core and three resources once each, got %v", paths)
+	}
+
+	explanations, err := cfg.Explain("resources.explicit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origins := explanations[0].Origins
+	if last := origins[len(origins)-1]; last.Kind != OriginResourceFile || last.Source != "./resources/shop/opencore.resource.json" {
+		t.Errorf("expected the last resource file as origin, got %+v", origins)
+	}
+}
+
+func TestLoadResourceFilesErrors(t *testing.T) {
+	cases := map[string]struct {
+		files map[string]string
+		want  string
+	}{
+		"path": {
+			files: map[string]string{ResourceFileJSON: `{"path": "./elsewhere"}`},
+			want:  "path: is the directory of the file",
+		},
+		"unknown": {
+			files: map[string]string{ResourceFileJSON: `{"bulid": {}}`},
+			want:  `invalid ./resources/chat/opencore.resource.json:` + "\n" + `  bulid: unknown option, did you mean "build"?`,
+		},
+		"both": {
+			files: map[string]string{ResourceFileJSON: `{}`, ResourceFileTS: `export default {}`},
+			want:  "./resources/chat has both opencore.resource.ts and opencore.resource.json",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for file, content := range tc.files {
+				writeResourceFile(t, root, "resources/chat", file, content)
+			}
+			cfg, err := parseConfig([]byte(`{"name": "demo", "core": {"path": "./core"}, "resources": {"include": ["./resources/*"]}}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cfg.loadResourceFiles(root); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaID is the published location of the generated config schema.
+const SchemaID = "https://opencorejs.dev/schemas/opencore.config.schema.json"
+
+var (
+	resourceSideType = reflect.TypeOf(ResourceBuildSideConfig{})
+	sizeBudgetType   = reflect.TypeOf(SizeBudget{})
+	adapterType      = reflect.TypeOf(AdapterConfig{})
+)
+
+// schemaField is a JSON field of a config struct.
+type schemaField struct {
+	name string
+	typ  reflect.Type
+	enum []string
+}
+
+// schemaFields lists the JSON fields of a config struct in declaration order.
+func schemaFields(t reflect.Type) []schemaField {
+	fields := make([]schemaField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		var enum []string
+		if values := field.Tag.Get("enum"); values != "" {
+			enum = strings.Split(values, ",")
+		}
+		fields = append(fields, schemaField{name: name, typ: field.Type, enum: enum})
+	}
+	return fields
+}
+
+// validateConfigTree reports options of a decoded config file that the CLI
+// does not know, with the closest known name, and values outside the
+// allowed set of enum options. Type errors are left to json.Unmarshal. name
+// is how the error refers to the file.
+func validateConfigTree(tree map[string]any, name string) error {
+	var issues []string
+	validateValue("", tree, reflect.TypeOf(Config{}), &issues)
+	if len(issues) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s:\n  %s", name, strings.Join(issues, "\n  "))
+}
+
+func validateValue(path string, value any, t reflect.Type, issues *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil {
+		return
+	}
+
+	switch t {
+	case adapterType, sizeBudgetType:
+		// The loader writes the adapter section itself, and size budgets
+		// validate their own forms.
+		return
+	case resourceSideType:
+		if _, ok := value.(bool); ok {
+			return
+		}
+		t = reflect.TypeOf(BuildSideConfig{})
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := schemaFields(t)
+		known := make(map[string]schemaField, len(fields))
+		for _, field := range fields {
+			known[field.name] = field
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := joinPath(path, key)
+			field, ok := known[key]
+			if !ok {
+				*issues = append(*issues, unknownOption(fieldPath, key, fields))
+				continue
+			}
+			if len(field.enum) > 0 {
+				if s, ok := object[key].(string); ok && !containsString(field.enum, s) {
+					*issues = append(*issues, invalidEnumValue(fieldPath, s, field.enum))
+				}
+				continue
+			}
+			validateValue(fieldPath, object[key], field.typ, issues)
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			validateValue(fmt.Sprintf("%s[%d]", path, i), item, t.Elem(), issues)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		for key, item := range object {
+			validateValue(joinPath(path, key), item, t.Elem(), issues)
+		}
+	}
+}
+
+func unknownOption(path, key string, fields []schemaField) string {
+	best, bestDistance := "", 0
+	for _, field := range fields {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(field.name))
+		if best == "" || distance < bestDistance {
+			best, bestDistance = field.name, distance
+		}
+	}
+	if best != "" && bestDistance <= max(2, len(key)/3) {
+		return fmt.Sprintf("%s: unknown option, did you mean %q?", path, best)
+	}
+	return fmt.Sprintf("%s: unknown option", path)
+}
+
+// invalidEnumValue describes a value outside the options of an enum field.
+// Options are matched case-sensitively, like the JSON Schema and the
+// defineConfig types, so a value in another case points to the right one.
+func invalidEnumValue(path, value string, options []string) string {
+	message := fmt.Sprintf("%s: %q is not one of %s", path, value, quoteList(options))
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return fmt.Sprintf("%s, did you mean %q?", message, option)
+		}
+	}
+	return message
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// schemaDocument is the root of the generated schema, with the keywords in
+// the conventional order.
+type schemaDocument struct {
+	Schema               string         `json:"$schema"`
+	ID                   string         `json:"$id"`
+	Title                string         `json:"title"`
+	Type                 string         `json:"type"`
+	Required             []string       `json:"required"`
+	AdditionalProperties bool           `json:"additionalProperties"`
+	Properties           any            `json:"properties"`
+	Defs                 map[string]any `json:"$defs"`
+}
+
+// Schema returns a JSON Schema of opencore.config.ts generated from the
+// Config structs, as shipped in schemas/opencore.config.schema.json.
+func Schema() ([]byte, error) {
+	defs := make(map[string]any)
+	schemaFor(reflect.TypeOf(Config{}), defs)
+	root := defs["Config"].(map[string]any)
+	delete(defs, "Config")
+
+	data, err := json.MarshalIndent(schemaDocument{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		ID:         SchemaID,
+		Title:      "OpenCore Config",
+		Type:       "object",
+		Required:   []string{"name"},
+		Properties: root["properties"],
+		Defs:       defs,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaFor returns the schema of t. Structs are added to defs and
+// referenced by name.
+func schemaFor(t reflect.Type, defs map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case adapterType:
+		// Adapters are objects created by the adapter packages.
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"server": map[string]any{"type": "object"},
+				"client": map[string]any{"type": "object"},
+			},
+			"additionalProperties": false,
+		}
+	case sizeBudgetType:
+		// A byte count or a size string such as "250 KB", alone or in
+		// { max, soft }.
+		if _, ok := defs["SizeBudget"]; !ok {
+			size := []any{map[string]any{"type": "integer", "minimum": 0}, map[string]any{"type": "string"}}
+			defs["SizeBudget"] = map[string]any{"anyOf": append(size, map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{"max": map[string]any{"anyOf": size}, "soft": map[string]any{"type": "boolean"}},
+				"required":             []string{"max"},
+				"additionalProperties": false,
+			})}
+		}
+		return map[string]any{"$ref": "#/$defs/SizeBudget"}
+	case resourceSideType:
+		// false skips the side; an object overrides its options.
+		return map[string]any{"anyOf": []any{
+			map[string]any{"type": "boolean"},
+			schemaFor(reflect.TypeOf(BuildSideConfig{}), defs),
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		ref := map[string]any{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := defs[t.Name()]; ok {
+			return ref
+		}
+		properties := make(map[string]any)
+		defs[t.Name()] = map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		for _, field := range schemaFields(t) {
+			property := schemaFor(field.typ, defs)
+			if len(field.enum) > 0 {
+				property = map[string]any{"type": "string", "enum": field.enum}
+			}
+			properties[field.name] = property
+		}
+		return ref
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfigJSONSuggestsOptions(t *testing.T) {
+	_, err := parseConfig([]byte(`{
+		"name": "demo",
+		"core": {"path": "./core", "resourceName": "core"},
+		"resources": {"explicit": [{"path": "./chat", "build": {"server": false, "client": {"platfrom": "browser"}}}]},
+		"build": {"sourcemaps": true, "maxWorker": 4, "environments": {"production": {"minfy": true}}},
+		"dev": {"restart": {"mode": "restart"}},
+		"somethingElse": true
+	}`))
+	if err == nil {
+		t.Fatal("expected unknown options to be rejected")
+	}
+	for _, want := range []string{
+		`build.sourcemaps: unknown option, did you mean "sourceMaps"?`,
+		`build.maxWorker: unknown option, did you mean "maxWorkers"?`,
+		`build.environments.production.minfy: unknown option, did you mean "minify"?`,
+		`resources.explicit[0].build.client.platfrom: unknown option, did you mean "platform"?`,
+		`dev.restart.mode: "restart" is not one of "auto", "process", "txadmin", "none"`,
+		"somethingElse: unknown option\n",
+	} {
+		if !strings.Contains(err.Error()+"\n", want) {
+			t.Errorf("expected %q in:\n%s", want, err)
+		}
+	}
+}
+
+func TestValidateConfigJSONAcceptsValidConfig(t *testing.T) {
+	_, err := parseConfig([]byte(`{
+		"name": "demo",
+		"outDir": "./build",
+		"adapter": {"server": {"name": "fivem", "valid": true}},
+		"core": {"path": "./core", "resourceName": "core", "views": {"path": "./ui", "framework": "vite"}},
+		"resources": {"include": ["./resources/*"], "explicit": [{"path": "./chat", "build": {"server": false, "client": {"platform": "browser", "format": "iife"}, "budgets": {"client": "250 KB"}}}]},
+		"build": {"minify": true, "dependencyResolution": {"mode": "shared-resource"}, "environments": {"production": {"resources": {"devtools": false}}}},
+		"dev": {"restart": {"mode": "txadmin"}, "process": {"env": {"ANY_NAME": "1"}}}
+	}`))
+	if err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+}
+
+func TestSchemaIsUpToDate(t *testing.T) {
+	schema, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shipped, err := os.ReadFile(filepath.Join("..", "..", "schemas", "opencore.config.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(schema, shipped) {
+		t.Error("schemas/opencore.config.schema.json is out of date; run `go run . config schema > schemas/opencore.config.schema.json`")
+	}
+}
+
+// TestSchemaEnumsMatchValidator checks that the validator accepts exactly
+// the enum values the JSON Schema lists: every listed value, and no value in
+// another case.
+func TestSchemaEnumsMatchValidator(t *testing.T) {
+	types := make(map[string]reflect.Type)
+	var collect func(reflect.Type)
+	collect = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		if typ == resourceSideType {
+			typ = reflect.TypeOf(BuildSideConfig{})
+		}
+		if typ.Kind() != reflect.Struct || types[typ.Name()] != nil {
+			return
+		}
+		types[typ.Name()] = typ
+		for _, field := range schemaFields(typ) {
+			collect(field.typ)
+		}
+	}
+	collect(reflect.TypeOf(Config{}))
+
+	data, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	checked := 0
+	for name, def := range schema.Defs {
+		for property, spec := range def.Properties {
+			if len(spec.Enum) == 0 {
+				continue
+			}
+			typ, ok := types[name]
+			if !ok {
+				t.Fatalf("no config type for $defs/%s", name)
+			}
+			for _, value := range spec.Enum {
+				var issues []string
+				validateValue("", map[string]any{property: value}, typ, &issues)
+				if len(issues) > 0 {
+					t.Errorf("%s.%s: schema lists %q but the validator rejects it: %v", name, property, value, issues)
+				}
+				upper := strings.ToUpper(value)
+				if upper == value || slices.Contains(spec.Enum, upper) {
+					continue
+				}
+				issues = nil
+				validateValue("", map[string]any{property: upper}, typ, &issues)
+				if len(issues) != 1 || !strings.HasSuffix(issues[0], fmt.Sprintf("did you mean %q?", value)) {
+					t.Errorf("%s.%s: expected %q to be rejected with a hint, got %v", name, property, upper, issues)
+				}
+			}
+			checked++
+		}
+	}
+	if checked == 0 {
+		t.Fatal("expected the schema to have enum options")
+	}
+}
+
+// TestTypeScriptTypesMatchConfig keeps the defineConfig types in index.d.ts
+// in sync with the options the CLI accepts.
+func TestTypeScriptTypesMatchConfig(t *testing.T) {
+	declarations, err := os.ReadFile(filepath.Join("..", "..", "index.d.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces := parseInterfaces(string(declarations))
+
+	types := map[string]reflect.Type{
+		"OpenCoreConfig":             reflect.TypeOf(Config{}),
+		"OpenCoreAdapterConfig":      reflect.TypeOf(AdapterConfig{}),
+		"CoreConfig":                 reflect.TypeOf(CoreConfig{}),
+		"ResourcesConfig":            reflect.TypeOf(ResourcesConfig{}),
+		"StandaloneConfig":           reflect.TypeOf(StandaloneConfig{}),
+		"ExplicitResource":           reflect.TypeOf(ExplicitResource{}),
+		"ResourceBuildConfig":        reflect.TypeOf(ResourceBuildConfig{}),
+		"ViewsConfig":                reflect.TypeOf(ViewsConfig{}),
+		"EntryPoints":                reflect.TypeOf(EntryPoints{}),
+		"BuildConfig":                reflect.TypeOf(BuildConfig{}),
+		"SideBuildConfig":            reflect.TypeOf(BuildSideConfig{}),
+		"DependencyResolutionConfig": reflect.TypeOf(DependencyResolutionConfig{}),
+		"BudgetsConfig":              reflect.TypeOf(BudgetsConfig{}),
+		"EnvironmentOverride":        reflect.TypeOf(EnvironmentOverride{}),
+		"FileReplacement":            reflect.TypeOf(FileReplacement{}),
+		"DeployConfig":               reflect.TypeOf(DeployConfig{}),
+		"DevConfig":                  reflect.TypeOf(DevConfig{}),
+		"DevBridgeConfig":            reflect.TypeOf(DevBridgeConfig{}),
+		"DevRestartConfig":           reflect.TypeOf(DevRestartConfig{}),
+		"DevTxAdminConfig":           reflect.TypeOf(DevTxAdminConfig{}),
+		"DevProcessConfig":           reflect.TypeOf(DevProcessConfig{}),
+		"OpenCoreWorkspace":          reflect.TypeOf(Workspace{}),
+	}
+	for name, typ := range types {
+		fields, ok := interfaces[name]
+		if !ok {
+			t.Errorf("index.d.ts has no interface %s", name)
+			continue
+		}
+		var goFields []string
+		for _, field := range schemaFields(typ) {
+			goFields = append(goFields, field.name)
+		}
+		sort.Strings(goFields)
+		sort.Strings(fields)
+		if strings.Join(fields, ",") != strings.Join(goFields, ",") {
+			t.Errorf("%s: index.d.ts declares %v, the CLI accepts %v", name, fields, goFields)
+		}
+	}
+}
+
+var (
+	interfacePattern = regexp.MustCompile(`(?s)export interface (\w+)[^{]*\{(.*?)\n\}`)
+	commentPattern   = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	propertyPattern  = regexp.MustCompile(`(?m)^  (\w+)\??:`)
+)
+
+// parseInterfaces returns the property names of every exported interface.
+func parseInterfaces(source string) map[string][]string {
+	interfaces := make(map[string][]string)
+	for _, match := range interfacePattern.FindAllStringSubmatch(source, -1) {
+		body := commentPattern.ReplaceAllString(match[2], "")
+		var fields []string
+		for _, property := range propertyPattern.FindAllStringSubmatch(body, -1) {
+			fields = append(fields, property[1])
+		}
+		interfaces[match[1]] = fields
+	}
+	return interfaces
+}
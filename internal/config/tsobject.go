@@ -0,0 +1,259 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// tsObject is an object literal in the config source. Offsets are byte
+// positions in the source, so rewrites can touch single properties and keep
+// the rest of the file as written.
+type tsObject struct {
+	open, close int // offsets of { and }
+	props       []*tsProperty
+}
+
+// tsProperty is a `key: value` property of an object literal. Spreads,
+// methods, shorthand and computed properties are skipped.
+type tsProperty struct {
+	key        string
+	start      int       // offset of the key
+	valueStart int       // offset of the value
+	end        int       // offset after the value
+	value      *tsObject // set when the value is an object literal
+}
+
+// prop returns the property named key; the last one wins, as in JavaScript.
+func (o *tsObject) prop(key string) *tsProperty {
+	if o == nil {
+		return nil
+	}
+	for i := len(o.props) - 1; i >= 0; i-- {
+		if o.props[i].key == key {
+			return o.props[i]
+		}
+	}
+	return nil
+}
+
+// object returns the object literal value of the property named key.
+func (o *tsObject) object(key string) *tsObject {
+	if prop := o.prop(key); prop != nil {
+		return prop.value
+	}
+	return nil
+}
+
+type tsScanner struct {
+	src []byte
+}
+
+// parseConfigObject finds the object literal passed to defineConfig, or
+// exported by default, and parses it.
+func parseConfigObject(src []byte) (*tsObject, error) {
+	s := tsScanner{src: src}
+	previous := ""
+	for i := 0; i < len(src); {
+		if j := s.skipTrivia(i); j != i {
+			i = j
+			continue
+		}
+		switch c := src[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			i = s.skipString(i)
+			previous = ""
+		case isIdentStart(c):
+			end := s.skipIdent(i)
+			word := string(src[i:end])
+			next := s.skipTrivia(end)
+			if word == "defineConfig" && next < len(src) && src[next] == '(' {
+				next = s.skipTrivia(next + 1)
+			}
+			if (word == "defineConfig" || (word == "default" && previous == "export")) && next < len(src) && src[next] == '{' {
+				return s.parseObject(next)
+			}
+			i, previous = end, word
+		default:
+			i++
+			previous = ""
+		}
+	}
+	return nil, fmt.Errorf("no object literal passed to defineConfig found")
+}
+
+func (s *tsScanner) parseObject(i int) (*tsObject, error) {
+	object := &tsObject{open: i}
+	i++
+	for {
+		i = s.skipTrivia(i)
+		if i >= len(s.src) {
+			return nil, fmt.Errorf("unterminated object literal at %s", s.position(object.open))
+		}
+		switch c := s.src[i]; {
+		case c == '}':
+			object.close = i
+			return object, nil
+		case c == ',':
+			i++
+			continue
+		case c == ')' || c == ']':
+			return nil, fmt.Errorf("unexpected %q at %s", c, s.position(i))
+		}
+
+		start, key := i, ""
+		switch c := s.src[i]; {
+		case c == '"' || c == '\'':
+			end := s.skipString(i)
+			unquoted, err := strconv.Unquote(`"` + string(s.src[i+1:end-1]) + `"`)
+			if err != nil {
+				unquoted = string(s.src[i+1 : end-1])
+			}
+			i, key = end, unquoted
+		case bytes.HasPrefix(s.src[i:], []byte("...")):
+			i = s.skipValue(i + 3)
+			continue
+		case isIdentStart(c):
+			end := s.skipIdent(i)
+			i, key = end, string(s.src[start:end])
+		default:
+			i = s.skipValue(i)
+			continue
+		}
+
+		i = s.skipTrivia(i)
+		if i >= len(s.src) || s.src[i] != ':' {
+			// Shorthand properties, methods and accessors.
+			i = s.skipValue(i)
+			continue
+		}
+
+		prop := &tsProperty{key: key, start: start, valueStart: s.skipTrivia(i + 1)}
+		i = prop.valueStart
+		if i < len(s.src) && s.src[i] == '{' {
+			value, err := s.parseObject(i)
+			if err != nil {
+				return nil, err
+			}
+			prop.value = value
+			i = value.close + 1
+			prop.end = i
+			// A cast such as `as const` still belongs to the value.
+			if end := s.skipValue(i); end > i {
+				prop.end = end
+			}
+		} else {
+			prop.end = s.skipValue(i)
+		}
+		object.props = append(object.props, prop)
+		i = prop.end
+	}
+}
+
+// skipValue returns the offset after the expression starting at i, which
+// ends before a comma or closing bracket outside nested brackets.
+func (s *tsScanner) skipValue(i int) int {
+	end := i
+	for i < len(s.src) {
+		if j := s.skipTrivia(i); j != i {
+			i = j
+			continue
+		}
+		switch s.src[i] {
+		case ',', '}', ')', ']':
+			return end
+		case '"', '\'', '`':
+			i = s.skipString(i)
+		case '(', '[', '{':
+			i = s.skipBalanced(i)
+		default:
+			i++
+		}
+		end = i
+	}
+	return end
+}
+
+// skipBalanced returns the offset after the bracket matching the one at i.
+func (s *tsScanner) skipBalanced(i int) int {
+	depth := 0
+	for i < len(s.src) {
+		if j := s.skipTrivia(i); j != i {
+			i = j
+			continue
+		}
+		switch s.src[i] {
+		case '"', '\'', '`':
+			i = s.skipString(i)
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return i
+}
+
+// skipString returns the offset after the string or template literal at i.
+func (s *tsScanner) skipString(i int) int {
+	quote := s.src[i]
+	for i++; i < len(s.src); i++ {
+		switch c := s.src[i]; {
+		case c == '\\':
+			i++
+		case c == quote:
+			return i + 1
+		case quote == '`' && c == '$' && i+1 < len(s.src) && s.src[i+1] == '{':
+			i = s.skipBalanced(i+1) - 1
+		}
+	}
+	return i
+}
+
+// skipTrivia returns the offset after whitespace and comments at i.
+func (s *tsScanner) skipTrivia(i int) int {
+	for i < len(s.src) {
+		switch {
+		case s.src[i] == ' ' || s.src[i] == '\t' || s.src[i] == '\n' || s.src[i] == '\r':
+			i++
+		case bytes.HasPrefix(s.src[i:], []byte("//")):
+			end := bytes.IndexByte(s.src[i:], '\n')
+			if end < 0 {
+				return len(s.src)
+			}
+			i += end
+		case bytes.HasPrefix(s.src[i:], []byte("/*")):
+			end := bytes.Index(s.src[i+2:], []byte("*/"))
+			if end < 0 {
+				return len(s.src)
+			}
+			i += end + 4
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func (s *tsScanner) skipIdent(i int) int {
+	for i < len(s.src) && (isIdentStart(s.src[i]) || (s.src[i] >= '0' && s.src[i] <= '9')) {
+		i++
+	}
+	return i
+}
+
+// position formats an offset as line:column for error messages.
+func (s *tsScanner) position(offset int) string {
+	line := bytes.Count(s.src[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(s.src[:offset], '\n')
+	return fmt.Sprintf("%d:%d", line, column)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
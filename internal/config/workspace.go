@@ -0,0 +1,189 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// WorkspaceFile marks the root of a workspace: a repository holding several
+// OpenCore projects.
+const WorkspaceFile = "opencore.workspace.ts"
+
+// Workspace lists the projects of a workspace, which build, doctor and dev
+// operate on together.
+type Workspace struct {
+	// Projects are project directories relative to the workspace root. Globs
+	// such as ./servers/* match every directory with an opencore.config.ts.
+	Projects []string `json:"projects"`
+	// MaxWorkers is the size of the build worker pool the projects share.
+	// When unset, the first project's build settings decide.
+	MaxWorkers int `json:"maxWorkers,omitempty"`
+
+	root string
+}
+
+// WorkspaceProject is a project of a workspace.
+type WorkspaceProject struct {
+	// Name is the project's directory name.
+	Name string
+	// Path is the project directory relative to the workspace root.
+	Path string
+	// Root is the absolute project directory.
+	Root string
+}
+
+// FindWorkspaceRoot searches upwards from startDir to locate
+// opencore.workspace.ts.
+func FindWorkspaceRoot(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, WorkspaceFile)); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("%s not found in current directory or any parent directory", WorkspaceFile)
+}
+
+// LoadWorkspace reads and transpiles the opencore.workspace.ts at root.
+func LoadWorkspace(root string) (*Workspace, error) {
+	output, err := transpileConfig(filepath.Join(root, WorkspaceFile), root)
+	if err != nil {
+		return nil, err
+	}
+	return parseWorkspace(root, output)
+}
+
+func parseWorkspace(root string, output []byte) (*Workspace, error) {
+	var tree map[string]any
+	if err := json.Unmarshal(output, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace JSON: %w\nOutput: %s", err, string(output))
+	}
+	var issues []string
+	validateValue("", tree, reflect.TypeOf(Workspace{}), &issues)
+	if len(issues) > 0 {
+		return nil, fmt.Errorf("invalid %s:\n  %s", WorkspaceFile, strings.Join(issues, "\n  "))
+	}
+
+	var workspace Workspace
+	if err := json.Unmarshal(output, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace JSON: %w\nOutput: %s", err, string(output))
+	}
+	if len(workspace.Projects) == 0 {
+		return nil, fmt.Errorf("%s: projects is required", WorkspaceFile)
+	}
+	if workspace.MaxWorkers < 0 {
+		return nil, fmt.Errorf("%s: maxWorkers must not be negative", WorkspaceFile)
+	}
+	workspace.root = root
+	if _, err := workspace.AllProjects(); err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// Root returns the workspace root.
+func (w *Workspace) Root() string {
+	return w.root
+}
+
+// AllProjects returns the projects of the workspace in the order they are
+// listed. Project names must be unique, since --project selects by name.
+func (w *Workspace) AllProjects() ([]WorkspaceProject, error) {
+	var projects []WorkspaceProject
+	seen := make(map[string]string)
+	for _, entry := range w.Projects {
+		p := normalizedConfigPath(entry)
+		if p == "" || filepath.IsAbs(p) {
+			return nil, fmt.Errorf("%s: project %q must be a path relative to the workspace", WorkspaceFile, entry)
+		}
+		matches, err := filepath.Glob(filepath.Join(w.root, p))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid project pattern %q: %w", WorkspaceFile, entry, err)
+		}
+		glob := strings.ContainsAny(p, "*?[")
+
+		found := 0
+		for _, match := range matches {
+			if _, err := os.Stat(filepath.Join(match, "opencore.config.ts")); err != nil {
+				if glob {
+					continue
+				}
+				return nil, fmt.Errorf("%s: project %s has no opencore.config.ts", WorkspaceFile, entry)
+			}
+			found++
+
+			rel, err := filepath.Rel(w.root, match)
+			if err != nil {
+				rel = match
+			}
+			project := WorkspaceProject{Name: filepath.Base(match), Path: rel, Root: match}
+			if other, ok := seen[project.Name]; ok {
+				if other == project.Path {
+					continue
+				}
+				return nil, fmt.Errorf("%s: projects %s and %s are both named %s", WorkspaceFile, other, project.Path, project.Name)
+			}
+			seen[project.Name] = project.Path
+			projects = append(projects, project)
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("%s: no project found at %s", WorkspaceFile, entry)
+		}
+	}
+	return projects, nil
+}
+
+// SelectProjects returns the projects named in names, by directory name or
+// path, in workspace order. No names selects every project.
+func (w *Workspace) SelectProjects(names []string) ([]WorkspaceProject, error) {
+	projects, err := w.AllProjects()
+	if err != nil || len(names) == 0 {
+		return projects, err
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range names {
+		matched := false
+		for _, project := range projects {
+			if name == project.Name || normalizedConfigPath(name) == project.Path {
+				selected[project.Path] = true
+				matched = true
+			}
+		}
+		if !matched {
+			available := make([]string, 0, len(projects))
+			for _, project := range projects {
+				available = append(available, project.Name)
+			}
+			return nil, fmt.Errorf("unknown project %q (projects: %s)", name, strings.Join(available, ", "))
+		}
+	}
+
+	var subset []WorkspaceProject
+	for _, project := range projects {
+		if selected[project.Path] {
+			subset = append(subset, project)
+		}
+	}
+	return subset, nil
+}
+
+// WorkspaceRoot returns the root of the workspace the project belongs to,
+// or "" outside a workspace.
+func (c *Config) WorkspaceRoot() string {
+	return c.workspaceRoot
+}
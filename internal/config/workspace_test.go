@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeWorkspaceProject(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "opencore.config.ts"), []byte("export default {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceProject(t, filepath.Join(root, "servers", "fivem"))
+	writeWorkspaceProject(t, filepath.Join(root, "servers", "redm"))
+	if err := os.MkdirAll(filepath.Join(root, "servers", "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	workspace, err := parseWorkspace(root, []byte(`{"projects": ["./servers/*"], "maxWorkers": 4}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workspace.MaxWorkers != 4 || workspace.Root() != root {
+		t.Errorf("unexpected workspace %+v", workspace)
+	}
+	projects, err := workspace.SelectProjects(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 || projects[0].Name != "fivem" || projects[1].Name != "redm" {
+		t.Fatalf("expected the globbed projects with a config, got %+v", projects)
+	}
+	if want := filepath.Join("servers", "redm"); projects[1].Path != want || projects[1].Root != filepath.Join(root, want) {
+		t.Errorf("unexpected project paths %+v", projects[1])
+	}
+
+	for _, names := range [][]string{{"redm"}, {"./servers/redm"}} {
+		selected, err := workspace.SelectProjects(names)
+		if err != nil || len(selected) != 1 || selected[0].Name != "redm" {
+			t.Errorf("%v: expected redm, got %+v %v", names, selected, err)
+		}
+	}
+	if _, err := workspace.SelectProjects([]string{"gta"}); err == nil || !strings.Contains(err.Error(), "projects: fivem, redm") {
+		t.Errorf("expected an unknown project to list the projects, got %v", err)
+	}
+}
+
+func TestParseWorkspaceErrors(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceProject(t, filepath.Join(root, "a", "server"))
+	writeWorkspaceProject(t, filepath.Join(root, "b", "server"))
+	if err := os.MkdirAll(filepath.Join(root, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		`{"project": ["./a/server"]}`:                `project: unknown option, did you mean "projects"?`,
+		`{"projects": []}`:                           "projects is required",
+		`{"projects": ["./lib"]}`:                    "project ./lib has no opencore.config.ts",
+		`{"projects": ["./missing/*"]}`:              "no project found at ./missing/*",
+		`{"projects": ["/abs/project"]}`:             "must be a path relative to the workspace",
+		`{"projects": ["./a/server", "./b/server"]}`: "are both named server",
+	}
+	for output, want := range cases {
+		if _, err := parseWorkspace(root, []byte(output)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected %q, got %v", output, want, err)
+		}
+	}
+}
+
+func TestFindWorkspaceRoot(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "servers", "fivem")
+	writeWorkspaceProject(t, project)
+	if err := os.WriteFile(filepath.Join(root, WorkspaceFile), []byte("export default { projects: [] }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := FindWorkspaceRoot(project)
+	if err != nil || found != root {
+		t.Errorf("expected %s, got %s %v", root, found, err)
+	}
+	if _, err := FindWorkspaceRoot(t.TempDir()); err == nil {
+		t.Error("expected no workspace outside one")
+	}
+}
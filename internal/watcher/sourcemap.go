@@ -0,0 +1,128 @@
+package watcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sourceMap is a decoded version 3 source map.
+type sourceMap struct {
+	sources []string
+	// lines holds the segments of each generated line, sorted by column.
+	lines [][]mapping
+}
+
+type mapping struct {
+	generatedColumn int
+	source          int // -1 when the segment maps to no source
+	line            int // 0-based, like the map itself
+	column          int
+}
+
+// parseSourceMap decodes a source map. Source paths are joined with the
+// map's sourceRoot but left relative to the map.
+func parseSourceMap(data []byte) (*sourceMap, error) {
+	var raw struct {
+		Version    int      `json:"version"`
+		SourceRoot string   `json:"sourceRoot"`
+		Sources    []string `json:"sources"`
+		Mappings   string   `json:"mappings"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid source map: %w", err)
+	}
+	if raw.Version != 3 {
+		return nil, fmt.Errorf("unsupported source map version %d", raw.Version)
+	}
+
+	m := &sourceMap{sources: make([]string, len(raw.Sources))}
+	for i, source := range raw.Sources {
+		if raw.SourceRoot != "" {
+			source = strings.TrimSuffix(raw.SourceRoot, "/") + "/" + source
+		}
+		m.sources[i] = source
+	}
+
+	// Fields other than the generated column are relative to the previous
+	// segment of the whole mappings string.
+	var source, line, column int
+	for _, encodedLine := range strings.Split(raw.Mappings, ";") {
+		var segments []mapping
+		generatedColumn := 0
+		for _, encoded := range strings.Split(encodedLine, ",") {
+			if encoded == "" {
+				continue
+			}
+			fields, err := decodeVLQ(encoded)
+			if err != nil {
+				return nil, err
+			}
+			generatedColumn += fields[0]
+			segment := mapping{generatedColumn: generatedColumn, source: -1}
+			if len(fields) >= 4 {
+				source += fields[1]
+				line += fields[2]
+				column += fields[3]
+				segment.source, segment.line, segment.column = source, line, column
+			}
+			segments = append(segments, segment)
+		}
+		sort.SliceStable(segments, func(i, j int) bool {
+			return segments[i].generatedColumn < segments[j].generatedColumn
+		})
+		m.lines = append(m.lines, segments)
+	}
+	return m, nil
+}
+
+// lookup returns the original position of a generated one. Lines and
+// columns are 1-based, as in stack traces.
+func (m *sourceMap) lookup(line, column int) (source string, originalLine, originalColumn int, ok bool) {
+	if line < 1 || line > len(m.lines) {
+		return "", 0, 0, false
+	}
+	segments := m.lines[line-1]
+	i := sort.Search(len(segments), func(i int) bool {
+		return segments[i].generatedColumn > column-1
+	}) - 1
+	if i < 0 {
+		// A frame before the first segment of its line belongs to that segment.
+		i = 0
+	}
+	if i >= len(segments) || segments[i].source < 0 || segments[i].source >= len(m.sources) {
+		return "", 0, 0, false
+	}
+	segment := segments[i]
+	return m.sources[segment.source], segment.line + 1, segment.column + 1, true
+}
+
+const base64Digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeVLQ decodes the base64 VLQ fields of a mappings segment.
+func decodeVLQ(encoded string) ([]int, error) {
+	var fields []int
+	value, shift := 0, 0
+	for i := 0; i < len(encoded); i++ {
+		digit := strings.IndexByte(base64Digits, encoded[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid source map mapping %q", encoded)
+		}
+		value += (digit & 31) << shift
+		if digit&32 != 0 {
+			shift += 5
+			continue
+		}
+		if value&1 != 0 {
+			fields = append(fields, -(value >> 1))
+		} else {
+			fields = append(fields, value>>1)
+		}
+		value, shift = 0, 0
+	}
+	if shift != 0 || len(fields) == 0 {
+		return nil, fmt.Errorf("invalid source map mapping %q", encoded)
+	}
+	return fields, nil
+}
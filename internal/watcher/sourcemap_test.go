@@ -0,0 +1,45 @@
+package watcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testSourceMap maps line 1 of the bundle to line 1 of the source, and
+// line 2 to line 2 with a segment at generated column 4 -> source column 4.
+const testSourceMap = `{"version":3,"sources":["../../resources/chat/src/server.ts"],"mappings":"AAAA;AACA,IAAI"}`
+
+func TestDecodeVLQ(t *testing.T) {
+	cases := map[string][]int{
+		"AAAA": {0, 0, 0, 0},
+		"IAAI": {4, 0, 0, 4},
+		"D":    {-1},
+		"gB":   {16},
+	}
+	for encoded, want := range cases {
+		got, err := decodeVLQ(encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", encoded, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", encoded, want, got)
+		}
+	}
+	if _, err := decodeVLQ("g"); err == nil {
+		t.Error("expected an unterminated field to fail")
+	}
+}
+
+func TestSourceMapLookup(t *testing.T) {
+	sm, err := parseSourceMap([]byte(testSourceMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, line, column, ok := sm.lookup(2, 6)
+	if !ok || source != "../../resources/chat/src/server.ts" || line != 2 || column != 5 {
+		t.Errorf("unexpected lookup result %s:%d:%d (%v)", source, line, column, ok)
+	}
+	if _, _, _, ok := sm.lookup(3, 1); ok {
+		t.Error("expected a line past the mappings to have no position")
+	}
+}
@@ -0,0 +1,153 @@
+package watcher
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stackLocation matches a bundle position in a stack frame, e.g.
+// "@chat/server.js:12:34" in "at handler (@chat/server.js:12:34)".
+var stackLocation = regexp.MustCompile(`([^\s()]+\.[cm]?js):(\d+):(\d+)`)
+
+const inlineSourceMapPrefix = "//# sourceMappingURL=data:application/json;base64,"
+
+// stackRemapper rewrites stack frames that point into built bundles to the
+// original TypeScript sources, using the source maps written by the build.
+type stackRemapper struct {
+	mu     sync.Mutex
+	outDir string
+	maps   map[string]cachedSourceMap
+}
+
+type cachedSourceMap struct {
+	modTime time.Time
+	sm      *sourceMap // nil when the bundle has no usable map
+	dir     string     // directory the map's sources are relative to
+}
+
+func newStackRemapper(outDir string) *stackRemapper {
+	return &stackRemapper{outDir: outDir, maps: make(map[string]cachedSourceMap)}
+}
+
+// setOutDir points the remapper at the build output of a reloaded config.
+func (r *stackRemapper) setOutDir(outDir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outDir = outDir
+}
+
+// Remap rewrites every frame of stack it has a source map for to a path
+// relative to the project root; other frames are left as they are.
+func (r *stackRemapper) Remap(stack string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return stackLocation.ReplaceAllStringFunc(stack, func(location string) string {
+		match := stackLocation.FindStringSubmatch(location)
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+
+		bundle := r.findBundle(match[1])
+		if bundle == "" {
+			return location
+		}
+		cached := r.load(bundle)
+		if cached.sm == nil {
+			return location
+		}
+		source, originalLine, originalColumn, ok := cached.sm.lookup(line, column)
+		if !ok {
+			return location
+		}
+		return projectRelative(filepath.Join(cached.dir, filepath.FromSlash(source))) +
+			":" + strconv.Itoa(originalLine) + ":" + strconv.Itoa(originalColumn)
+	})
+}
+
+// findBundle returns the built file a stack frame path refers to. Frames use
+// the server's view of the file ("@chat/server.js", or a path inside the
+// deployment destination), so the longest trailing part of the path that
+// exists in the build output wins. The output copy is preferred because the
+// relative source paths in its map are only valid from there.
+func (r *stackRemapper) findBundle(framePath string) string {
+	framePath = strings.TrimPrefix(framePath, "file://")
+	framePath = strings.TrimPrefix(framePath, "@")
+	parts := strings.FieldsFunc(filepath.ToSlash(framePath), func(c rune) bool { return c == '/' })
+	for i := range parts {
+		candidate := filepath.Join(append([]string{r.outDir}, parts[i:]...)...)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	if filepath.IsAbs(framePath) {
+		if info, err := os.Stat(framePath); err == nil && !info.IsDir() {
+			return framePath
+		}
+	}
+	return ""
+}
+
+// load returns the source map of bundle, reading it again when the bundle
+// was rebuilt. External maps (bundle + ".map") are tried before inline ones.
+func (r *stackRemapper) load(bundle string) cachedSourceMap {
+	info, err := os.Stat(bundle)
+	if err != nil {
+		return cachedSourceMap{}
+	}
+	if cached, ok := r.maps[bundle]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached
+	}
+
+	cached := cachedSourceMap{modTime: info.ModTime(), dir: filepath.Dir(bundle)}
+	if data, err := os.ReadFile(bundle + ".map"); err == nil {
+		cached.sm, _ = parseSourceMap(data)
+	} else if data, err := os.ReadFile(bundle); err == nil {
+		cached.sm = inlineSourceMap(data)
+	}
+	r.maps[bundle] = cached
+	return cached
+}
+
+// inlineSourceMap decodes the data URL source map esbuild appends to a
+// bundle when build.sourceMaps is on.
+func inlineSourceMap(bundle []byte) *sourceMap {
+	i := bytes.LastIndex(bundle, []byte(inlineSourceMapPrefix))
+	if i < 0 {
+		return nil
+	}
+	encoded := bundle[i+len(inlineSourceMapPrefix):]
+	if end := bytes.IndexAny(encoded, "\r\n"); end >= 0 {
+		encoded = encoded[:end]
+	}
+	data, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return nil
+	}
+	sm, _ := parseSourceMap(data)
+	return sm
+}
+
+// projectRelative makes path relative to the project root, the working
+// directory of dev mode, so terminals can open it.
+func projectRelative(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(abs)
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,52 @@
+package watcher
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStackRemapperRewritesBundleFrames(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("build/chat/server.js", "a\nbbbbbbbb\n")
+	write("build/chat/server.js.map", testSourceMap)
+	inline := base64.StdEncoding.EncodeToString([]byte(`{"version":3,"sources":["../../resources/radio/src/client.ts"],"mappings":"AAAA"}`))
+	write("build/radio/client.js", "x\n//# sourceMappingURL=data:application/json;base64,"+inline+"\n")
+
+	r := newStackRemapper("build")
+	stack := "Error: boom\n" +
+		"    at handler (@chat/server.js:2:6)\n" +
+		"    at /srv/resources/[opencore]/radio/client.js:1:1\n" +
+		"    at node:internal/process/task_queues:95:5\n" +
+		"    at @unknown/server.js:1:1"
+	want := "Error: boom\n" +
+		"    at handler (resources/chat/src/server.ts:2:5)\n" +
+		"    at resources/radio/src/client.ts:1:1\n" +
+		"    at node:internal/process/task_queues:95:5\n" +
+		"    at @unknown/server.js:1:1"
+	if got := r.Remap(stack); got != want {
+		t.Errorf("unexpected stack:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A rebuilt bundle is read again.
+	write("build/chat/server.js.map", `{"version":3,"sources":["../../resources/chat/src/main.ts"],"mappings":"AAAA;AACA,IAAI"}`)
+	future := r.maps[filepath.Join("build", "chat", "server.js")].modTime.Add(1e9)
+	if err := os.Chtimes(filepath.Join(root, "build/chat/server.js"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Remap("at @chat/server.js:2:6"); got != "at resources/chat/src/main.ts:2:5" {
+		t.Errorf("expected the new map to be used, got %s", got)
+	}
+}
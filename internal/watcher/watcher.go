@@ -3,6 +3,7 @@ package watcher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,6 +28,7 @@ type Watcher struct {
 	debounceTimers map[string]*time.Timer
 	restarter      restarter
 	logQueue       chan LogMessage
+	stacks         *stackRemapper
 	buildingMutex  sync.Mutex
 	buildingSet    map[string]bool // Track which resources are currently being built
 }
@@ -39,10 +41,11 @@ func New(cfg *config.Config) (*Watcher, error) {
 
 	watcher := &Watcher{
 		config:         cfg,
-		builder:        builder.New(cfg),
+		builder:        newDevBuilder(cfg),
 		watcher:        w,
 		debounceTimers: make(map[string]*time.Timer),
 		logQueue:       make(chan LogMessage, 256),
+		stacks:         newStackRemapper(cfg.OutDir),
 		buildingSet:    make(map[string]bool),
 	}
 
@@ -55,16 +58,48 @@ func New(cfg *config.Config) (*Watcher, error) {
 	return watcher, nil
 }
 
+// replaceBuilder switches to a builder for a reloaded config. The old one is
+// closed; builds still running on it finish first.
+func (w *Watcher) replaceBuilder(cfg *config.Config) {
+	w.builder.Close()
+	w.builder = newDevBuilder(cfg)
+	w.stacks.setOutDir(cfg.OutDir)
+}
+
+// reloadConfig loads the config again for the environment dev mode started
+// with, so --env and its overrides survive config changes.
+func (w *Watcher) reloadConfig() (*config.Config, string, error) {
+	cfg, root, err := config.LoadWithProjectRoot()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg.Build.Environment = w.config.Build.Environment
+	if err := cfg.ApplyEnvironment(); err != nil {
+		return nil, "", err
+	}
+	return cfg, root, nil
+}
+
+// newDevBuilder returns a builder that always writes source maps, which the
+// log bridge uses to point stack traces at the original sources.
+func newDevBuilder(cfg *config.Config) *builder.Builder {
+	b := builder.New(cfg)
+	b.SetDevSourceMaps(true)
+	return b
+}
+
 func (w *Watcher) Watch(ctx context.Context) error {
+	defer func() { w.builder.Close() }()
 	allTasks := w.builder.CollectTasks()
 
-	// Watch config file for dynamic updates
-	configPath := "opencore.config.ts"
-	if _, err := os.Stat(configPath); err == nil {
-		if err := w.watcher.Add(configPath); err != nil {
-			fmt.Println(ui.Warning(fmt.Sprintf("Failed to watch %s: %v", configPath, err)))
-		} else {
-			fmt.Println(ui.Info(fmt.Sprintf("Watching configuration: %s", configPath)))
+	// Watch config files for dynamic updates
+	for _, configPath := range w.configFiles() {
+		if _, err := os.Stat(configPath); err == nil {
+			if err := w.watcher.Add(configPath); err != nil {
+				fmt.Println(ui.Warning(fmt.Sprintf("Failed to watch %s: %v", configPath, err)))
+			} else {
+				fmt.Println(ui.Info(fmt.Sprintf("Watching configuration: %s", configPath)))
+			}
 		}
 	}
 
@@ -175,9 +210,9 @@ func (w *Watcher) Watch(ctx context.Context) error {
 						return
 					}
 					// Handle config file change
-					if filepath.Base(fileName) == "opencore.config.ts" {
+					if w.isConfigFile(fileName) {
 						fmt.Println(ui.Info("Configuration changed, reloading..."))
-						newCfg, root, err := config.LoadWithProjectRoot()
+						newCfg, root, err := w.reloadConfig()
 						if err != nil {
 							fmt.Println(ui.Error(fmt.Sprintf("Failed to reload config: %v", err)))
 							return
@@ -187,7 +222,7 @@ func (w *Watcher) Watch(ctx context.Context) error {
 							return
 						}
 						w.config = newCfg
-						w.builder = builder.New(newCfg)
+						w.replaceBuilder(newCfg)
 						newRestarter, restarterErr := newRestarter(newCfg)
 						if restarterErr != nil {
 							fmt.Println(ui.Error(fmt.Sprintf("Failed to configure restart mode: %v", restarterErr)))
@@ -201,6 +236,9 @@ func (w *Watcher) Watch(ctx context.Context) error {
 
 						// Re-add all paths (fsnotify handles duplicates)
 						w.registerPaths()
+						for _, configPath := range w.configFiles() {
+							_ = w.watcher.Add(configPath)
+						}
 
 						fmt.Println(ui.Info("Config reloaded, triggering full build..."))
 						if err := w.builder.BuildWithOutputContext(ctx, builder.OutputModeAuto); err != nil {
@@ -260,11 +298,13 @@ func (w *Watcher) Watch(ctx context.Context) error {
 
 					if err != nil {
 						fmt.Println(ui.Error(fmt.Sprintf("Build failed: %v", err)))
-						delete(w.debounceTimers, fileName)
-						return
+						if errors.Is(err, context.Canceled) {
+							delete(w.debounceTimers, fileName)
+							return
+						}
 					}
 
-					// Notify framework for hot reload
+					// Notify framework for hot reload of the resources that built
 					w.notifyFramework(results)
 
 					// Clean up timer reference
@@ -282,11 +322,11 @@ func (w *Watcher) Watch(ctx context.Context) error {
 					w.watcher.Add(event.Name)
 
 					// Re-collect tasks to include new resource if it matches globs
-					newCfg, root, _ := config.LoadWithProjectRoot()
+					newCfg, root, _ := w.reloadConfig()
 					if newCfg != nil {
 						_ = os.Chdir(root)
 						w.config = newCfg
-						w.builder = builder.New(newCfg)
+						w.replaceBuilder(newCfg)
 						allTasks = w.builder.CollectTasks()
 					}
 				}
@@ -328,6 +368,7 @@ func (w *Watcher) registerPaths() {
 	// 3. Watch existing resource directories recursively (entire resource path, not just src)
 	paths := append([]string{}, w.config.GetResourcePaths()...)
 	paths = append(paths, w.config.GetStandalonePaths()...)
+	paths = append(paths, w.modulePaths()...)
 	for _, basePath := range paths {
 		// Walk entire resource directory recursively to catch all changes
 		// (fxmanifest.lua, package.json, src/, views/, etc.)
@@ -367,53 +408,52 @@ func (w *Watcher) tasksForChangedFile(all []builder.BuildTask, changedFile strin
 	if w.shouldIgnorePath(changedFile) {
 		return nil
 	}
-
-	changedAbs, err := filepath.Abs(changedFile)
-	if err != nil {
-		changedAbs = changedFile
-	}
-
-	// Find best matching task (longest path prefix)
-	bestIdx := -1
-	bestLen := -1
-	for i, t := range all {
-		taskAbs, err := filepath.Abs(t.Path)
-		if err != nil {
-			taskAbs = t.Path
-		}
-
-		// Normalize path separators for Windows
-		taskAbs = filepath.Clean(taskAbs)
-		changedAbs = filepath.Clean(changedAbs)
-
-		if strings.HasPrefix(changedAbs, taskAbs+string(os.PathSeparator)) || changedAbs == taskAbs {
-			if len(taskAbs) > bestLen {
-				bestLen = len(taskAbs)
-				bestIdx = i
-			}
+	for _, module := range w.modulePaths() {
+		if isPathWithin(changedFile, module) {
+			// Resources may have started or stopped importing the module
+			// since the tasks were collected.
+			all = w.builder.CollectTasks()
+			break
 		}
 	}
+	return builder.TasksForChangedFile(all, changedFile)
+}
 
-	if bestIdx == -1 {
+// modulePaths returns the directories of the shared modules.
+func (w *Watcher) modulePaths() []string {
+	modules, err := w.config.SharedModules()
+	if err != nil {
 		return nil
 	}
+	paths := make([]string, 0, len(modules))
+	for _, module := range modules {
+		paths = append(paths, module.Path)
+	}
+	return paths
+}
 
-	best := all[bestIdx]
-	base := strings.Split(best.ResourceName, "/")[0]
-
-	// If the change is in a views task, rebuild only the views task.
-	if best.Type == builder.TypeViews || strings.HasSuffix(best.ResourceName, "/ui") {
-		return []builder.BuildTask{best}
+// configFiles returns opencore.config.ts and the preset files it extends
+// from the project. Presets installed as packages are not watched.
+func (w *Watcher) configFiles() []string {
+	files := []string{"opencore.config.ts"}
+	for _, preset := range w.config.Presets() {
+		if !w.shouldIgnorePath(preset.File) {
+			files = append(files, preset.File)
+		}
 	}
+	return files
+}
 
-	// Otherwise rebuild all tasks belonging to the base resource (e.g., resource + its views).
-	var affected []builder.BuildTask
-	for _, t := range all {
-		if strings.Split(t.ResourceName, "/")[0] == base {
-			affected = append(affected, t)
+func (w *Watcher) isConfigFile(path string) bool {
+	if filepath.Base(path) == "opencore.config.ts" || config.IsResourceFile(path) {
+		return true
+	}
+	for _, preset := range w.config.Presets() {
+		if filepath.Clean(path) == filepath.Clean(preset.File) {
+			return true
 		}
 	}
-	return affected
+	return false
 }
 
 func (w *Watcher) shouldIgnorePath(path string) bool {
@@ -481,17 +521,22 @@ func (w *Watcher) Close() error {
 // notifyFramework restarts affected resources or the managed process.
 func (w *Watcher) notifyFramework(results []builder.BuildResult) {
 	// Find unique resources that were successfully built
-	uniqueResources := make(map[string]struct{})
+	uniqueResources := make(map[string]bool)
 	for _, r := range results {
-		if r.Success {
-			// Get base resource name (e.g., "core" instead of "core/ui")
-			baseName := strings.Split(r.Task.ResourceName, "/")[0]
-			uniqueResources[baseName] = struct{}{}
+		// Get base resource name (e.g., "core" instead of "core/ui")
+		baseName := strings.Split(r.Task.ResourceName, "/")[0]
+		if built, seen := uniqueResources[baseName]; !seen || built {
+			uniqueResources[baseName] = r.Success
 		}
 	}
 	resources := make([]string, 0, len(uniqueResources))
-	for resourceName := range uniqueResources {
-		resources = append(resources, resourceName)
+	for resourceName, built := range uniqueResources {
+		if built {
+			resources = append(resources, resourceName)
+		}
+	}
+	if len(resources) == 0 {
+		return
 	}
 	if err := w.restarter.Restart(resources); err != nil {
 		fmt.Println(ui.Error(fmt.Sprintf("Restart failed: %v", err)))
@@ -642,7 +687,7 @@ func (w *Watcher) displayLog(log LogMessage) {
 		fmt.Printf("  %s: %s\n", errStyle.Render(log.Error.Name), errStyle.Render(log.Error.Message))
 		if log.Error.Stack != "" {
 			stackStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4B5563"))
-			fmt.Println(stackStyle.Render(log.Error.Stack))
+			fmt.Println(stackStyle.Render(w.stacks.Remap(log.Error.Stack)))
 		}
 	}
 }
@@ -56,8 +56,11 @@ func main() {
 	rootCmd.AddCommand(commands.NewInitCommand())
 	rootCmd.AddCommand(commands.NewCreateCommand())
 	rootCmd.AddCommand(commands.NewBuildCommand())
+	rootCmd.AddCommand(commands.NewAnalyzeCommand())
 	rootCmd.AddCommand(commands.NewDevCommand())
+	rootCmd.AddCommand(commands.NewDeployCommand())
 	rootCmd.AddCommand(commands.NewDoctorCommand())
+	rootCmd.AddCommand(commands.NewConfigCommand())
 	rootCmd.AddCommand(commands.NewCloneCommand())
 	rootCmd.AddCommand(commands.NewAdapterCommand())
 	rootCmd.AddCommand(commands.NewUpdateCommand())